@@ -0,0 +1,39 @@
+// SPDX-License-Identifier: Unlicense OR MIT
+
+package app
+
+import "sync"
+
+var atExitFuncs struct {
+	mu    sync.Mutex
+	funcs []func()
+}
+
+// AtExit registers f to run when the platform backend gets a chance to
+// run code before the process disappears, such as on application
+// termination on iOS and tvOS where there is no guarantee that deferred
+// calls or os.Exit hooks ever run. It is intended for flushing state that
+// must survive the process, such as profiles or logs.
+//
+// Registered functions run in last-in-first-out order. AtExit is safe to
+// call from any goroutine.
+func AtExit(f func()) {
+	atExitFuncs.mu.Lock()
+	defer atExitFuncs.mu.Unlock()
+	atExitFuncs.funcs = append(atExitFuncs.funcs, f)
+}
+
+// runAtExit runs the functions registered with AtExit, most recently
+// registered first, recovering and discarding any panic so that one
+// faulty hook cannot stop the others from running.
+func runAtExit() {
+	atExitFuncs.mu.Lock()
+	funcs := append([]func(){}, atExitFuncs.funcs...)
+	atExitFuncs.mu.Unlock()
+	for i := len(funcs) - 1; i >= 0; i-- {
+		func() {
+			defer func() { recover() }()
+			funcs[i]()
+		}()
+	}
+}
@@ -0,0 +1,82 @@
+// SPDX-License-Identifier: Unlicense OR MIT
+
+package app
+
+import (
+	"bytes"
+	"container/list"
+	"encoding/base64"
+	"fmt"
+	"image/png"
+
+	"gioui.org/io/pointer"
+)
+
+// maxCustomCursorCSS bounds customCursorCSS: enough for an application
+// switching between a handful of custom cursor images without keeping
+// every base64-encoded PNG it has ever registered in memory.
+const maxCustomCursorCSS = 64
+
+// customCursorCSS is an LRU cache from a pointer.Cursor registered with
+// pointer.NewCursor or pointer.NewAnimatedCursor to the CSS cursor value
+// that displays it, keyed by the Cursor's internal id so that setting the
+// same custom cursor every frame doesn't re-encode its image to a PNG
+// data URL each time.
+var customCursorCSS = newCustomCursorCache(maxCustomCursorCSS)
+
+type customCursorCache struct {
+	limit   int
+	entries map[pointer.Cursor]*list.Element
+	order   *list.List // front is most recently used
+}
+
+type customCursorEntry struct {
+	cursor pointer.Cursor
+	css    string
+}
+
+func newCustomCursorCache(limit int) *customCursorCache {
+	return &customCursorCache{
+		limit:   limit,
+		entries: make(map[pointer.Cursor]*list.Element),
+		order:   list.New(),
+	}
+}
+
+func (c *customCursorCache) get(cursor pointer.Cursor) (string, bool) {
+	e, ok := c.entries[cursor]
+	if !ok {
+		return "", false
+	}
+	c.order.MoveToFront(e)
+	return e.Value.(*customCursorEntry).css, true
+}
+
+func (c *customCursorCache) put(cursor pointer.Cursor, css string) {
+	if e, ok := c.entries[cursor]; ok {
+		e.Value.(*customCursorEntry).css = css
+		c.order.MoveToFront(e)
+		return
+	}
+	e := c.order.PushFront(&customCursorEntry{cursor: cursor, css: css})
+	c.entries[cursor] = e
+	for len(c.entries) > c.limit {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*customCursorEntry).cursor)
+	}
+}
+
+// cursorCSS returns the CSS cursor value for a custom cursor frame,
+// encoding its image as a PNG data URL in the form the CSS cursor
+// property expects: url(...) hotspotX hotspotY, and a generic fallback
+// keyword. It reports false if the image couldn't be encoded, in which
+// case the caller should fall back to CursorDefault.
+func cursorCSS(f pointer.CursorFrame) (string, bool) {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, f.Img); err != nil {
+		return "", false
+	}
+	enc := base64.StdEncoding.EncodeToString(buf.Bytes())
+	return fmt.Sprintf("url(data:image/png;base64,%s) %d %d, auto", enc, f.Hotspot.X, f.Hotspot.Y), true
+}
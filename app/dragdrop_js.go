@@ -0,0 +1,85 @@
+// SPDX-License-Identifier: Unlicense OR MIT
+
+package app
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"syscall/js"
+
+	"gioui.org/io/transfer"
+)
+
+// dropEvent handles a "drop" on w.cnv, delivering each dropped file as a
+// transfer.DataEvent backed by the File's arrayBuffer() promise, and any
+// offered "text/uri-list" or "text/plain" payload as a DataEvent of the
+// same MIME type. This mirrors ReadClipboard's delivery of pasted content:
+// a drop has no in-page transfer.SourceFilter to negotiate with, so, like
+// a paste, it is simply handed to whichever target has registered a
+// matching transfer.TargetFilter.
+func (w *window) dropEvent(e js.Value) {
+	e.Call("preventDefault")
+	data := e.Get("dataTransfer")
+	if !data.Truthy() {
+		return
+	}
+	for _, mime := range []string{"text/uri-list", "text/plain"} {
+		if s := data.Call("getData", mime).String(); s != "" {
+			w.w.Event(transfer.DataEvent{
+				Type: mime,
+				Open: func() io.ReadCloser {
+					return io.NopCloser(strings.NewReader(s))
+				},
+			})
+		}
+	}
+	files := data.Get("files")
+	for i, n := 0, files.Length(); i < n; i++ {
+		file := files.Index(i)
+		mime := file.Get("type").String()
+		if mime == "" {
+			mime = "application/octet-stream"
+		}
+		w.w.Event(transfer.DataEvent{
+			Type: mime,
+			Open: func() io.ReadCloser {
+				return newFileReader(file)
+			},
+		})
+	}
+}
+
+// fileReader adapts a JS File's arrayBuffer() promise, the only way to
+// read file content from a "drop" DataTransfer, to an io.ReadCloser: the
+// whole file is read into memory once the promise resolves, and Read
+// blocks on a channel until it does.
+type fileReader struct {
+	file js.Value
+	buf  *bytes.Reader
+	done chan []byte
+}
+
+func newFileReader(file js.Value) *fileReader {
+	r := &fileReader{file: file, done: make(chan []byte, 1)}
+	var cb js.Func
+	cb = js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		buf := args[0]
+		data := make([]byte, js.Global().Get("Uint8Array").New(buf).Get("length").Int())
+		js.CopyBytesToGo(data, js.Global().Get("Uint8Array").New(buf))
+		r.done <- data
+		cb.Release()
+		return nil
+	})
+	file.Call("arrayBuffer").Call("then", cb)
+	return r
+}
+
+func (r *fileReader) Read(p []byte) (int, error) {
+	if r.buf == nil {
+		r.buf = bytes.NewReader(<-r.done)
+	}
+	return r.buf.Read(p)
+}
+
+func (r *fileReader) Close() error { return nil }
@@ -0,0 +1,99 @@
+// SPDX-License-Identifier: Unlicense OR MIT
+
+package app
+
+import (
+	"syscall/js"
+	"time"
+
+	"gioui.org/io/gamepad"
+)
+
+// gamepadPollInterval bounds how long a pressed button or moved stick can
+// go unnoticed while the window isn't animating and so isn't already
+// polling every frame through animCallback.
+const gamepadPollInterval = 100 * time.Millisecond
+
+// gamepadState is the last state diffGamepads saw for one entry of
+// navigator.getGamepads(), keyed by that entry's own "index" property
+// (which the browser may reuse after a disconnect, unlike id).
+type gamepadState struct {
+	id      gamepad.ID
+	buttons []bool
+	axes    []float32
+}
+
+// pollGamepads diffs navigator.getGamepads() against the state recorded on
+// the previous call, delivering Connect, Disconnect, Axis, ButtonPress and
+// ButtonRelease events for whatever changed. It is called once per
+// animation frame from animCallback, and on a low-rate ticker the rest of
+// the time, so that a button press still wakes up an application that
+// isn't otherwise animating.
+func (w *window) pollGamepads() {
+	navigator := js.Global().Get("navigator")
+	getGamepads := navigator.Get("getGamepads")
+	if !getGamepads.Truthy() {
+		return
+	}
+	pads := navigator.Call("getGamepads")
+	seen := make(map[int]bool, pads.Length())
+	for i, n := 0, pads.Length(); i < n; i++ {
+		pad := pads.Index(i)
+		if !pad.Truthy() || !pad.Get("connected").Bool() {
+			continue
+		}
+		idx := pad.Get("index").Int()
+		seen[idx] = true
+		st, ok := w.gamepads[idx]
+		if !ok {
+			if w.gamepads == nil {
+				w.gamepads = make(map[int]*gamepadState)
+			}
+			w.nextGamepadID++
+			st = &gamepadState{id: w.nextGamepadID}
+			w.gamepads[idx] = st
+			w.w.Event(gamepad.Event{ID: st.id, Kind: gamepad.Connect, Name: pad.Get("id").String()})
+		}
+		st.diffButtons(w, pad.Get("buttons"))
+		st.diffAxes(w, pad.Get("axes"))
+	}
+	for idx, st := range w.gamepads {
+		if seen[idx] {
+			continue
+		}
+		w.w.Event(gamepad.Event{ID: st.id, Kind: gamepad.Disconnect})
+		delete(w.gamepads, idx)
+	}
+}
+
+func (st *gamepadState) diffButtons(w *window, buttons js.Value) {
+	for n := buttons.Length(); len(st.buttons) < n; {
+		st.buttons = append(st.buttons, false)
+	}
+	for i, n := 0, buttons.Length(); i < n; i++ {
+		pressed := buttons.Index(i).Get("pressed").Bool()
+		if pressed == st.buttons[i] {
+			continue
+		}
+		st.buttons[i] = pressed
+		kind := gamepad.ButtonRelease
+		if pressed {
+			kind = gamepad.ButtonPress
+		}
+		w.w.Event(gamepad.Event{ID: st.id, Kind: kind, Index: i})
+	}
+}
+
+func (st *gamepadState) diffAxes(w *window, axes js.Value) {
+	for n := axes.Length(); len(st.axes) < n; {
+		st.axes = append(st.axes, 0)
+	}
+	for i, n := 0, axes.Length(); i < n; i++ {
+		v := float32(axes.Index(i).Float())
+		if v == st.axes[i] {
+			continue
+		}
+		st.axes[i] = v
+		w.w.Event(gamepad.Event{ID: st.id, Kind: gamepad.Axis, Index: i, Value: v})
+	}
+}
@@ -0,0 +1,151 @@
+// SPDX-License-Identifier: Unlicense OR MIT
+
+package app
+
+import (
+	"errors"
+	"image"
+	"time"
+
+	headlessgpu "gioui.org/gpu/headless"
+	"gioui.org/io/event"
+	"gioui.org/io/key"
+	"gioui.org/io/pointer"
+	"gioui.org/io/system"
+	"gioui.org/op"
+	"gioui.org/unit"
+)
+
+// Headless configures the Window to render offscreen through
+// [gioui.org/gpu/headless] instead of creating a platform window. It is
+// intended for golden-image widget tests and server-side rendering,
+// where there is no display and no [app.Main] event loop. width and
+// height are the size of the offscreen target, in pixels. Decorations
+// default to off, since a headless window has no close/minimize/maximize
+// controls to decorate.
+func Headless(width, height int) Option {
+	return func(_ unit.Metric, cnf *Config) {
+		cnf.headless = true
+		cnf.Size = image.Pt(width, height)
+		cnf.Decorated = false
+	}
+}
+
+// Frame delivers a [FrameEvent] for time t to a [Headless] window,
+// standing in for the platform clock that drives a real window's
+// redraws. It lets tests script animations deterministically. Frame
+// panics if w was not created with the Headless option.
+func (w *Window) Frame(t time.Time) {
+	if _, ok := w.driver.(*headlessDriver); !ok {
+		panic("app: Frame requires a Headless window")
+	}
+	w.deliverHeadlessFrame(t)
+}
+
+// Snapshot renders the most recent frame submitted to a [Headless]
+// window's [FrameEvent.Frame] and returns it as an image. Snapshot
+// panics if w was not created with the Headless option.
+func (w *Window) Snapshot() (*image.RGBA, error) {
+	hd, ok := w.driver.(*headlessDriver)
+	if !ok {
+		panic("app: Snapshot requires a Headless window")
+	}
+	if hd.err != nil {
+		return nil, hd.err
+	}
+	img := image.NewRGBA(image.Rectangle{Max: hd.size})
+	if err := hd.gpu.Screenshot(img); err != nil {
+		return nil, err
+	}
+	return img, nil
+}
+
+func (w *Window) initHeadless(size image.Point) {
+	gpuWin, err := headlessgpu.NewWindow(size.X, size.Y)
+	if err != nil {
+		w.basic = failedDriver{err: err}
+		return
+	}
+	d := &headlessDriver{win: &callbacks{w}, size: size}
+	d.gpu = gpuWin
+	(&callbacks{w}).SetDriver(d)
+	w.deliverHeadlessFrame(time.Time{})
+}
+
+func (w *Window) deliverHeadlessFrame(t time.Time) {
+	hd := w.driver.(*headlessDriver)
+	w.processEvent(frameEvent{
+		FrameEvent: FrameEvent{
+			Now:    t,
+			Metric: unit.Metric{PxPerDp: 1, PxPerSp: 1},
+			Size:   hd.size,
+		},
+		Sync: true,
+	})
+}
+
+// headlessDriver implements driver by rendering frames through
+// [gioui.org/gpu/headless] instead of a platform window.
+type headlessDriver struct {
+	win  *callbacks
+	size image.Point
+	gpu  *headlessgpu.Window
+	err  error
+}
+
+func (d *headlessDriver) SetAnimating(anim bool)                  {}
+func (d *headlessDriver) ShowTextInput(show bool)                 {}
+func (d *headlessDriver) SetInputHint(mode key.InputHint)         {}
+func (d *headlessDriver) ReadClipboard()                          {}
+func (d *headlessDriver) WriteClipboard(mime string, s []byte)    {}
+func (d *headlessDriver) Configure(opts []Option)                 {}
+func (d *headlessDriver) SetCursor(cursor pointer.Cursor)         {}
+func (d *headlessDriver) SetCloseIntercepted(intercept bool)      {}
+func (d *headlessDriver) Perform(system.Action)                   {}
+func (d *headlessDriver) EditorStateChanged(old, new editorState) {}
+func (d *headlessDriver) ProcessEvent(e event.Event)              {}
+
+func (d *headlessDriver) NewContext() (context, error) {
+	return nil, errors.New("app: Headless windows render through gpu/headless, not a platform context")
+}
+
+// Run f immediately: a headless window has no separate window thread.
+func (d *headlessDriver) Run(f func()) {
+	f()
+}
+
+// Frame renders frame offscreen and feeds it back through the normal
+// frame pipeline so state such as hover, focus and semantics stay
+// consistent with a platform window.
+func (d *headlessDriver) Frame(frame *op.Ops) {
+	if err := d.gpu.Frame(frame); err != nil {
+		d.err = err
+	}
+	d.win.ProcessFrame(frame, nil)
+}
+
+// Invalidate delivers another frame immediately, using the wall clock.
+// Use [Window.Frame] instead for deterministic timing in tests.
+func (d *headlessDriver) Invalidate() {
+	d.win.w.deliverHeadlessFrame(time.Now())
+}
+
+func (d *headlessDriver) Event() event.Event {
+	if e, ok := d.win.nextEvent(); ok {
+		return e
+	}
+	return wakeupEvent{}
+}
+
+// failedDriver reports a Headless window's construction error from
+// Window.Event, so tests see the failure instead of a window that never
+// produces a frame.
+type failedDriver struct {
+	err error
+}
+
+func (f failedDriver) Event() event.Event {
+	return DestroyEvent{Err: f.err}
+}
+
+func (f failedDriver) Invalidate() {}
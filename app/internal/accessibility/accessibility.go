@@ -0,0 +1,170 @@
+// SPDX-License-Identifier: Unlicense OR MIT
+
+// Package accessibility converts the semantic tree exposed by
+// [gioui.org/io/input] into a platform-neutral form, modeled after the
+// tree/adapter split used by AccessKit, for an OS-specific accessibility
+// bridge (AT-SPI2 on Linux, UI Automation on Windows, NSAccessibility on
+// macOS) to expose to assistive technology.
+//
+// The conversion and the [Adapter] interface live here so that the
+// platform-specific bridges can be swapped in with a build tag without
+// touching the [gioui.org/app] package, which only depends on the
+// [Source] and [Adapter] interfaces.
+//
+// As of this package, the per-OS adapters in atspi_linux.go, uia_windows.go
+// and nsaccessibility_macos.go only retain the latest [Tree]: none of them
+// registers with its OS accessibility API (no D-Bus object on the session
+// bus, no COM IRawElementProviderSimple answering WM_GETOBJECT, no
+// NSAccessibilityElement conformance), so Orca, NVDA and VoiceOver do not
+// see Gio's semantic tree yet. That registration, which needs the window
+// handle owned by [gioui.org/app]'s platform drivers, is unimplemented.
+package accessibility
+
+import (
+	"gioui.org/f32"
+	"gioui.org/io/input"
+)
+
+// Role is a coarse, platform-neutral classification of a [Node], used by
+// the OS bridges to pick the matching AT-SPI role, UIA control pattern or
+// NSAccessibility role.
+type Role uint8
+
+const (
+	// RoleGeneric is a container with no particular semantics.
+	RoleGeneric Role = iota
+	// RoleButton is a node with a click gesture and no children of its own.
+	RoleButton
+	// RoleLabel is a leaf node with a description or label and no gestures.
+	RoleLabel
+)
+
+// Node is the platform-neutral equivalent of an [input.SemanticNode]: a
+// flattened, by-ID view of the tree that the OS bridges can walk without
+// reaching back into the [input.Router].
+type Node struct {
+	Role        Role
+	Label       string
+	Description string
+	Selected    bool
+	Disabled    bool
+	Bounds      Bounds
+	Children    []input.SemanticID
+}
+
+// Bounds is the on-screen rectangle of a [Node], in the same coordinate
+// space as [input.SemanticDesc.Bounds].
+type Bounds struct {
+	Min, Max Point
+}
+
+// Point is a 2D integer coordinate.
+type Point struct {
+	X, Y int
+}
+
+// Tree is a platform-neutral snapshot of the semantic tree, suitable for
+// handing to an OS accessibility bridge.
+type Tree struct {
+	Root  input.SemanticID
+	Nodes map[input.SemanticID]Node
+}
+
+// Source is the subset of a Gio window needed to build and refresh a
+// [Tree]. The [gioui.org/app] callbacks type already implements Source.
+type Source interface {
+	// SemanticRoot returns the ID of the semantics root node.
+	SemanticRoot() input.SemanticID
+	// LookupSemantic looks up a semantic node from an ID.
+	LookupSemantic(id input.SemanticID) (input.SemanticNode, bool)
+	// SemanticAt returns the semantic node under pos, if any.
+	SemanticAt(pos f32.Point) (input.SemanticID, bool)
+}
+
+// Adapter bridges a platform-neutral [Tree] to an OS-specific
+// accessibility API. Implementations are registered per OS by replacing
+// [New] from an init function behind a build tag; see atspi_linux.go,
+// uia_windows.go and nsaccessibility_macos.go. None of the current
+// implementations registers with its OS API yet; see the package doc.
+type Adapter interface {
+	// TreeUpdated is called whenever the semantic tree changes. diffs
+	// lists the IDs of nodes that were added, removed or changed, so the
+	// adapter can push targeted tree-update events to the assistive
+	// technology instead of requiring it to poll.
+	TreeUpdated(tree Tree, diffs []input.SemanticID)
+	// HitTest answers a platform hit-test query (AT-SPI
+	// getAccessibleAtPoint, UIA ElementProviderFromPoint, NSAccessibility
+	// accessibilityHitTest) by forwarding to the window.
+	HitTest(pos f32.Point) (input.SemanticID, bool)
+	// Release releases any OS resources held by the adapter.
+	Release()
+}
+
+// New returns the accessibility adapter for the current OS. It defaults
+// to a no-op adapter; platforms with a real bridge override it from an
+// init function.
+var New = newNoopAdapter
+
+// BuildTree flattens the semantic tree rooted at src.SemanticRoot into a
+// [Tree] keyed by [input.SemanticID], the form the OS bridges operate on.
+func BuildTree(src Source) Tree {
+	t := Tree{
+		Root:  src.SemanticRoot(),
+		Nodes: map[input.SemanticID]Node{},
+	}
+	addNode(t.Nodes, src, t.Root)
+	return t
+}
+
+func addNode(nodes map[input.SemanticID]Node, src Source, id input.SemanticID) {
+	if _, visited := nodes[id]; visited {
+		return
+	}
+	n, ok := src.LookupSemantic(id)
+	if !ok {
+		return
+	}
+	children := make([]input.SemanticID, len(n.Children))
+	for i, ch := range n.Children {
+		children[i] = ch.ID
+	}
+	nodes[id] = Node{
+		Role:        roleFor(n.Desc, len(children) > 0),
+		Label:       n.Desc.Label,
+		Description: n.Desc.Description,
+		Selected:    n.Desc.Selected,
+		Disabled:    n.Desc.Disabled,
+		Bounds:      boundsFor(n.Desc),
+		Children:    children,
+	}
+	for _, ch := range children {
+		addNode(nodes, src, ch)
+	}
+}
+
+func roleFor(desc input.SemanticDesc, hasChildren bool) Role {
+	switch {
+	case desc.Gestures&input.ClickGesture != 0:
+		return RoleButton
+	case hasChildren:
+		return RoleGeneric
+	default:
+		return RoleLabel
+	}
+}
+
+func boundsFor(desc input.SemanticDesc) Bounds {
+	b := desc.Bounds
+	return Bounds{
+		Min: Point{X: b.Min.X, Y: b.Min.Y},
+		Max: Point{X: b.Max.X, Y: b.Max.Y},
+	}
+}
+
+type noopAdapter struct{}
+
+func newNoopAdapter(Source) Adapter { return noopAdapter{} }
+
+func (noopAdapter) TreeUpdated(Tree, []input.SemanticID)       {}
+func (noopAdapter) HitTest(f32.Point) (input.SemanticID, bool) { return 0, false }
+func (noopAdapter) Release()                                   {}
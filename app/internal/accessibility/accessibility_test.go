@@ -0,0 +1,127 @@
+// SPDX-License-Identifier: Unlicense OR MIT
+
+package accessibility
+
+import (
+	"image"
+	"testing"
+
+	"gioui.org/f32"
+	"gioui.org/io/input"
+)
+
+// fakeSource is a [Source] backed by a fixed set of nodes, for testing
+// [BuildTree] without a real [input.Router].
+type fakeSource struct {
+	root  input.SemanticID
+	nodes map[input.SemanticID]input.SemanticNode
+}
+
+func (f fakeSource) SemanticRoot() input.SemanticID { return f.root }
+
+func (f fakeSource) LookupSemantic(id input.SemanticID) (input.SemanticNode, bool) {
+	n, ok := f.nodes[id]
+	return n, ok
+}
+
+func (f fakeSource) SemanticAt(pos f32.Point) (input.SemanticID, bool) {
+	return 0, false
+}
+
+func TestBuildTree(t *testing.T) {
+	button := input.SemanticNode{
+		ID:   2,
+		Desc: input.SemanticDesc{Label: "OK", Gestures: input.ClickGesture, Bounds: image.Rect(0, 0, 10, 10)},
+	}
+	label := input.SemanticNode{
+		ID:   3,
+		Desc: input.SemanticDesc{Label: "hello"},
+	}
+	root := input.SemanticNode{
+		ID:       1,
+		Children: []input.SemanticNode{button, label},
+		Desc:     input.SemanticDesc{},
+	}
+	src := fakeSource{
+		root: root.ID,
+		nodes: map[input.SemanticID]input.SemanticNode{
+			root.ID:   root,
+			button.ID: button,
+			label.ID:  label,
+		},
+	}
+
+	tree := BuildTree(src)
+	if tree.Root != root.ID {
+		t.Fatalf("Root is %d, want %d", tree.Root, root.ID)
+	}
+	if len(tree.Nodes) != 3 {
+		t.Fatalf("got %d nodes, want 3", len(tree.Nodes))
+	}
+
+	rootNode, ok := tree.Nodes[root.ID]
+	if !ok {
+		t.Fatal("root node missing from Tree")
+	}
+	if rootNode.Role != RoleGeneric {
+		t.Errorf("root Role is %v, want RoleGeneric", rootNode.Role)
+	}
+	if got, want := rootNode.Children, []input.SemanticID{button.ID, label.ID}; !equalIDs(got, want) {
+		t.Errorf("root Children is %v, want %v", got, want)
+	}
+
+	buttonNode, ok := tree.Nodes[button.ID]
+	if !ok {
+		t.Fatal("button node missing from Tree")
+	}
+	if buttonNode.Role != RoleButton {
+		t.Errorf("button Role is %v, want RoleButton", buttonNode.Role)
+	}
+	if buttonNode.Label != "OK" {
+		t.Errorf("button Label is %q, want %q", buttonNode.Label, "OK")
+	}
+	wantBounds := Bounds{Min: Point{0, 0}, Max: Point{10, 10}}
+	if buttonNode.Bounds != wantBounds {
+		t.Errorf("button Bounds is %+v, want %+v", buttonNode.Bounds, wantBounds)
+	}
+
+	labelNode, ok := tree.Nodes[label.ID]
+	if !ok {
+		t.Fatal("label node missing from Tree")
+	}
+	if labelNode.Role != RoleLabel {
+		t.Errorf("label Role is %v, want RoleLabel", labelNode.Role)
+	}
+}
+
+func TestBuildTreeIgnoresUnknownRoot(t *testing.T) {
+	src := fakeSource{root: 42, nodes: map[input.SemanticID]input.SemanticNode{}}
+	tree := BuildTree(src)
+	if len(tree.Nodes) != 0 {
+		t.Errorf("got %d nodes for a missing root, want 0", len(tree.Nodes))
+	}
+}
+
+func TestNoopAdapter(t *testing.T) {
+	src := fakeSource{root: 1, nodes: map[input.SemanticID]input.SemanticNode{
+		1: {ID: 1},
+	}}
+	a := newNoopAdapter(src)
+	a.TreeUpdated(BuildTree(src), nil)
+	if id, ok := a.HitTest(f32.Point{}); ok || id != 0 {
+		t.Errorf("HitTest on noopAdapter returned (%d, %v), want (0, false)", id, ok)
+	}
+	a.Release()
+}
+
+func equalIDs(a, b []input.SemanticID) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
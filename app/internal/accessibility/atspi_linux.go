@@ -0,0 +1,53 @@
+// SPDX-License-Identifier: Unlicense OR MIT
+
+//go:build linux && !android
+// +build linux,!android
+
+package accessibility
+
+import (
+	"sync"
+
+	"gioui.org/f32"
+	"gioui.org/io/input"
+)
+
+func init() {
+	New = newATSPIAdapter
+}
+
+// atspiAdapter retains the latest semantic [Tree] for a window so it is
+// available once AT-SPI2 registration is implemented. It does not yet
+// register an accessible object tree on the D-Bus session bus: Orca sees
+// nothing from it today. Registration will need the window handle owned
+// by [gioui.org/app]'s X11 and Wayland drivers, which don't expose one
+// for this purpose yet.
+type atspiAdapter struct {
+	src Source
+
+	mu   sync.Mutex
+	tree Tree
+}
+
+func newATSPIAdapter(src Source) Adapter {
+	return &atspiAdapter{src: src}
+}
+
+func (a *atspiAdapter) TreeUpdated(tree Tree, diffs []input.SemanticID) {
+	a.mu.Lock()
+	a.tree = tree
+	a.mu.Unlock()
+	// TODO: register an org.a11y.atspi.Accessible object on the session
+	// bus and push diffs as org.a11y.atspi.Event.Object signals. Until
+	// then, nothing here is reachable from Orca at all.
+}
+
+func (a *atspiAdapter) HitTest(pos f32.Point) (input.SemanticID, bool) {
+	return a.src.SemanticAt(pos)
+}
+
+func (a *atspiAdapter) Release() {
+	a.mu.Lock()
+	a.tree = Tree{}
+	a.mu.Unlock()
+}
@@ -0,0 +1,54 @@
+// SPDX-License-Identifier: Unlicense OR MIT
+
+//go:build darwin && !ios
+// +build darwin,!ios
+
+package accessibility
+
+import (
+	"sync"
+
+	"gioui.org/f32"
+	"gioui.org/io/input"
+)
+
+func init() {
+	New = newNSAccessibilityAdapter
+}
+
+// nsAccessibilityAdapter retains the latest semantic [Tree] for a window
+// so it is available once NSAccessibility registration is implemented.
+// It does not yet conform to NSAccessibilityElement or answer
+// accessibilityChildren/accessibilityHitTest: VoiceOver sees nothing
+// from it today. That will need the NSView that AppKit sends those
+// messages to, which [gioui.org/app]'s macOS driver doesn't expose for
+// this purpose yet.
+type nsAccessibilityAdapter struct {
+	src Source
+
+	mu   sync.Mutex
+	tree Tree
+}
+
+func newNSAccessibilityAdapter(src Source) Adapter {
+	return &nsAccessibilityAdapter{src: src}
+}
+
+func (a *nsAccessibilityAdapter) TreeUpdated(tree Tree, diffs []input.SemanticID) {
+	a.mu.Lock()
+	a.tree = tree
+	a.mu.Unlock()
+	// TODO: conform to NSAccessibilityElement and call
+	// NSAccessibilityPostNotification for diffs. Until then, nothing here
+	// is reachable from VoiceOver at all.
+}
+
+func (a *nsAccessibilityAdapter) HitTest(pos f32.Point) (input.SemanticID, bool) {
+	return a.src.SemanticAt(pos)
+}
+
+func (a *nsAccessibilityAdapter) Release() {
+	a.mu.Lock()
+	a.tree = Tree{}
+	a.mu.Unlock()
+}
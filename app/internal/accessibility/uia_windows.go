@@ -0,0 +1,50 @@
+// SPDX-License-Identifier: Unlicense OR MIT
+
+package accessibility
+
+import (
+	"sync"
+
+	"gioui.org/f32"
+	"gioui.org/io/input"
+)
+
+func init() {
+	New = newUIAAdapter
+}
+
+// uiaAdapter retains the latest semantic [Tree] for a window so it is
+// available once UI Automation registration is implemented. It does not
+// yet implement IRawElementProviderSimple/IRawElementProviderFragment or
+// answer WM_GETOBJECT: NVDA sees nothing from it today. That will need
+// the HWND that UiaReturnRawElementProvider requires, which
+// [gioui.org/app]'s Windows driver doesn't expose for this purpose yet.
+type uiaAdapter struct {
+	src Source
+
+	mu   sync.Mutex
+	tree Tree
+}
+
+func newUIAAdapter(src Source) Adapter {
+	return &uiaAdapter{src: src}
+}
+
+func (a *uiaAdapter) TreeUpdated(tree Tree, diffs []input.SemanticID) {
+	a.mu.Lock()
+	a.tree = tree
+	a.mu.Unlock()
+	// TODO: implement the COM provider interfaces, answer WM_GETOBJECT,
+	// and raise UiaRaiseAutomationEvent/UiaRaiseStructureChangedEvent for
+	// diffs. Until then, nothing here is reachable from NVDA at all.
+}
+
+func (a *uiaAdapter) HitTest(pos f32.Point) (input.SemanticID, bool) {
+	return a.src.SemanticAt(pos)
+}
+
+func (a *uiaAdapter) Release() {
+	a.mu.Lock()
+	a.tree = Tree{}
+	a.mu.Unlock()
+}
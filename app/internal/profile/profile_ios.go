@@ -0,0 +1,128 @@
+// SPDX-License-Identifier: Unlicense OR MIT
+
+//go:build ios && pprof
+// +build ios,pprof
+
+// Package profile enables CPU, heap, block and mutex profiling for Gio
+// programs built by gogio with -target ios or tvos and -tags pprof. It
+// exists so that attaching Instruments or go tool pprof to a Gio iOS
+// build is a zero-code-change flow: importing gioui.org/app is enough,
+// and this package takes care of the rest through app.AtExit.
+package profile
+
+/*
+#cgo CFLAGS: -fmodules -fobjc-arc -x objective-c
+
+#include <Foundation/Foundation.h>
+#include <stdlib.h>
+
+static char *gio_profileDocumentsDir(void) {
+	@autoreleasepool {
+		NSArray<NSString *> *paths = NSSearchPathForDirectoriesInDomains(NSDocumentDirectory, NSUserDomainMask, YES);
+		return strdup(paths.firstObject.UTF8String);
+	}
+}
+*/
+import "C"
+
+import (
+	"os"
+	"os/signal"
+	"path/filepath"
+	"runtime"
+	"runtime/pprof"
+	"sync"
+	"syscall"
+	"unsafe"
+
+	"gioui.org/app"
+)
+
+// cpuProfile and memProfile name the CPU and heap profile files written to
+// the app's Documents directory. gogio's -pprof-cpu and -pprof-mem flags
+// override them with -ldflags -X at link time.
+var (
+	cpuProfile = "cpu.pprof"
+	memProfile = "mem.pprof"
+)
+
+var (
+	mu      sync.Mutex
+	cpuFile *os.File
+)
+
+func init() {
+	runtime.SetBlockProfileRate(1)
+	runtime.SetMutexProfileFraction(1)
+	dir := documentsDir()
+	startCPUProfile(dir)
+	app.AtExit(func() { flush(dir) })
+	// A SIGUSR1 lets a developer attached over lldb or ssh snapshot and
+	// rotate the profiles without killing the app.
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, syscall.SIGUSR1)
+	go func() {
+		for range sigs {
+			flush(dir)
+			startCPUProfile(dir)
+		}
+	}()
+}
+
+func documentsDir() string {
+	cstr := C.gio_profileDocumentsDir()
+	defer C.free(unsafe.Pointer(cstr))
+	return C.GoString(cstr)
+}
+
+func startCPUProfile(dir string) {
+	mu.Lock()
+	defer mu.Unlock()
+	f, err := os.Create(filepath.Join(dir, cpuProfile))
+	if err != nil {
+		return
+	}
+	if err := pprof.StartCPUProfile(f); err != nil {
+		f.Close()
+		return
+	}
+	cpuFile = f
+}
+
+// flush stops the running CPU profile and writes the heap, block and
+// mutex profiles next to it, ready for Instruments or go tool pprof.
+func flush(dir string) {
+	mu.Lock()
+	pprof.StopCPUProfile()
+	if cpuFile != nil {
+		cpuFile.Close()
+		cpuFile = nil
+	}
+	mu.Unlock()
+	runtime.GC()
+	writeProfile(dir, "heap")
+	writeProfile(dir, "block")
+	writeProfile(dir, "mutex")
+}
+
+func writeProfile(dir, name string) {
+	p := pprof.Lookup(name)
+	if p == nil {
+		return
+	}
+	ext := "." + name
+	if name == "heap" {
+		ext = ""
+	}
+	f, err := os.Create(filepath.Join(dir, memProfile+ext))
+	if err != nil {
+		return
+	}
+	defer f.Close()
+	p.WriteTo(f, 0)
+}
+
+//export gio_profileFlush
+func gio_profileFlush() {
+	flush(documentsDir())
+}
@@ -229,8 +229,11 @@ func (h *x11EventHandler) handleEvents() bool {
 				}
 			case C.XkbStateNotify:
 				state := (*C.XkbStateNotifyEvent)(unsafe.Pointer(xev))
-				h.w.xkb.UpdateMask(uint32(state.base_mods), uint32(state.latched_mods), uint32(state.locked_mods),
+				events := h.w.xkb.UpdateMask(uint32(state.base_mods), uint32(state.latched_mods), uint32(state.locked_mods),
 					uint32(state.base_group), uint32(state.latched_group), uint32(state.locked_group))
+				for _, e := range events {
+					w.w.Event(e)
+				}
 			}
 		case C.KeyPress:
 			kevt := (*C.XKeyPressedEvent)(unsafe.Pointer(xev))
@@ -36,6 +36,9 @@ type Context struct {
 	compTable *C.struct_xkb_compose_table
 	compState *C.struct_xkb_compose_state
 	utf8Buf   []byte
+	// group is the most recently reported active layout group, used
+	// to detect group changes in UpdateMask.
+	group C.xkb_layout_index_t
 }
 
 var (
@@ -43,6 +46,9 @@ var (
 	_XKB_MOD_NAME_SHIFT = []byte("Shift\x00")
 	_XKB_MOD_NAME_ALT   = []byte("Mod1\x00")
 	_XKB_MOD_NAME_LOGO  = []byte("Mod4\x00")
+	_XKB_MOD_NAME_ALTGR = []byte("Mod5\x00")
+	_XKB_MOD_NAME_CAPS  = []byte("Lock\x00")
+	_XKB_MOD_NAME_NUM   = []byte("Mod2\x00")
 )
 
 func (x *Context) Destroy() {
@@ -141,6 +147,15 @@ func (x *Context) DispatchKey(keyCode uint32) (events []event.Event) {
 		if C.xkb_state_mod_name_is_active(x.state, (*C.char)(unsafe.Pointer(&_XKB_MOD_NAME_LOGO[0])), C.XKB_STATE_MODS_EFFECTIVE) == 1 {
 			cmd.Modifiers |= key.ModSuper
 		}
+		if C.xkb_state_mod_name_is_active(x.state, (*C.char)(unsafe.Pointer(&_XKB_MOD_NAME_ALTGR[0])), C.XKB_STATE_MODS_EFFECTIVE) == 1 {
+			cmd.Modifiers |= key.ModAltGr
+		}
+		if C.xkb_state_mod_name_is_active(x.state, (*C.char)(unsafe.Pointer(&_XKB_MOD_NAME_CAPS[0])), C.XKB_STATE_MODS_EFFECTIVE) == 1 {
+			cmd.Modifiers |= key.ModCapsLock
+		}
+		if C.xkb_state_mod_name_is_active(x.state, (*C.char)(unsafe.Pointer(&_XKB_MOD_NAME_NUM[0])), C.XKB_STATE_MODS_EFFECTIVE) == 1 {
+			cmd.Modifiers |= key.ModNumLock
+		}
 		events = append(events, cmd)
 	}
 	C.xkb_compose_state_feed(x.compState, sym)
@@ -185,9 +200,14 @@ func (x *Context) IsRepeatKey(keyCode uint32) bool {
 	return C.xkb_keymap_key_repeats(x.keyMap, C.xkb_keycode_t(keyCode)) == 1
 }
 
-func (x *Context) UpdateMask(depressed, latched, locked, group uint32) {
+func (x *Context) UpdateMask(depressed, latched, locked, group uint32) (events []event.Event) {
 	xkbGrp := C.xkb_layout_index_t(group)
 	C.xkb_state_update_mask(x.state, C.xkb_mod_mask_t(depressed), C.xkb_mod_mask_t(latched), C.xkb_mod_mask_t(locked), xkbGrp, xkbGrp, xkbGrp)
+	if xkbGrp != x.group {
+		x.group = xkbGrp
+		events = append(events, key.LayoutEvent{Group: int(xkbGrp)})
+	}
+	return
 }
 
 func mapXKBKeyCode(keyCode uint32) uint32 {
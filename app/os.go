@@ -47,9 +47,16 @@ type Config struct {
 	Decorated bool
 	// Focused reports whether has the keyboard focus.
 	Focused bool
+	// MaxIdleDeadline bounds how long the JS backend's requestIdleCallback
+	// may delay a requested redraw while the window isn't animating, in
+	// milliseconds. Zero means the browser's own default deadline. It has
+	// no effect on other platforms.
+	MaxIdleDeadline int
 	// decoHeight is the height of the fallback decoration for platforms such
 	// as Wayland that may need fallback client-side decorations.
 	decoHeight unit.Dp
+	// headless is true for windows created with the Headless option.
+	headless bool
 }
 
 // ConfigEvent is sent whenever the configuration of a Window changes.
@@ -201,6 +208,11 @@ type driver interface {
 	Configure([]Option)
 	// SetCursor updates the current cursor to name.
 	SetCursor(cursor pointer.Cursor)
+	// SetCloseIntercepted controls whether a platform close gesture (the
+	// window's close button, Alt-F4, Cmd-Q, WM_CLOSE, the Android back
+	// button, or a browser tab closing) delivers a CloseRequestEvent
+	// instead of proceeding straight to a DestroyEvent.
+	SetCloseIntercepted(intercept bool)
 	// Wakeup wakes up the event loop and sends a WakeupEvent.
 	// Wakeup()
 	// Perform actions on the window.
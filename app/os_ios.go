@@ -229,6 +229,16 @@ func onLowMemory() {
 	debug.FreeOSMemory()
 }
 
+//export onWillTerminate
+func onWillTerminate() {
+	runAtExit()
+}
+
+//export onDidEnterBackground
+func onDidEnterBackground() {
+	runAtExit()
+}
+
 //export onUpArrow
 func onUpArrow(h C.uintptr_t) {
 	viewFor(h).onKeyCommand(key.NameUpArrow)
@@ -330,6 +340,10 @@ func (w *window) SetCursor(cursor pointer.Cursor) {
 	w.cursor = windowSetCursor(w.cursor, cursor)
 }
 
+// SetCloseIntercepted is a no-op on iOS: views are dismissed by the host
+// app, not closed by the user, so there is no close gesture to intercept.
+func (w *window) SetCloseIntercepted(intercept bool) {}
+
 func (w *window) onKeyCommand(name key.Name) {
 	w.ProcessEvent(key.Event{
 		Name: name,
@@ -6,16 +6,19 @@ import (
 	"fmt"
 	"image"
 	"image/color"
+	"math"
 	"strings"
 	"syscall/js"
 	"time"
 	"unicode"
+	"unicode/utf16"
 	"unicode/utf8"
 
 	"gioui.org/internal/f32color"
 
 	"gioui.org/f32"
 	"gioui.org/io/clipboard"
+	"gioui.org/io/gamepad"
 	"gioui.org/io/key"
 	"gioui.org/io/pointer"
 	"gioui.org/io/system"
@@ -47,13 +50,32 @@ type window struct {
 	visualViewport        js.Value
 	screenOrientation     js.Value
 	cleanfuncs            []func()
-	touches               []js.Value
-	composing             bool
-	requestFocus          bool
+	// pointers compacts DOM pointerId values, which may be arbitrarily
+	// large, into small pointer.ID values, by first-seen order.
+	pointers     []js.Value
+	composing    bool
+	requestFocus bool
 
 	chanAnimation chan struct{}
 	chanRedraw    chan struct{}
 
+	// gamepads tracks the last state seen for each connected gamepad, by
+	// its navigator.getGamepads() index, so pollGamepads can diff button
+	// and axis changes frame to frame.
+	gamepads      map[int]*gamepadState
+	nextGamepadID gamepad.ID
+	gamepadTicker *time.Ticker
+
+	// idlePending tracks a requestRedraw scheduled through
+	// requestIdleCallback (or its setTimeout(0) fallback) while the
+	// window isn't animating; idleFunc and idleHandle are the callback
+	// and handle needed to cancel it, and idleUsesTimeout records which
+	// API idleHandle belongs to.
+	idlePending     bool
+	idleUsesTimeout bool
+	idleFunc        js.Func
+	idleHandle      js.Value
+
 	config    Config
 	inset     f32.Point
 	scale     float32
@@ -64,6 +86,11 @@ type window struct {
 	wakeups       chan struct{}
 
 	contextStatus contextStatus
+
+	// closeIntercepted tracks whether beforeunload should deliver a
+	// CloseRequestEvent and ask the browser to confirm the close,
+	// instead of letting the tab or window close unconditionally.
+	closeIntercepted bool
 }
 
 func newWindow(win *callbacks, options []Option) error {
@@ -105,6 +132,9 @@ func newWindow(win *callbacks, options []Option) error {
 	w.addEventListeners()
 	w.addHistory()
 	w.w = win
+	w.gamepadTicker = time.NewTicker(gamepadPollInterval)
+	w.cleanfuncs = append(w.cleanfuncs, w.gamepadTicker.Stop)
+	w.cleanfuncs = append(w.cleanfuncs, w.cancelIdleRedraw)
 
 	go func() {
 		defer w.cleanup()
@@ -122,6 +152,13 @@ func newWindow(win *callbacks, options []Option) error {
 				w.animCallback()
 			case <-w.chanRedraw:
 				w.draw(true)
+			case <-w.gamepadTicker.C:
+				// animCallback already polls every frame while
+				// animating; the ticker only needs to cover the
+				// idle case.
+				if !w.animating {
+					w.pollGamepads()
+				}
 			}
 		}
 	}()
@@ -203,6 +240,25 @@ func (w *window) addEventListeners() {
 		}
 		return w.browserHistory.Call("back")
 	})
+	w.addEventListener(w.window, "beforeunload", func(this js.Value, args []js.Value) interface{} {
+		if !w.closeIntercepted {
+			return nil
+		}
+		w.w.Event(CloseRequestEvent{})
+		// Asking the browser to confirm the close requires both
+		// calling preventDefault and setting returnValue.
+		args[0].Call("preventDefault")
+		args[0].Set("returnValue", "")
+		return ""
+	})
+	w.addEventListener(w.window, "gamepadconnected", func(this js.Value, args []js.Value) interface{} {
+		w.pollGamepads()
+		return nil
+	})
+	w.addEventListener(w.window, "gamepaddisconnected", func(this js.Value, args []js.Value) interface{} {
+		w.pollGamepads()
+		return nil
+	})
 	w.addEventListener(w.document, "visibilitychange", func(this js.Value, args []js.Value) interface{} {
 		ev := system.StageEvent{}
 		switch w.document.Get("visibilityState").String() {
@@ -210,24 +266,36 @@ func (w *window) addEventListeners() {
 			ev.Stage = system.StagePaused
 		default:
 			ev.Stage = system.StageRunning
+			// A redraw scheduled through requestIdleCallback while
+			// hidden may have been deprioritized indefinitely by the
+			// browser; force it now that the page is visible again.
+			if w.idlePending {
+				w.cancelIdleRedraw()
+				w.pushRedraw()
+			}
 		}
 		w.w.Event(ev)
 		return nil
 	})
-	w.addEventListener(w.cnv, "mousemove", func(this js.Value, args []js.Value) interface{} {
-		w.pointerEvent(pointer.Move, 0, 0, args[0])
-		return nil
-	})
-	w.addEventListener(w.cnv, "mousedown", func(this js.Value, args []js.Value) interface{} {
-		w.pointerEvent(pointer.Press, 0, 0, args[0])
+	w.addEventListener(w.cnv, "pointerdown", func(this js.Value, args []js.Value) interface{} {
+		w.cnv.Call("setPointerCapture", args[0].Get("pointerId"))
+		w.pointerEvent(pointer.Press, args[0])
 		if w.requestFocus {
-			w.focus()
+			w.focus() // iOS can only focus inside a touch-originated event.
 			w.requestFocus = false
 		}
 		return nil
 	})
-	w.addEventListener(w.cnv, "mouseup", func(this js.Value, args []js.Value) interface{} {
-		w.pointerEvent(pointer.Release, 0, 0, args[0])
+	w.addEventListener(w.cnv, "pointerup", func(this js.Value, args []js.Value) interface{} {
+		w.pointerEvent(pointer.Release, args[0])
+		return nil
+	})
+	w.addEventListener(w.cnv, "pointermove", func(this js.Value, args []js.Value) interface{} {
+		w.pointerEvent(pointer.Move, args[0])
+		return nil
+	})
+	w.addEventListener(w.cnv, "pointercancel", func(this js.Value, args []js.Value) interface{} {
+		w.pointerEvent(pointer.Cancel, args[0])
 		return nil
 	})
 	w.addEventListener(w.cnv, "wheel", func(this js.Value, args []js.Value) interface{} {
@@ -242,35 +310,24 @@ func (w *window) addEventListeners() {
 			dx *= 120
 			dy *= 120
 		}
-		w.pointerEvent(pointer.Scroll, float32(dx), float32(dy), e)
-		return nil
-	})
-	w.addEventListener(w.cnv, "touchstart", func(this js.Value, args []js.Value) interface{} {
-		w.touchEvent(pointer.Press, args[0])
-		if w.requestFocus {
-			w.focus() // iOS can only focus inside a Touch event.
-			w.requestFocus = false
-		}
+		w.w.Event(pointer.Event{
+			Kind:      pointer.Scroll,
+			Source:    pointer.Mouse,
+			Scroll:    f32.Point{X: float32(dx), Y: float32(dy)},
+			Modifiers: modifiersFor(e),
+		})
 		return nil
 	})
-	w.addEventListener(w.cnv, "touchend", func(this js.Value, args []js.Value) interface{} {
-		w.touchEvent(pointer.Release, args[0])
+	w.addEventListener(w.cnv, "dragenter", func(this js.Value, args []js.Value) interface{} {
+		args[0].Call("preventDefault")
 		return nil
 	})
-	w.addEventListener(w.cnv, "touchmove", func(this js.Value, args []js.Value) interface{} {
-		w.touchEvent(pointer.Move, args[0])
+	w.addEventListener(w.cnv, "dragover", func(this js.Value, args []js.Value) interface{} {
+		args[0].Call("preventDefault")
 		return nil
 	})
-	w.addEventListener(w.cnv, "touchcancel", func(this js.Value, args []js.Value) interface{} {
-		// Cancel all touches even if only one touch was cancelled.
-		for i := range w.touches {
-			w.touches[i] = js.Null()
-		}
-		w.touches = w.touches[:0]
-		w.w.Event(pointer.Event{
-			Type:   pointer.Cancel,
-			Source: pointer.Touch,
-		})
+	w.addEventListener(w.cnv, "drop", func(this js.Value, args []js.Value) interface{} {
+		w.dropEvent(args[0])
 		return nil
 	})
 	w.addEventListener(w.tarea, "focus", func(this js.Value, args []js.Value) interface{} {
@@ -294,9 +351,15 @@ func (w *window) addEventListeners() {
 		w.composing = true
 		return nil
 	})
+	w.addEventListener(w.tarea, "compositionupdate", func(this js.Value, args []js.Value) interface{} {
+		w.compositionUpdate(args[0].Get("data").String())
+		return nil
+	})
 	w.addEventListener(w.tarea, "compositionend", func(this js.Value, args []js.Value) interface{} {
+		w.compositionUpdate(args[0].Get("data").String())
+		w.w.SetComposingRegion(key.Range{Start: -1, End: -1})
 		w.composing = false
-		w.flushInput()
+		w.tarea.Set("value", "")
 		return nil
 	})
 	w.addEventListener(w.tarea, "input", func(this js.Value, args []js.Value) interface{} {
@@ -326,6 +389,48 @@ func (w *window) flushInput() {
 	w.w.EditorInsert(string(val))
 }
 
+// compositionUpdate replaces the editor's composing region with an
+// in-progress IME composition string, mirroring the preedit handling done
+// natively by the Windows backend's WM_IME_COMPOSITION (os_windows.go) and
+// macOS's setMarkedText (os_macos.go): data replaces whatever was
+// previously composing, or the selection if this is the composition's
+// first update, and the resulting range is re-marked as composing so it
+// renders and behaves as a preedit run instead of committed text.
+func (w *window) compositionUpdate(data string) {
+	state := w.w.EditorState()
+	rng := state.compose
+	if rng.Start == -1 {
+		rng = state.Selection.Range
+	}
+	if rng.Start > rng.End {
+		rng.Start, rng.End = rng.End, rng.Start
+	}
+	end := rng.Start + utf8.RuneCountInString(data)
+	w.w.EditorReplace(rng, data)
+	w.w.SetComposingRegion(key.Range{Start: rng.Start, End: end})
+	units := w.tarea.Get("selectionEnd").Int()
+	pos := rng.Start + utf16RuneOffset(data, units)
+	w.w.SetEditorSelection(key.Range{Start: pos, End: pos})
+}
+
+// utf16RuneOffset converts units, a count of UTF-16 code units into s (as
+// reported by a textarea's selectionStart/selectionEnd, which JavaScript
+// strings are indexed in), into the equivalent rune offset.
+func utf16RuneOffset(s string, units int) int {
+	runes, chars := 0, 0
+	for _, r := range s {
+		if chars >= units {
+			break
+		}
+		chars++
+		if r1, _ := utf16.EncodeRune(r); r1 != unicode.ReplacementChar {
+			chars++
+		}
+		runes++
+	}
+	return runes
+}
+
 func (w *window) blur() {
 	w.tarea.Call("blur")
 	w.requestFocus = false
@@ -389,92 +494,82 @@ func modifiersFor(e js.Value) key.Modifiers {
 	return mods
 }
 
-func (w *window) touchEvent(typ pointer.Type, e js.Value) {
-	e.Call("preventDefault")
-	t := time.Duration(e.Get("timeStamp").Int()) * time.Millisecond
-	changedTouches := e.Get("changedTouches")
-	n := changedTouches.Length()
+// pointerEvent handles a DOM PointerEvent, unifying what used to be
+// separate mouse and touch listeners: the Pointer Events API reports
+// mouse, touch and pen input through the same event shape, distinguished
+// by pointerType, and additionally carries the pressure and tilt a Stylus
+// Source needs. The API reports a pressed barrel button the same way as
+// an ordinary secondary mouse click, with nothing to tell them apart, so
+// a barrel press shows up only as ButtonSecondary in Buttons and
+// StylusBarrel is never set; StylusEraser is the only stylus button
+// reported.
+func (w *window) pointerEvent(kind pointer.Kind, e js.Value) {
+	if kind != pointer.Cancel {
+		e.Call("preventDefault")
+	}
 	rect := w.cnv.Call("getBoundingClientRect")
 	scale := w.scale
-	var mods key.Modifiers
-	if e.Get("shiftKey").Bool() {
-		mods |= key.ModShift
-	}
-	if e.Get("altKey").Bool() {
-		mods |= key.ModAlt
-	}
-	if e.Get("ctrlKey").Bool() {
-		mods |= key.ModCtrl
-	}
-	for i := 0; i < n; i++ {
-		touch := changedTouches.Index(i)
-		pid := w.touchIDFor(touch)
-		x, y := touch.Get("clientX").Float(), touch.Get("clientY").Float()
-		x -= rect.Get("left").Float()
-		y -= rect.Get("top").Float()
-		pos := f32.Point{
-			X: float32(x) * scale,
-			Y: float32(y) * scale,
-		}
-		w.w.Event(pointer.Event{
-			Type:      typ,
-			Source:    pointer.Touch,
-			Position:  pos,
-			PointerID: pid,
-			Time:      t,
-			Modifiers: mods,
-		})
-	}
-}
+	x := (e.Get("clientX").Float() - rect.Get("left").Float()) * float64(scale)
+	y := (e.Get("clientY").Float() - rect.Get("top").Float()) * float64(scale)
 
-func (w *window) touchIDFor(touch js.Value) pointer.ID {
-	id := touch.Get("identifier")
-	for i, id2 := range w.touches {
-		if id2.Equal(id) {
-			return pointer.ID(i)
-		}
+	source := pointer.Mouse
+	switch e.Get("pointerType").String() {
+	case "touch":
+		source = pointer.Touch
+	case "pen":
+		source = pointer.Stylus
 	}
-	pid := pointer.ID(len(w.touches))
-	w.touches = append(w.touches, id)
-	return pid
-}
 
-func (w *window) pointerEvent(typ pointer.Type, dx, dy float32, e js.Value) {
-	e.Call("preventDefault")
-	x, y := e.Get("clientX").Float(), e.Get("clientY").Float()
-	rect := w.cnv.Call("getBoundingClientRect")
-	x -= rect.Get("left").Float()
-	y -= rect.Get("top").Float()
-	scale := w.scale
-	pos := f32.Point{
-		X: float32(x) * scale,
-		Y: float32(y) * scale,
-	}
-	scroll := f32.Point{
-		X: dx * scale,
-		Y: dy * scale,
-	}
-	t := time.Duration(e.Get("timeStamp").Int()) * time.Millisecond
 	jbtns := e.Get("buttons").Int()
 	var btns pointer.Buttons
-	if jbtns&1 != 0 {
+	if jbtns&0x1 != 0 {
 		btns |= pointer.ButtonPrimary
 	}
-	if jbtns&2 != 0 {
+	if jbtns&0x2 != 0 {
 		btns |= pointer.ButtonSecondary
 	}
-	if jbtns&4 != 0 {
+	if jbtns&0x4 != 0 {
 		btns |= pointer.ButtonTertiary
 	}
-	w.w.Event(pointer.Event{
-		Type:      typ,
-		Source:    pointer.Mouse,
+	var stylusBtns pointer.StylusButtons
+	if source == pointer.Stylus && jbtns&0x20 != 0 {
+		// The eraser button, where supported, is reported as bit 0x20
+		// of MouseEvent/PointerEvent.buttons.
+		stylusBtns |= pointer.StylusEraser
+	}
+
+	ev := pointer.Event{
+		Kind:      kind,
+		Source:    source,
+		PointerID: w.pointerIDFor(e.Get("pointerId")),
 		Buttons:   btns,
-		Position:  pos,
-		Scroll:    scroll,
-		Time:      t,
+		Position:  f32.Point{X: float32(x), Y: float32(y)},
+		Time:      time.Duration(e.Get("timeStamp").Int()) * time.Millisecond,
 		Modifiers: modifiersFor(e),
-	})
+	}
+	if source == pointer.Stylus {
+		ev.Pressure = float32(e.Get("pressure").Float())
+		ev.TiltX = float32(e.Get("tiltX").Float()) * math.Pi / 180
+		ev.TiltY = float32(e.Get("tiltY").Float()) * math.Pi / 180
+		ev.Twist = float32(e.Get("twist").Float()) * math.Pi / 180
+		ev.StylusButtons = stylusBtns
+	}
+	w.w.Event(ev)
+}
+
+// pointerIDFor returns a compact pointer.ID for a DOM pointerId, assigning
+// a new one the first time id is seen. Browsers don't guarantee pointerId
+// values are small or reused, so this keeps the same table-based
+// compaction the old per-touch identifier tracking used.
+func (w *window) pointerIDFor(id js.Value) pointer.ID {
+	for i, id2 := range w.pointers {
+		if id2.Equal(id) {
+			return pointer.ID(i)
+		}
+	}
+	pid := pointer.ID(len(w.pointers))
+	w.pointers = append(w.pointers, id)
+	return pid
 }
 
 func (w *window) addEventListener(this js.Value, event string, f func(this js.Value, args []js.Value) interface{}) {
@@ -499,6 +594,7 @@ func (w *window) animCallback() {
 	if anim {
 		w.requestAnimationFrame.Invoke(w.redraw)
 	}
+	w.pollGamepads()
 	if anim {
 		w.draw(false)
 	}
@@ -514,6 +610,14 @@ func (w *window) SetAnimating(anim bool) {
 	}
 }
 
+// SetCloseIntercepted controls whether beforeunload delivers a
+// CloseRequestEvent and prompts the user to confirm leaving the page
+// (intercept true), or lets the tab or window close unconditionally
+// (intercept false).
+func (w *window) SetCloseIntercepted(intercept bool) {
+	w.closeIntercepted = intercept
+}
+
 func (w *window) ReadClipboard() {
 	if w.clipboard.IsUndefined() {
 		return
@@ -593,12 +697,35 @@ var webCursor = [...]string{
 	pointer.CursorNorthWestSouthEastResize: "nwse-resize",
 }
 
+// SetCursor sets the CSS cursor for the canvas, encoding a custom cursor
+// registered with pointer.NewCursor or pointer.NewAnimatedCursor as a PNG
+// data URL (see cursorCSS). As on Windows (os_windows.go's
+// loadCustomCursor), an animated cursor only ever shows its first frame.
 func (w *window) SetCursor(cursor pointer.Cursor) {
 	style := w.cnv.Get("style")
+	if frames, ok := cursor.Frames(); ok {
+		css, ok := customCursorCSS.get(cursor)
+		if !ok {
+			if css, ok = cursorCSS(frames[0]); ok {
+				customCursorCSS.put(cursor, css)
+			} else {
+				style.Set("cursor", webCursor[pointer.CursorDefault])
+				return
+			}
+		}
+		style.Set("cursor", css)
+		return
+	}
 	style.Set("cursor", webCursor[cursor])
 }
 
 func (w *window) Wakeup() {
+	// A wakeup means the application has work to do now, so any redraw
+	// deferred to an idle callback no longer needs to wait for one.
+	if w.idlePending {
+		w.cancelIdleRedraw()
+		w.pushRedraw()
+	}
 	select {
 	case w.wakeups <- struct{}{}:
 	default:
@@ -726,13 +853,66 @@ func (w *window) navigationColor(c color.NRGBA) {
 	theme.Set("content", fmt.Sprintf("#%06X", []uint8{rgba.R, rgba.G, rgba.B}))
 }
 
+// requestRedraw schedules a draw. While animating, the next
+// requestAnimationFrame callback (animCallback) will draw anyway, so the
+// request is just queued for the main goroutine to pick up; otherwise it
+// is deferred to requestIdleCallback (or setTimeout(0) where unsupported)
+// so a mostly-static UI doesn't pay requestAnimationFrame's cost merely to
+// process a single one-off redraw, such as after a resize.
 func (w *window) requestRedraw() {
+	if w.animating {
+		w.pushRedraw()
+		return
+	}
+	w.scheduleIdleRedraw()
+}
+
+func (w *window) pushRedraw() {
 	select {
 	case w.chanRedraw <- struct{}{}:
 	default:
 	}
 }
 
+func (w *window) scheduleIdleRedraw() {
+	if w.idlePending {
+		return
+	}
+	w.idlePending = true
+	w.idleFunc = js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		w.idlePending = false
+		w.idleFunc.Release()
+		w.pushRedraw()
+		return nil
+	})
+	if ric := w.window.Get("requestIdleCallback"); ric.Truthy() {
+		opts := js.Global().Get("Object").New()
+		if d := w.config.MaxIdleDeadline; d > 0 {
+			opts.Set("timeout", d)
+		}
+		w.idleUsesTimeout = false
+		w.idleHandle = ric.Invoke(w.idleFunc, opts)
+	} else {
+		w.idleUsesTimeout = true
+		w.idleHandle = w.window.Call("setTimeout", w.idleFunc, 0)
+	}
+}
+
+// cancelIdleRedraw cancels a redraw scheduled by scheduleIdleRedraw, if
+// any is still pending.
+func (w *window) cancelIdleRedraw() {
+	if !w.idlePending {
+		return
+	}
+	w.idlePending = false
+	if w.idleUsesTimeout {
+		w.window.Call("clearTimeout", w.idleHandle)
+	} else {
+		w.window.Call("cancelIdleCallback", w.idleHandle)
+	}
+	w.idleFunc.Release()
+}
+
 func osMain() {
 	select {}
 }
@@ -368,6 +368,11 @@ type window struct {
 	// cmdKeys is for storing the current key event while
 	// waiting for a doCommandBySelector.
 	cmdKeys cmdKeys
+
+	// closeIntercepted tracks whether the window delegate's
+	// windowShouldClose: should veto the close and deliver a
+	// CloseRequestEvent instead.
+	closeIntercepted bool
 }
 
 type cmdKeys struct {
@@ -537,6 +542,14 @@ func (w *window) SetCursor(cursor pointer.Cursor) {
 	w.cursor = windowSetCursor(w.cursor, cursor)
 }
 
+// SetCloseIntercepted controls whether the window delegate's
+// windowShouldClose: callback delivers a CloseRequestEvent and vetoes the
+// close (intercept true), or lets the close proceed to a DestroyEvent as
+// usual (intercept false).
+func (w *window) SetCloseIntercepted(intercept bool) {
+	w.closeIntercepted = intercept
+}
+
 func (w *window) EditorStateChanged(old, new editorState) {
 	if old.Selection.Range != new.Selection.Range || !areSnippetsConsistent(old.Snippet, new.Snippet) {
 		C.discardMarkedText(w.view)
@@ -982,6 +995,19 @@ func gio_onAttached(h C.uintptr_t, attached C.int) {
 	}
 }
 
+// gio_onShouldClose is called from the window delegate's
+// windowShouldClose:, and returns 0 to veto the close.
+//
+//export gio_onShouldClose
+func gio_onShouldClose(h C.uintptr_t) C.int {
+	w := windowFor(h)
+	if w.closeIntercepted {
+		w.ProcessEvent(CloseRequestEvent{})
+		return 0
+	}
+	return 1
+}
+
 //export gio_onDestroy
 func gio_onDestroy(h C.uintptr_t) {
 	w := windowFor(h)
@@ -1162,6 +1188,9 @@ func convertMods(mods C.NSUInteger) key.Modifiers {
 	if mods&C.NSShiftKeyMask != 0 {
 		kmods |= key.ModShift
 	}
+	if mods&C.NSAlphaShiftKeyMask != 0 {
+		kmods |= key.ModCapsLock
+	}
 	return kmods
 }
 
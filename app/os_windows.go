@@ -3,10 +3,12 @@
 package app
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
 	"image"
 	"io"
+	"math"
 	"runtime"
 	"sort"
 	"strings"
@@ -52,6 +54,21 @@ type window struct {
 	// frameDims stores the last seen window frame width and height.
 	frameDims image.Point
 	loop      *eventLoop
+
+	// closeIntercepted tracks whether WM_CLOSE should deliver a
+	// CloseRequestEvent instead of proceeding to DestroyWindow.
+	closeIntercepted bool
+
+	// rawMousePos is the position used for WM_INPUT-derived pointer.Move
+	// events. WM_POINTERUPDATE is coalesced by Windows to roughly 125Hz and
+	// rounds to whole pixels, so rawMousePos is reset to each such event's
+	// position and then refined by the uncoalesced, sub-pixel relative
+	// deltas that arrive through WM_INPUT in between.
+	rawMousePos f32.Point
+	// pointerButtons is the button state of the most recent pointer event,
+	// reused for the synthetic Move events WM_INPUT produces since raw
+	// input carries its own, separate button change encoding.
+	pointerButtons pointer.Buttons
 }
 
 const _WM_WAKEUP = windows.WM_USER + iota
@@ -64,7 +81,10 @@ type gpuAPI struct {
 // drivers is the list of potential Context implementations.
 var drivers []gpuAPI
 
-// winMap maps win32 HWNDs to *windows.
+// winMap maps win32 HWNDs to *windows. It is the registry backing Gio's
+// multi-window support on Win32: windowProc uses it to route each message
+// to the window whose HWND it names, and newWindow/WM_DESTROY add and
+// remove entries as windows are created and destroyed.
 var winMap sync.Map
 
 // iconID is the ID of the icon in the resource file.
@@ -80,10 +100,22 @@ var resources struct {
 	cursor syscall.Handle
 }
 
+// osMain blocks forever, regardless of how many windows are open or
+// closed: as on the other desktop backends, Gio never decides on the
+// application's behalf that the last window closing means the process
+// should exit. A program that wants that behavior gets it for free by
+// calling os.Exit from its own DestroyEvent handling once its window count
+// reaches zero.
 func osMain() {
 	select {}
 }
 
+// newWindow starts a new top-level window. Windows are independent: each
+// gets its own goroutine locked to its own OS thread, so that its message
+// queue, animation/redraw state (see window.animating) and WM_QUIT all
+// belong to that window alone and don't affect any other window or
+// osMain's select{}. winMap is the only state shared between windows, and
+// sync.Map makes looking a HWND up in it, from any thread, safe.
 func newWindow(win *callbacks, options []Option) {
 	done := make(chan struct{})
 	go func() {
@@ -120,7 +152,13 @@ func newWindow(win *callbacks, options []Option) {
 
 // initResources initializes the resources global.
 func initResources() error {
-	windows.SetProcessDPIAware()
+	// Per-Monitor-V2 awareness lets Windows tell us about DPI changes
+	// (WM_DPICHANGED) as a window crosses monitors instead of silently
+	// scaling its bitmap, which is what plain SetProcessDPIAware's
+	// system-DPI awareness would otherwise leave Windows to do.
+	if err := windows.SetProcessDpiAwarenessContext(windows.DPI_AWARENESS_CONTEXT_PER_MONITOR_AWARE_V2); err != nil {
+		windows.SetProcessDPIAware()
+	}
 	hInst, err := windows.GetModuleHandle()
 	if err != nil {
 		return err
@@ -180,6 +218,17 @@ func (w *window) init() error {
 	if err := windows.EnableMouseInPointer(1); err != nil {
 		return err
 	}
+	// Register for WM_INPUT so high-frequency, sub-pixel relative mouse and
+	// pen deltas are available between the coalesced WM_POINTERUPDATE
+	// messages handled in windowProc.
+	if err := windows.RegisterRawInputDevices([]windows.RawInputDevice{
+		// HID mouse.
+		{UsagePage: 0x01, Usage: 0x02, Flags: 0, Target: hwnd},
+		// HID digitizer pen.
+		{UsagePage: 0x0D, Usage: 0x02, Flags: 0, Target: hwnd},
+	}); err != nil {
+		return err
+	}
 	w.hdc, err = windows.GetDC(hwnd)
 	if err != nil {
 		windows.DestroyWindow(hwnd)
@@ -246,8 +295,30 @@ func windowProc(hwnd syscall.Handle, msg uint32, wParam, lParam uintptr) uintptr
 		// The message is processed.
 		return windows.TRUE
 	case windows.WM_DPICHANGED:
-		// Let Windows know we're prepared for runtime DPI changes.
+		// lParam points at the RECT Windows recommends for the new DPI;
+		// applying it keeps the window's dp size constant across the
+		// monitor change instead of leaving its pixel size constant and
+		// blurring the content.
+		rect := (*windows.Rect)(unsafe.Pointer(lParam))
+		windows.SetWindowPos(w.hwnd, 0, rect.Left, rect.Top, rect.Right-rect.Left, rect.Bottom-rect.Top,
+			windows.SWP_NOZORDER|windows.SWP_NOACTIVATE)
+		return windows.TRUE
+	case windows.WM_GETDPISCALEDSIZE:
+		// Scale the suggested window size ourselves so the transition
+		// lands on the same dp size at the new DPI, rather than Windows'
+		// own guess (which assumes no custom non-client area).
+		size := (*windows.Size)(unsafe.Pointer(lParam))
+		newDPI := int(wParam)
+		if oldDPI := windows.GetWindowDPI(w.hwnd); oldDPI != 0 && newDPI != 0 {
+			size.CX = int32(int(size.CX) * newDPI / oldDPI)
+			size.CY = int32(int(size.CY) * newDPI / oldDPI)
+		}
 		return windows.TRUE
+	case windows.WM_DISPLAYCHANGE, windows.WM_SETTINGCHANGE:
+		// A monitor was added/removed/reconfigured, or a system setting
+		// (including text scaling) changed; re-derive config from
+		// current window placement and DPI.
+		w.update()
 	case windows.WM_ERASEBKGND:
 		// Avoid flickering between GPU content and background color.
 		return windows.TRUE
@@ -296,6 +367,8 @@ func windowProc(hwnd syscall.Handle, msg uint32, wParam, lParam uintptr) uintptr
 		}
 
 		w.pointerUpdate(pi, pid, kind, lParam)
+	case windows.WM_INPUT:
+		w.rawInput(lParam)
 	case windows.WM_CANCELMODE:
 		w.ProcessEvent(pointer.Event{
 			Kind: pointer.Cancel,
@@ -319,6 +392,13 @@ func windowProc(hwnd syscall.Handle, msg uint32, wParam, lParam uintptr) uintptr
 		w.scrollEvent(wParam, lParam, false, getModifiers())
 	case windows.WM_POINTERHWHEEL:
 		w.scrollEvent(wParam, lParam, true, getModifiers())
+	case windows.WM_CLOSE:
+		if w.closeIntercepted {
+			w.ProcessEvent(CloseRequestEvent{})
+			return 0
+		}
+		// Let DefWindowProc destroy the window as usual.
+		break
 	case windows.WM_DESTROY:
 		w.ProcessEvent(Win32ViewEvent{})
 		w.ProcessEvent(DestroyEvent{})
@@ -393,16 +473,18 @@ func windowProc(hwnd syscall.Handle, msg uint32, wParam, lParam uintptr) uintptr
 		w.loop.Wakeup()
 		w.loop.FlushEvents()
 	case windows.WM_IME_STARTCOMPOSITION:
-		imc := windows.ImmGetContext(w.hwnd)
-		if imc == 0 {
+		if !w.updateIMECaret() {
 			return windows.TRUE
 		}
-		defer windows.ImmReleaseContext(w.hwnd, imc)
-		sel := w.w.EditorState().Selection
-		caret := sel.Transform.Transform(sel.Caret.Pos.Add(f32.Pt(0, sel.Caret.Descent)))
-		icaret := image.Pt(int(caret.X+.5), int(caret.Y+.5))
-		windows.ImmSetCompositionWindow(imc, icaret.X, icaret.Y)
-		windows.ImmSetCandidateWindow(imc, icaret.X, icaret.Y)
+	case windows.WM_IME_NOTIFY:
+		switch wParam {
+		case windows.IMN_OPENCANDIDATE, windows.IMN_CHANGECANDIDATE:
+			// The candidate list follows the composition window, but its
+			// size isn't known until IME notifies us it's open, so
+			// reposition it here too rather than relying solely on the
+			// placement made for WM_IME_STARTCOMPOSITION.
+			w.updateIMECaret()
+		}
 	case windows.WM_IME_COMPOSITION:
 		imc := windows.ImmGetContext(w.hwnd)
 		if imc == 0 {
@@ -459,12 +541,21 @@ func getModifiers() key.Modifiers {
 	if windows.GetKeyState(windows.VK_MENU)&0x1000 != 0 {
 		kmods |= key.ModAlt
 	}
+	if windows.GetKeyState(windows.VK_RMENU)&0x1000 != 0 {
+		kmods |= key.ModAltGr
+	}
 	if windows.GetKeyState(windows.VK_CONTROL)&0x1000 != 0 {
 		kmods |= key.ModCtrl
 	}
 	if windows.GetKeyState(windows.VK_SHIFT)&0x1000 != 0 {
 		kmods |= key.ModShift
 	}
+	if windows.GetKeyState(windows.VK_CAPITAL)&0x1 != 0 {
+		kmods |= key.ModCapsLock
+	}
+	if windows.GetKeyState(windows.VK_NUMLOCK)&0x1 != 0 {
+		kmods |= key.ModNumLock
+	}
 	return kmods
 }
 
@@ -513,20 +604,68 @@ func (w *window) pointerUpdate(pi windows.PointerInfo, pid pointer.ID, kind poin
 	}
 
 	src := pointer.Touch
-	if pi.PointerType == windows.PT_MOUSE {
+	switch pi.PointerType {
+	case windows.PT_MOUSE:
 		src = pointer.Mouse
+	case windows.PT_PEN:
+		src = pointer.Stylus
 	}
 
 	x, y := coordsFromlParam(lParam)
 	np := windows.Point{X: int32(x), Y: int32(y)}
 	windows.ScreenToClient(w.hwnd, &np)
 	p := f32.Point{X: float32(np.X), Y: float32(np.Y)}
-	w.ProcessEvent(pointer.Event{
+	// Resync the WM_INPUT delta accumulator to the ground truth reported by
+	// the pointer API; see the rawMousePos field doc.
+	w.rawMousePos = p
+	w.pointerButtons = getPointerButtons(pi)
+	evt := pointer.Event{
 		Kind:      kind,
 		Source:    src,
 		Position:  p,
 		PointerID: pid,
-		Buttons:   getPointerButtons(pi),
+		Buttons:   w.pointerButtons,
+		Time:      windows.GetMessageTime(),
+		Modifiers: getModifiers(),
+	}
+	if src == pointer.Stylus {
+		// The pointer-ID-indexed pen properties (pressure, tilt,
+		// rotation, barrel/eraser state) live in a separate struct from
+		// PointerInfo and need their own query.
+		if pen, err := windows.GetPointerPenInfo(uint32(pid)); err == nil {
+			evt.Pressure = float32(pen.Pressure) / 1024
+			evt.TiltX = float32(pen.TiltX) * math.Pi / 180
+			evt.TiltY = float32(pen.TiltY) * math.Pi / 180
+			evt.Twist = float32(pen.Rotation) * math.Pi / 180
+			evt.StylusButtons = getStylusButtons(pen)
+		}
+	}
+	w.ProcessEvent(evt)
+}
+
+// rawInput handles WM_INPUT, delivering the uncoalesced, sub-pixel relative
+// mouse deltas that WM_POINTERUPDATE's ~125Hz coalesced, whole-pixel
+// reporting loses. lParam names the RAWINPUT buffer to fetch with
+// GetRawInputData; unlike the WM_POINTER* messages, WM_INPUT provides no
+// absolute position of its own, so deltas accumulate onto rawMousePos,
+// which pointerUpdate periodically resyncs to the pointer API's ground
+// truth.
+func (w *window) rawInput(lParam uintptr) {
+	raw, ok := windows.GetRawInputMouseData(lParam)
+	if !ok || raw.Flags&windows.MOUSE_MOVE_ABSOLUTE != 0 {
+		// Absolute-positioning devices (e.g. a VM's tablet passthrough)
+		// don't benefit from delta accumulation, and some devices report
+		// zero deltas here regardless of motion; either way there is
+		// nothing useful to add atop WM_POINTERUPDATE for them.
+		return
+	}
+	w.rawMousePos.X += float32(raw.LastX)
+	w.rawMousePos.Y += float32(raw.LastY)
+	w.ProcessEvent(pointer.Event{
+		Kind:      pointer.Move,
+		Source:    pointer.Mouse,
+		Position:  w.rawMousePos,
+		Buttons:   w.pointerButtons,
 		Time:      windows.GetMessageTime(),
 		Modifiers: getModifiers(),
 	})
@@ -612,6 +751,13 @@ func (w *window) SetAnimating(anim bool) {
 	w.animating = anim
 }
 
+// SetCloseIntercepted controls whether WM_CLOSE delivers a
+// CloseRequestEvent and vetoes the close (intercept true), or lets the
+// default window procedure destroy the window as usual (intercept false).
+func (w *window) SetCloseIntercepted(intercept bool) {
+	w.closeIntercepted = intercept
+}
+
 func (w *window) ProcessEvent(e event.Event) {
 	w.w.ProcessEvent(e)
 	w.loop.FlushEvents()
@@ -677,12 +823,29 @@ func (w *window) ReadClipboard() {
 	w.readClipboard()
 }
 
+// clipboardHTMLFormat is the name Windows registers the CF_HTML format
+// under; unlike CF_UNICODETEXT it has no built-in constant.
+const clipboardHTMLFormat = "HTML Format"
+
+// clipboardPNGFormat is the de-facto registered name browsers and other
+// Win32 apps use for PNG clipboard data, there being no built-in CF_PNG.
+const clipboardPNGFormat = "PNG"
+
 func (w *window) readClipboard() error {
 	if err := windows.OpenClipboard(w.hwnd); err != nil {
 		return err
 	}
 	defer windows.CloseClipboard()
-	mem, err := windows.GetClipboardData(windows.CF_UNICODETEXT)
+	// Prefer richer formats over plain text, mirroring the order most
+	// Win32 clipboard sources populate them in.
+	mime, format := "text/html", windows.RegisterClipboardFormat(clipboardHTMLFormat)
+	if !windows.IsClipboardFormatAvailable(format) {
+		mime, format = "image/png", windows.RegisterClipboardFormat(clipboardPNGFormat)
+	}
+	if !windows.IsClipboardFormatAvailable(format) {
+		mime, format = "application/text", windows.CF_UNICODETEXT
+	}
+	mem, err := windows.GetClipboardData(format)
 	if err != nil {
 		return err
 	}
@@ -691,11 +854,16 @@ func (w *window) readClipboard() error {
 		return err
 	}
 	defer windows.GlobalUnlock(mem)
-	content := gowindows.UTF16PtrToString((*uint16)(unsafe.Pointer(ptr)))
+	var content []byte
+	if format == windows.CF_UNICODETEXT {
+		content = []byte(gowindows.UTF16PtrToString((*uint16)(unsafe.Pointer(ptr))))
+	} else {
+		content = append([]byte(nil), unsafe.Slice((*byte)(ptr), windows.GlobalSize(mem))...)
+	}
 	w.ProcessEvent(transfer.DataEvent{
-		Type: "application/text",
+		Type: mime,
 		Open: func() io.ReadCloser {
-			return io.NopCloser(strings.NewReader(content))
+			return io.NopCloser(bytes.NewReader(content))
 		},
 	})
 	return nil
@@ -773,10 +941,10 @@ func (w *window) Configure(options []Option) {
 }
 
 func (w *window) WriteClipboard(mime string, s []byte) {
-	w.writeClipboard(string(s))
+	w.writeClipboard(mime, s)
 }
 
-func (w *window) writeClipboard(s string) error {
+func (w *window) writeClipboard(mime string, s []byte) error {
 	if err := windows.OpenClipboard(w.hwnd); err != nil {
 		return err
 	}
@@ -784,6 +952,17 @@ func (w *window) writeClipboard(s string) error {
 	if err := windows.EmptyClipboard(); err != nil {
 		return err
 	}
+	switch mime {
+	case "text/html":
+		return w.setClipboardBytes(windows.RegisterClipboardFormat(clipboardHTMLFormat), s)
+	case "image/png":
+		return w.setClipboardBytes(windows.RegisterClipboardFormat(clipboardPNGFormat), s)
+	default:
+		return w.setClipboardText(string(s))
+	}
+}
+
+func (w *window) setClipboardText(s string) error {
 	u16, err := gowindows.UTF16FromString(s)
 	if err != nil {
 		return err
@@ -808,6 +987,28 @@ func (w *window) writeClipboard(s string) error {
 	return nil
 }
 
+// setClipboardBytes places s verbatim under format, for clipboard formats
+// (registered HTML and PNG among them) whose payload is just a byte blob
+// rather than the UTF-16 text CF_UNICODETEXT requires.
+func (w *window) setClipboardBytes(format uint32, s []byte) error {
+	mem, err := windows.GlobalAlloc(len(s))
+	if err != nil {
+		return err
+	}
+	ptr, err := windows.GlobalLock(mem)
+	if err != nil {
+		windows.GlobalFree(mem)
+		return err
+	}
+	copy(unsafe.Slice((*byte)(ptr), len(s)), s)
+	windows.GlobalUnlock(mem)
+	if err := windows.SetClipboardData(format, mem); err != nil {
+		windows.GlobalFree(mem)
+		return err
+	}
+	return nil
+}
+
 func (w *window) SetCursor(cursor pointer.Cursor) {
 	c, err := loadCursor(cursor)
 	if err != nil {
@@ -856,13 +1057,90 @@ func loadCursor(cursor pointer.Cursor) (syscall.Handle, error) {
 	case pointer.CursorNone:
 		return 0, nil
 	default:
+		if frames, ok := cursor.Frames(); ok {
+			return loadCustomCursor(frames)
+		}
 		return windows.LoadCursor(windowsCursor[cursor])
 	}
 }
 
-func (w *window) ShowTextInput(show bool) {}
+// loadCustomCursor builds a Win32 cursor for a custom pointer.Cursor
+// registered with pointer.NewCursor or pointer.NewAnimatedCursor, using
+// CreateIconIndirect. Animated cursors currently display only their
+// first frame; ticking through the remaining frames would require
+// periodically re-calling SetCursor, which is left for a future change.
+func loadCustomCursor(frames []pointer.CursorFrame) (syscall.Handle, error) {
+	f := frames[0]
+	bounds := f.Img.Bounds()
+	w, h := bounds.Dx(), bounds.Dy()
+	pix := make([]byte, w*h*4)
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			r, g, b, a := f.Img.At(bounds.Min.X+x, bounds.Min.Y+y).RGBA()
+			o := (y*w + x) * 4
+			// Win32 expects a top-down, premultiplied BGRA bitmap.
+			pix[o+0] = byte(b >> 8)
+			pix[o+1] = byte(g >> 8)
+			pix[o+2] = byte(r >> 8)
+			pix[o+3] = byte(a >> 8)
+		}
+	}
+	color, err := windows.CreateBitmap(w, h, pix)
+	if err != nil {
+		return 0, err
+	}
+	mask, err := windows.CreateBitmap(w, h, make([]byte, w*h*4))
+	if err != nil {
+		return 0, err
+	}
+	return windows.CreateIconIndirect(&windows.IconInfo{
+		Icon:     false,
+		XHotspot: uint32(f.Hotspot.X),
+		YHotspot: uint32(f.Hotspot.Y),
+		Mask:     mask,
+		Color:    color,
+	})
+}
+
+// updateIMECaret repositions the IME composition and candidate windows at
+// the focused editor's caret. It reports whether w.hwnd currently has an
+// input context to position.
+func (w *window) updateIMECaret() bool {
+	imc := windows.ImmGetContext(w.hwnd)
+	if imc == 0 {
+		return false
+	}
+	defer windows.ImmReleaseContext(w.hwnd, imc)
+	sel := w.w.EditorState().Selection
+	caret := sel.Transform.Transform(sel.Caret.Pos.Add(f32.Pt(0, sel.Caret.Descent)))
+	icaret := image.Pt(int(caret.X+.5), int(caret.Y+.5))
+	windows.ImmSetCompositionWindow(imc, icaret.X, icaret.Y)
+	windows.ImmSetCandidateWindow(imc, icaret.X, icaret.Y)
+	return true
+}
+
+// ShowTextInput enables or disables the IME for w, associating or
+// disassociating its default input context with w.hwnd.
+func (w *window) ShowTextInput(show bool) {
+	if show {
+		windows.ImmAssociateContextEx(w.hwnd, 0, windows.IACE_DEFAULT)
+	} else {
+		windows.ImmAssociateContextEx(w.hwnd, 0, 0)
+	}
+}
 
-func (w *window) SetInputHint(_ key.InputHint) {}
+// SetInputHint disassociates the IME for hints whose on-screen-keyboard
+// equivalent on other platforms is a restricted digit entry pad, since
+// composing text makes no sense there; every other hint gets the default
+// input context back.
+func (w *window) SetInputHint(hint key.InputHint) {
+	switch hint {
+	case key.HintNumeric, key.HintTelephone:
+		windows.ImmAssociateContextEx(w.hwnd, 0, 0)
+	default:
+		windows.ImmAssociateContextEx(w.hwnd, 0, windows.IACE_DEFAULT)
+	}
+}
 
 func (w *window) HDC() syscall.Handle {
 	return w.hdc
@@ -1052,3 +1330,14 @@ func getPointerButtons(pi windows.PointerInfo) pointer.Buttons {
 
 	return btns
 }
+
+func getStylusButtons(pen windows.PointerPenInfo) pointer.StylusButtons {
+	var btns pointer.StylusButtons
+	if pen.PenFlags&windows.PEN_FLAG_BARREL != 0 {
+		btns |= pointer.StylusBarrel
+	}
+	if pen.PenFlags&windows.PEN_FLAG_INVERTED != 0 || pen.PenFlags&windows.PEN_FLAG_ERASER != 0 {
+		btns |= pointer.StylusEraser
+	}
+	return btns
+}
@@ -0,0 +1,11 @@
+// SPDX-License-Identifier: Unlicense OR MIT
+
+//go:build ios && pprof
+// +build ios,pprof
+
+package app
+
+// Importing gioui.org/app/internal/profile here, rather than from the
+// user's own program, makes -tags pprof self-contained: any gogio build
+// for -target ios or tvos with that tag gets profiling for free.
+import _ "gioui.org/app/internal/profile"
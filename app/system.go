@@ -11,3 +11,17 @@ type DestroyEvent struct {
 }
 
 func (DestroyEvent) ImplementsEvent() {}
+
+// CloseRequestEvent is sent when the platform receives a user request to
+// close the window, such as a click on the window's close button, Alt-F4,
+// Cmd-Q, the Android back button at the top of the back stack, or a
+// browser tab closing. It gives the application the opportunity to prompt
+// the user, for example to save changes, before the window is destroyed.
+//
+// A CloseRequestEvent is only delivered after [Window.PreventClose] was
+// called with true; otherwise the close proceeds straight to a
+// [DestroyEvent]. Call [Window.PreventClose] with false once the window is
+// safe to close, for example after the user confirms or declines to save.
+type CloseRequestEvent struct{}
+
+func (CloseRequestEvent) ImplementsEvent() {}
@@ -12,6 +12,7 @@ import (
 	"time"
 	"unicode/utf8"
 
+	"gioui.org/app/internal/accessibility"
 	"gioui.org/f32"
 	"gioui.org/font/gofont"
 	"gioui.org/gpu"
@@ -86,6 +87,9 @@ type Window struct {
 		tree     []input.SemanticNode
 		ids      map[input.SemanticID]input.SemanticNode
 	}
+	// access bridges the semantic tree to the platform accessibility API
+	// (AT-SPI2, UI Automation or NSAccessibility).
+	access   accessibility.Adapter
 	imeState editorState
 	driver   driver
 	// basic is the driver interface that is needed even after the window is gone.
@@ -108,6 +112,7 @@ type eventSummary struct {
 	view    *ViewEvent
 	frame   *frameEvent
 	destroy *DestroyEvent
+	close   *CloseRequestEvent
 }
 
 type callbacks struct {
@@ -170,6 +175,11 @@ func (w *Window) validateAndProcess(size image.Point, sync bool, frame *op.Ops,
 			}
 			w.gpu = gpu
 		}
+		// Resolve hit-testing, hover and cursor state against this frame's
+		// geometry before it reaches the GPU, so the Enter/Leave events and
+		// cursor updateCursor reads afterwards describe what is about to be
+		// drawn rather than the previous frame.
+		w.queue.Frame(frame)
 		if w.gpu != nil {
 			if err := w.frame(frame, size); err != nil {
 				w.ctx.Unlock()
@@ -185,7 +195,6 @@ func (w *Window) validateAndProcess(size image.Point, sync bool, frame *op.Ops,
 				return err
 			}
 		}
-		w.queue.Frame(frame)
 		// Let the client continue as soon as possible, in particular before
 		// a potentially blocking Present.
 		signal()
@@ -303,6 +312,22 @@ func (w *Window) Option(opts ...Option) {
 	})
 }
 
+// PreventClose controls whether platform close gestures (the window's
+// close button, Alt-F4, Cmd-Q, WM_CLOSE, the Android back button, or a
+// browser tab closing) are vetoed and delivered as a [CloseRequestEvent]
+// instead of proceeding straight to a [DestroyEvent]. The setting stays in
+// effect until it is changed: call PreventClose(true) before a close
+// gesture arrives, for example while there are unsaved changes, and
+// PreventClose(false) to let later close gestures destroy the window.
+func (w *Window) PreventClose(prevent bool) {
+	if w.basic == nil {
+		return
+	}
+	w.Run(func() {
+		w.driver.SetCloseIntercepted(prevent)
+	})
+}
+
 // Run f in the same thread as the native window event loop, and wait for f to
 // return or the window to close. If the window has not yet been created,
 // Run calls f directly.
@@ -407,9 +432,13 @@ func (c *callbacks) LookupSemantic(semID input.SemanticID) (input.SemanticNode,
 
 func (c *callbacks) AppendSemanticDiffs(diffs []input.SemanticID) []input.SemanticID {
 	c.w.updateSemantics()
+	start := len(diffs)
 	if tree := c.w.semantic.prevTree; len(tree) > 0 {
 		c.w.collectSemanticDiffs(&diffs, c.w.semantic.prevTree[0])
 	}
+	if added := diffs[start:]; len(added) > 0 {
+		c.w.access.TreeUpdated(accessibility.BuildTree(c), added)
+	}
 	return diffs
 }
 
@@ -418,6 +447,13 @@ func (c *callbacks) SemanticAt(pos f32.Point) (input.SemanticID, bool) {
 	return c.w.queue.SemanticAt(pos)
 }
 
+// SemanticHitTest answers a platform accessibility hit-test query by
+// routing it through the window's accessibility adapter, which in turn
+// calls back into SemanticAt.
+func (c *callbacks) SemanticHitTest(pos f32.Point) (input.SemanticID, bool) {
+	return c.w.access.HitTest(pos)
+}
+
 func (c *callbacks) EditorState() editorState {
 	return c.w.imeState
 }
@@ -561,6 +597,10 @@ func (c *callbacks) nextEvent() (event.Event, bool) {
 		// Clear pending events after DestroyEvent is delivered.
 		*s = eventSummary{}
 		return e, true
+	case s.close != nil:
+		e := *s.close
+		s.close = nil
+		return e, true
 	case s.cfg != nil:
 		e := *s.cfg
 		s.cfg = nil
@@ -615,12 +655,18 @@ func (w *Window) processEvent(e event.Event) bool {
 		w.coalesced.frame = &e2
 	case DestroyEvent:
 		w.destroyGPU()
+		if hd, ok := w.driver.(*headlessDriver); ok {
+			hd.gpu.Release()
+		}
 		w.driver = nil
+		w.access.Release()
 		if q := w.timer.quit; q != nil {
 			q <- struct{}{}
 			<-q
 		}
 		w.coalesced.destroy = &e2
+	case CloseRequestEvent:
+		w.coalesced.close = &e2
 	case ViewEvent:
 		if reflect.ValueOf(e2).IsZero() && w.gpu != nil {
 			w.ctx.Lock()
@@ -719,13 +765,18 @@ func (w *Window) init() {
 	var cnf Config
 	cnf.apply(unit.Metric{}, options)
 
-	w.nocontext = cnf.CustomRenderer
+	w.nocontext = cnf.CustomRenderer || cnf.headless
 	w.decorations.Theme = theme
 	w.decorations.Decorations = deco
 	w.decorations.enabled = cnf.Decorated
 	w.decorations.height = decoHeight
 	w.imeState.compose = key.Range{Start: -1, End: -1}
 	w.semantic.ids = make(map[input.SemanticID]input.SemanticNode)
+	w.access = accessibility.New(&callbacks{w})
+	if cnf.headless {
+		w.initHeadless(cnf.Size)
+		return
+	}
 	newWindow(&callbacks{w}, options)
 }
 
@@ -922,6 +973,15 @@ func Decorated(enabled bool) Option {
 	}
 }
 
+// MaxIdleDeadline sets the maximum time, in milliseconds, the JS backend's
+// requestIdleCallback may delay a requested redraw while the window isn't
+// animating. It has no effect on other platforms.
+func MaxIdleDeadline(ms int) Option {
+	return func(_ unit.Metric, cnf *Config) {
+		cnf.MaxIdleDeadline = ms
+	}
+}
+
 // flushEvent is sent to detect when the user program
 // has completed processing of all prior events. Its an
 // [io/event.Event] but only for internal use.
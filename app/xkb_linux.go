@@ -32,11 +32,19 @@ type xkb struct {
 	compTable *C.struct_xkb_compose_table
 	compState *C.struct_xkb_compose_state
 	utf8Buf   []byte
+	// group is the most recently reported active layout group, used
+	// to detect group changes in updateMask.
+	group C.xkb_layout_index_t
 }
 
 var (
 	_XKB_MOD_NAME_CTRL  = []byte("Control\x00")
 	_XKB_MOD_NAME_SHIFT = []byte("Shift\x00")
+	_XKB_MOD_NAME_ALT   = []byte("Mod1\x00")
+	_XKB_MOD_NAME_SUPER = []byte("Mod4\x00")
+	_XKB_MOD_NAME_ALTGR = []byte("Mod5\x00")
+	_XKB_MOD_NAME_CAPS  = []byte("Lock\x00")
+	_XKB_MOD_NAME_NUM   = []byte("Mod2\x00")
 )
 
 func (x *xkb) Destroy() {
@@ -124,12 +132,36 @@ func (x *xkb) dispatchKey(w *Window, keyCode C.uint32_t) {
 		if C.xkb_state_mod_name_is_active(x.state, (*C.char)(unsafe.Pointer(&_XKB_MOD_NAME_SHIFT[0])), C.XKB_STATE_MODS_EFFECTIVE) == 1 {
 			cmd.Modifiers |= key.ModShift
 		}
+		if C.xkb_state_mod_name_is_active(x.state, (*C.char)(unsafe.Pointer(&_XKB_MOD_NAME_ALT[0])), C.XKB_STATE_MODS_EFFECTIVE) == 1 {
+			cmd.Modifiers |= key.ModAlt
+		}
+		if C.xkb_state_mod_name_is_active(x.state, (*C.char)(unsafe.Pointer(&_XKB_MOD_NAME_SUPER[0])), C.XKB_STATE_MODS_EFFECTIVE) == 1 {
+			cmd.Modifiers |= key.ModSuper
+		}
+		if C.xkb_state_mod_name_is_active(x.state, (*C.char)(unsafe.Pointer(&_XKB_MOD_NAME_ALTGR[0])), C.XKB_STATE_MODS_EFFECTIVE) == 1 {
+			cmd.Modifiers |= key.ModAltGr
+		}
+		if C.xkb_state_mod_name_is_active(x.state, (*C.char)(unsafe.Pointer(&_XKB_MOD_NAME_CAPS[0])), C.XKB_STATE_MODS_EFFECTIVE) == 1 {
+			cmd.Modifiers |= key.ModCapsLock
+		}
+		if C.xkb_state_mod_name_is_active(x.state, (*C.char)(unsafe.Pointer(&_XKB_MOD_NAME_NUM[0])), C.XKB_STATE_MODS_EFFECTIVE) == 1 {
+			cmd.Modifiers |= key.ModNumLock
+		}
 		w.event(cmd)
 	}
 	C.xkb_compose_state_feed(x.compState, sym)
 	var size C.int
 	switch C.xkb_compose_state_get_status(x.compState) {
-	case C.XKB_COMPOSE_CANCELLED, C.XKB_COMPOSE_COMPOSING:
+	case C.XKB_COMPOSE_COMPOSING:
+		size = C.xkb_compose_state_get_utf8(x.compState, (*C.char)(unsafe.Pointer(&x.utf8Buf[0])), C.size_t(len(x.utf8Buf)))
+		if int(size) >= len(x.utf8Buf) {
+			x.utf8Buf = make([]byte, size+1)
+			size = C.xkb_compose_state_get_utf8(x.compState, (*C.char)(unsafe.Pointer(&x.utf8Buf[0])), C.size_t(len(x.utf8Buf)))
+		}
+		w.event(key.PreEditEvent{Text: string(x.utf8Buf[:size])})
+		return
+	case C.XKB_COMPOSE_CANCELLED:
+		w.event(key.PreEditEvent{})
 		return
 	case C.XKB_COMPOSE_COMPOSED:
 		size = C.xkb_compose_state_get_utf8(x.compState, (*C.char)(unsafe.Pointer(&x.utf8Buf[0])), C.size_t(len(x.utf8Buf)))
@@ -138,6 +170,7 @@ func (x *xkb) dispatchKey(w *Window, keyCode C.uint32_t) {
 			size = C.xkb_compose_state_get_utf8(x.compState, (*C.char)(unsafe.Pointer(&x.utf8Buf[0])), C.size_t(len(x.utf8Buf)))
 		}
 		C.xkb_compose_state_reset(x.compState)
+		w.event(key.PreEditEvent{})
 	case C.XKB_COMPOSE_NOTHING:
 		size = C.xkb_state_key_get_utf8(x.state, C.xkb_keycode_t(keyCode), (*C.char)(unsafe.Pointer(&x.utf8Buf[0])), C.size_t(len(x.utf8Buf)))
 		if int(size) >= len(x.utf8Buf) {
@@ -167,9 +200,13 @@ func (x *xkb) isRepeatKey(keyCode C.uint32_t) bool {
 	return C.xkb_keymap_key_repeats(conn.xkb.keyMap, C.xkb_keycode_t(keyCode)) == 1
 }
 
-func (x *xkb) updateMask(depressed, latched, locked, group C.uint32_t) {
+func (x *xkb) updateMask(w *Window, depressed, latched, locked, group C.uint32_t) {
 	xkbGrp := C.xkb_layout_index_t(group)
 	C.xkb_state_update_mask(conn.xkb.state, C.xkb_mod_mask_t(depressed), C.xkb_mod_mask_t(latched), C.xkb_mod_mask_t(locked), xkbGrp, xkbGrp, xkbGrp)
+	if xkbGrp != x.group {
+		x.group = xkbGrp
+		w.event(key.LayoutEvent{Group: int(xkbGrp)})
+	}
 }
 
 func mapXKBKeyCode(keyCode C.uint32_t) C.uint32_t {
@@ -0,0 +1,172 @@
+// SPDX-License-Identifier: Unlicense OR MIT
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// bindAndroid implements -buildmode=bind for -target android. It runs gobind
+// over bi.pkgPath to generate Java wrappers and JNI glue, compiles the glue
+// into a per-architecture libgojni.so, compiles the generated Java sources
+// into classes.jar and assembles the result as an .aar, the same format
+// produced by gomobile bind.
+func bindAndroid(tmpDir string, tools *androidTools, bi *buildInfo) (err error) {
+	gobind, err := exec.LookPath("gobind")
+	if err != nil {
+		return fmt.Errorf("could not find gobind: %v (run `go install golang.org/x/mobile/cmd/gobind@latest`)", err)
+	}
+	javac, err := findJavaC()
+	if err != nil {
+		return fmt.Errorf("could not find javac: %v", err)
+	}
+	androidHome := os.Getenv("ANDROID_SDK_ROOT")
+	if androidHome == "" {
+		return fmt.Errorf("ANDROID_SDK_ROOT is not set. Please point it to the root of the Android SDK")
+	}
+	ndkRoot, err := findNDK(androidHome)
+	if err != nil {
+		return err
+	}
+	minSDK := 17
+	if bi.minsdk > minSDK {
+		minSDK = bi.minsdk
+	}
+
+	bindDir := filepath.Join(tmpDir, "gobind")
+	if err := os.MkdirAll(bindDir, 0755); err != nil {
+		return err
+	}
+	gobindCmd := exec.Command(
+		gobind,
+		"-lang=go,java",
+		"-outdir="+bindDir,
+		"-javapkg="+bi.javaPkg,
+		bi.pkgPath,
+	)
+	if _, err := runCmd(gobindCmd); err != nil {
+		return err
+	}
+
+	tcRoot := filepath.Join(ndkRoot, "toolchains", "llvm", "prebuilt", archNDK())
+	var builds errgroup.Group
+	for _, a := range bi.archs {
+		a := a
+		arch := allArchs[a]
+		clang, err := latestCompiler(tcRoot, a, minSDK)
+		if err != nil {
+			return fmt.Errorf("%s. Please make sure you have NDK >= r19c installed. Use the command `sdkmanager ndk-bundle` to install it.", err)
+		}
+		archDir := filepath.Join(tmpDir, "jni", arch.jniArch)
+		if err := os.MkdirAll(archDir, 0755); err != nil {
+			return fmt.Errorf("failed to create %q: %v", archDir, err)
+		}
+		libFile := filepath.Join(archDir, "libgojni.so")
+		goArgs := []string{
+			"build",
+			"-ldflags=" + reproducibleLdflags("-w -s "+bi.ldflags),
+			"-buildmode=c-shared",
+			"-tags", bi.tags,
+		}
+		goArgs = append(goArgs, reproducibleGoArgs()...)
+		goArgs = append(goArgs, "-o", libFile, filepath.Join(bindDir, "androidlib"))
+		cmd := exec.Command("go", goArgs...)
+		cmd.Env = append(
+			os.Environ(),
+			"GOOS=android",
+			"GOARCH="+a,
+			"GOARM=7", // Avoid softfloat.
+			"CGO_ENABLED=1",
+			"CC="+clang,
+		)
+		builds.Go(func() error {
+			_, err := runCmd(cmd)
+			return err
+		})
+	}
+	if err := builds.Wait(); err != nil {
+		return err
+	}
+
+	javaSrc := filepath.Join(bindDir, "java")
+	supportSrc, err := runCmd(exec.Command("go", "list", "-f", "{{.Dir}}", "golang.org/x/mobile/bind/java"))
+	if err != nil {
+		return err
+	}
+	var javaFiles []string
+	err = filepath.Walk(javaSrc, func(path string, f os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if filepath.Ext(path) == ".java" {
+			javaFiles = append(javaFiles, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	supportFiles, err := filepath.Glob(filepath.Join(supportSrc, "*.java"))
+	if err != nil {
+		return err
+	}
+	javaFiles = append(javaFiles, supportFiles...)
+
+	classes := filepath.Join(tmpDir, "bind-classes")
+	if err := os.MkdirAll(classes, 0755); err != nil {
+		return err
+	}
+	javacCmd := exec.Command(
+		javac,
+		"-target", "1.8",
+		"-source", "1.8",
+		"-bootclasspath", tools.androidjar,
+		"-d", classes,
+	)
+	javacCmd.Args = append(javacCmd.Args, javaFiles...)
+	if _, err := runCmd(javacCmd); err != nil {
+		return err
+	}
+	jarFile := filepath.Join(tmpDir, "classes.jar")
+	if err := writeJar(jarFile, classes); err != nil {
+		return err
+	}
+
+	aarFile := *destPath
+	if aarFile == "" {
+		aarFile = fmt.Sprintf("%s.aar", bi.name)
+	}
+	if filepath.Ext(aarFile) != ".aar" {
+		return fmt.Errorf("the specified output %q does not end in '.aar'", aarFile)
+	}
+	aar, err := os.Create(aarFile)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if cerr := aar.Close(); err == nil {
+			err = cerr
+		}
+	}()
+	aarw := newZipWriter(aar)
+	defer aarw.Close()
+	aarw.Create("R.txt")
+	aarw.Add("classes.jar", jarFile)
+
+	manifest := aarw.Create("AndroidManifest.xml")
+	fmt.Fprintf(manifest, "<manifest xmlns:android=\"http://schemas.android.com/apk/res/android\" package=\"%s\">\n", bi.javaPkg)
+	fmt.Fprintf(manifest, "\t<uses-sdk android:minSdkVersion=\"%d\"/>\n", bi.minsdk)
+	fmt.Fprint(manifest, "</manifest>\n")
+
+	for _, a := range bi.archs {
+		arch := allArchs[a]
+		libFile := filepath.Join("jni", arch.jniArch, "libgojni.so")
+		aarw.Add(filepath.ToSlash(libFile), filepath.Join(tmpDir, libFile))
+	}
+	return aarw.Close()
+}
@@ -13,9 +13,11 @@ import (
 	"os/exec"
 	"path/filepath"
 	"runtime"
+	"sort"
 	"strconv"
 	"strings"
 	"text/template"
+	"time"
 
 	"golang.org/x/sync/errgroup"
 	"golang.org/x/tools/go/packages"
@@ -26,10 +28,12 @@ type androidTools struct {
 	androidjar string
 }
 
-// zip.Writer with a sticky error.
+// zip.Writer with a sticky error. Entries are buffered and only written out
+// on Close, see flush.
 type zipWriter struct {
-	err error
-	w   *zip.Writer
+	err     error
+	w       *zip.Writer
+	entries []*zipEntry
 }
 
 // Writer that saves any errors.
@@ -114,7 +118,7 @@ func buildAndroid(tmpDir string, bi *buildInfo) error {
 	if err != nil {
 		return err
 	}
-	var extraJars []string
+	var extraJars, extraKotlin, extraAars []string
 	visitedPkgs := make(map[string]bool)
 	var visitPkg func(*packages.Package) error
 	visitPkg = func(p *packages.Package) error {
@@ -127,6 +131,16 @@ func buildAndroid(tmpDir string, bi *buildInfo) error {
 			return err
 		}
 		extraJars = append(extraJars, jars...)
+		kotlin, err := filepath.Glob(filepath.Join(dir, "*.kt"))
+		if err != nil {
+			return err
+		}
+		extraKotlin = append(extraKotlin, kotlin...)
+		aars, err := filepath.Glob(filepath.Join(dir, "*.aar"))
+		if err != nil {
+			return err
+		}
+		extraAars = append(extraAars, aars...)
 		switch {
 		case p.PkgPath == "net":
 			perms = append(perms, "network")
@@ -145,8 +159,21 @@ func buildAndroid(tmpDir string, bi *buildInfo) error {
 	if err := visitPkg(pkgs[0]); err != nil {
 		return err
 	}
+	for _, aar := range extraAars {
+		jar, err := unpackAar(tmpDir, aar)
+		if err != nil {
+			return fmt.Errorf("failed to unpack %s: %v", aar, err)
+		}
+		if jar != "" {
+			extraJars = append(extraJars, jar)
+		}
+	}
 
-	if err := compileAndroid(tmpDir, tools, bi); err != nil {
+	if *buildMode == "bind" {
+		return bindAndroid(tmpDir, tools, bi)
+	}
+
+	if err := compileAndroid(tmpDir, tools, bi, extraKotlin); err != nil {
 		return err
 	}
 	switch *buildMode {
@@ -179,7 +206,7 @@ func buildAndroid(tmpDir string, bi *buildInfo) error {
 	}
 }
 
-func compileAndroid(tmpDir string, tools *androidTools, bi *buildInfo) (err error) {
+func compileAndroid(tmpDir string, tools *androidTools, bi *buildInfo, extraKotlin []string) (err error) {
 	androidHome := os.Getenv("ANDROID_SDK_ROOT")
 	if androidHome == "" {
 		return errors.New("ANDROID_SDK_ROOT is not set. Please point it to the root of the Android SDK")
@@ -218,15 +245,15 @@ func compileAndroid(tmpDir string, tools *androidTools, bi *buildInfo) (err erro
 			return fmt.Errorf("failed to create %q: %v", archDir, err)
 		}
 		libFile := filepath.Join(archDir, "libgio.so")
-		cmd := exec.Command(
-			"go",
+		goArgs := []string{
 			"build",
-			"-ldflags=-w -s "+bi.ldflags,
+			"-ldflags=" + reproducibleLdflags("-w -s "+bi.ldflags),
 			"-buildmode=c-shared",
 			"-tags", bi.tags,
-			"-o", libFile,
-			bi.pkgPath,
-		)
+		}
+		goArgs = append(goArgs, reproducibleGoArgs()...)
+		goArgs = append(goArgs, "-o", libFile, bi.pkgPath)
+		cmd := exec.Command("go", goArgs...)
 		cmd.Env = append(
 			os.Environ(),
 			"GOOS=android",
@@ -267,9 +294,84 @@ func compileAndroid(tmpDir string, tools *androidTools, bi *buildInfo) (err erro
 			return err
 		})
 	}
+	if len(extraKotlin) > 0 {
+		kotlinc, err := findKotlinC()
+		if err != nil {
+			return fmt.Errorf("could not find kotlinc: %v", err)
+		}
+		classes := filepath.Join(tmpDir, "classes")
+		if err := os.MkdirAll(classes, 0755); err != nil {
+			return err
+		}
+		cmd := exec.Command(
+			kotlinc,
+			"-classpath", tools.androidjar,
+			"-d", classes,
+		)
+		cmd.Args = append(cmd.Args, extraKotlin...)
+		builds.Go(func() error {
+			_, err := runCmd(cmd)
+			return err
+		})
+	}
 	return builds.Wait()
 }
 
+// unpackAar extracts classes.jar and any jni/<abi>/*.so libraries from a
+// precompiled Android library dependency into tmpDir, so they can be merged
+// into the final APK/AAR alongside the Go-built libraries. It returns the
+// path to the extracted classes.jar, or the empty string if the AAR has none.
+func unpackAar(tmpDir, aarFile string) (classesJar string, err error) {
+	r, err := zip.OpenReader(aarFile)
+	if err != nil {
+		return "", err
+	}
+	defer r.Close()
+	outDir := filepath.Join(tmpDir, "aars", strings.TrimSuffix(filepath.Base(aarFile), filepath.Ext(aarFile)))
+	for _, f := range r.File {
+		switch {
+		case f.Name == "classes.jar":
+			classesJar = filepath.Join(outDir, f.Name)
+		case strings.HasPrefix(f.Name, "jni/") && strings.HasSuffix(f.Name, ".so"):
+			// Keep as-is; extracted below into outDir/jni/<abi>/*.so and
+			// then copied into tmpDir/jni/<abi> by the caller.
+		default:
+			continue
+		}
+		dst := filepath.Join(outDir, filepath.FromSlash(f.Name))
+		if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+			return "", err
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return "", err
+		}
+		w, err := os.Create(dst)
+		if err != nil {
+			rc.Close()
+			return "", err
+		}
+		_, err = io.Copy(w, rc)
+		rc.Close()
+		if cerr := w.Close(); err == nil {
+			err = cerr
+		}
+		if err != nil {
+			return "", err
+		}
+		if strings.HasPrefix(f.Name, "jni/") && strings.HasSuffix(f.Name, ".so") {
+			abiDir := filepath.Join(tmpDir, "jni", filepath.Base(filepath.Dir(dst)))
+			if err := os.MkdirAll(abiDir, 0755); err != nil {
+				return "", err
+			}
+			if err := copyFile(filepath.Join(abiDir, filepath.Base(dst)), dst); err != nil {
+				return "", err
+			}
+		}
+	}
+	return classesJar, nil
+}
+
 func archiveAndroid(tmpDir string, bi *buildInfo, perms []string) (err error) {
 	aarFile := *destPath
 	if aarFile == "" {
@@ -319,8 +421,14 @@ func archiveAndroid(tmpDir string, bi *buildInfo, perms []string) (err error) {
 
 	for _, a := range bi.archs {
 		arch := allArchs[a]
-		libFile := filepath.Join("jni", arch.jniArch, "libgio.so")
-		aarw.Add(filepath.ToSlash(libFile), filepath.Join(tmpDir, libFile))
+		libs, err := filepath.Glob(filepath.Join(tmpDir, "jni", arch.jniArch, "*.so"))
+		if err != nil {
+			return err
+		}
+		for _, lib := range libs {
+			libFile := filepath.Join("jni", arch.jniArch, filepath.Base(lib))
+			aarw.Add(filepath.ToSlash(libFile), lib)
+		}
 	}
 	classes := filepath.Join(tmpDir, "classes")
 	if _, err := os.Stat(classes); err == nil {
@@ -382,7 +490,10 @@ func exeAndroid(tmpDir string, tools *androidTools, bi *buildInfo, extraJars, pe
 		if err != nil {
 			return err
 		}
-		iconSnip = `android:icon="@mipmap/ic_launcher"`
+		if err := buildAdaptiveIcon(resDir, bi.iconPath); err != nil {
+			return err
+		}
+		iconSnip = `android:icon="@mipmap/ic_launcher" android:roundIcon="@mipmap/ic_launcher"`
 	}
 	err = ioutil.WriteFile(filepath.Join(valDir, "themes.xml"), []byte(themes), 0660)
 	if err != nil {
@@ -499,32 +610,33 @@ func exeAndroid(tmpDir string, tools *androidTools, bi *buildInfo, extraJars, pe
 	unsignedAPKZip := zip.NewWriter(unsignedAPKFile)
 	defer unsignedAPKZip.Close()
 
+	// Buffer every entry instead of streaming straight to the zip writer,
+	// so that a -reproducible build can sort them by name below: aapt2's
+	// link.apk ordering, the jni/<abi> glob order and the dex directory
+	// walk aren't guaranteed to be stable across machines or Go versions.
+	var apkEntries []*zipEntry
+
 	// Copy files from linkAPK to unsignedAPK.
 	for _, f := range linkAPKZip.File {
-		header := zip.FileHeader{
-			Name:   f.FileHeader.Name,
-			Method: f.FileHeader.Method,
-		}
-
+		name := f.FileHeader.Name
 		if isBundle {
 			// AAB have pre-defined folders.
-			switch header.Name {
+			switch name {
 			case "AndroidManifest.xml":
-				header.Name = "manifest/AndroidManifest.xml"
+				name = "manifest/AndroidManifest.xml"
 			}
 		}
-
-		w, err := unsignedAPKZip.CreateHeader(&header)
-		if err != nil {
-			return err
-		}
+		e := &zipEntry{name: name, compressed: f.FileHeader.Method == zip.Deflate}
 		r, err := f.Open()
 		if err != nil {
 			return err
 		}
-		if _, err := io.Copy(w, r); err != nil {
+		_, err = io.Copy(&e.buf, r)
+		r.Close()
+		if err != nil {
 			return err
 		}
+		apkEntries = append(apkEntries, e)
 	}
 
 	// Append new files (that doesn't exists inside the link.apk).
@@ -534,23 +646,31 @@ func exeAndroid(tmpDir string, tools *androidTools, bi *buildInfo, extraJars, pe
 			return err
 		}
 		defer f.Close()
-		w, err := unsignedAPKZip.CreateHeader(&zip.FileHeader{
-			Name:   filepath.ToSlash(path),
-			Method: zip.Deflate,
-		})
-		if err != nil {
+		e := &zipEntry{name: filepath.ToSlash(path), compressed: true}
+		if _, err := io.Copy(&e.buf, f); err != nil {
 			return err
 		}
-		_, err = io.Copy(w, f)
-		return err
+		apkEntries = append(apkEntries, e)
+		return nil
 	}
 
-	// Append Go binaries (libgio.so).
-	for _, a := range bi.archs {
-		arch := allArchs[a]
-		libFile := filepath.Join(arch.jniArch, "libgio.so")
-		if err := appendToZip(filepath.Join("lib", libFile), filepath.Join(tmpDir, "jni", libFile)); err != nil {
-			return err
+	// Append Go binaries (libgio.so) and any .so files bundled by AAR
+	// dependencies. For app bundles, native libraries instead go into their
+	// own per-ABI config split modules (see writeAbiSplitModules) so the
+	// Play Store can serve only the split matching a device's ABI.
+	if !isBundle {
+		for _, a := range bi.archs {
+			arch := allArchs[a]
+			libs, err := filepath.Glob(filepath.Join(tmpDir, "jni", arch.jniArch, "*.so"))
+			if err != nil {
+				return err
+			}
+			for _, lib := range libs {
+				libFile := filepath.Join(arch.jniArch, filepath.Base(lib))
+				if err := appendToZip(filepath.Join("lib", libFile), lib); err != nil {
+					return err
+				}
+			}
 		}
 	}
 
@@ -563,7 +683,36 @@ func exeAndroid(tmpDir string, tools *androidTools, bi *buildInfo, extraJars, pe
 		return err
 	}
 
-	return unsignedAPKZip.Close()
+	if *reproducible {
+		sort.Slice(apkEntries, func(i, j int) bool { return apkEntries[i].name < apkEntries[j].name })
+	}
+	mtime := zipModTime()
+	for _, e := range apkEntries {
+		fh := &zip.FileHeader{Name: e.name, Modified: mtime}
+		if e.compressed {
+			fh.Method = zip.Deflate
+		}
+		fh.SetMode(zipFileMode(e.name))
+		w, err := unsignedAPKZip.CreateHeader(fh)
+		if err != nil {
+			return err
+		}
+		if _, err := w.Write(e.buf.Bytes()); err != nil {
+			return err
+		}
+	}
+
+	if err := unsignedAPKZip.Close(); err != nil {
+		return err
+	}
+
+	if isBundle {
+		if err := writeAbiSplitModules(tmpDir, bi); err != nil {
+			return err
+		}
+		return writeBundleConfig(tmpDir)
+	}
+	return nil
 }
 
 func signAPK(tmpDir string, apkFile string, tools *androidTools, bi *buildInfo) error {
@@ -577,15 +726,81 @@ func signAPK(tmpDir string, apkFile string, tools *androidTools, bi *buildInfo)
 		}
 	}
 
-	_, err := runCmd(exec.Command(
-		filepath.Join(tools.buildtools, "apksigner"),
+	alias := bi.keyAlias
+	if alias == "" {
+		var err error
+		alias, err = detectKeyAlias(bi)
+		if err != nil {
+			return err
+		}
+	}
+
+	args := []string{
 		"sign",
-		"--ks-pass", "pass:"+bi.password,
+		"--ks-pass", "pass:" + bi.password,
 		"--ks", bi.key,
-		apkFile,
-	))
+		"--ks-key-alias", alias,
+	}
+	if bi.keyPass != "" {
+		args = append(args, "--key-pass", "pass:"+bi.keyPass)
+	}
+	for _, scheme := range []string{"v1", "v2", "v3", "v4"} {
+		enabled := "false"
+		for _, s := range bi.schemes {
+			if strings.TrimSpace(s) == scheme {
+				enabled = "true"
+				break
+			}
+		}
+		args = append(args, fmt.Sprintf("--%s-signing-enabled", scheme), enabled)
+	}
+	args = append(args, apkFile)
 
-	return err
+	if _, err := runCmd(exec.Command(filepath.Join(tools.buildtools, "apksigner"), args...)); err != nil {
+		return err
+	}
+
+	for _, s := range bi.schemes {
+		if strings.TrimSpace(s) != "v4" {
+			continue
+		}
+		if bi.v4Sig != "" {
+			return os.Rename(apkFile+".idsig", bi.v4Sig)
+		}
+		break
+	}
+	return nil
+}
+
+// detectKeyAlias returns the alias of the signing key to use for bi.key. If
+// bi.keyAlias is unset, it lists the keystore's entries with `keytool -list`
+// and uses the alias if there is exactly one, to avoid a cryptic apksigner
+// or jarsigner failure when the wrong default alias is guessed.
+func detectKeyAlias(bi *buildInfo) (string, error) {
+	if bi.keyAlias != "" {
+		return bi.keyAlias, nil
+	}
+	keytoolList, err := runCmd(exec.Command(
+		"keytool",
+		"-keystore", bi.key,
+		"-list",
+		"-keypass", bi.password,
+		"-v",
+	))
+	if err != nil {
+		return "", err
+	}
+	var alias string
+	var aliases []string
+	for _, t := range strings.Split(keytoolList, "\n") {
+		if i, _ := fmt.Sscanf(t, "Alias name: %s", &alias); i > 0 {
+			aliases = append(aliases, alias)
+		}
+	}
+	if len(aliases) != 1 {
+		return "", fmt.Errorf("could not auto-detect a signing key alias in %s: found %d entries, specify -key-alias", bi.key, len(aliases))
+	}
+	return aliases[0], nil
 }
 
 func signAAB(tmpDir string, aabFile string, tools *androidTools, bi *buildInfo) error {
@@ -604,11 +819,19 @@ func signAAB(tmpDir string, aabFile string, tools *androidTools, bi *buildInfo)
 		return fmt.Errorf("bundletool was not found at %s. Download it from https://github.com/google/bundletool/releases and move to the respective folder", tools.buildtools)
 	}
 
+	modules := []string{filepath.Join(tmpDir, "app.zip")}
+	abiModules, err := filepath.Glob(filepath.Join(tmpDir, "abi.*.zip"))
+	if err != nil {
+		return err
+	}
+	modules = append(modules, abiModules...)
+
 	_, err = runCmd(exec.Command(
 		"java",
 		"-jar", bundletool,
 		"build-bundle",
-		"--modules="+filepath.Join(tmpDir, "app.zip"),
+		"--modules="+strings.Join(modules, ","),
+		"--config="+filepath.Join(tmpDir, "BundleConfig.pb"),
 		"--output="+filepath.Join(tmpDir, "app.aab"),
 	))
 	if err != nil {
@@ -625,24 +848,11 @@ func signAAB(tmpDir string, aabFile string, tools *androidTools, bi *buildInfo)
 		}
 	}
 
-	keytoolList, err := runCmd(exec.Command(
-		"keytool",
-		"-keystore", bi.key,
-		"-list",
-		"-keypass", bi.password,
-		"-v",
-	))
+	alias, err := detectKeyAlias(bi)
 	if err != nil {
 		return err
 	}
 
-	var alias string
-	for _, t := range strings.Split(keytoolList, "\n") {
-		if i, _ := fmt.Sscanf(t, "Alias name: %s", &alias); i > 0 {
-			break
-		}
-	}
-
 	_, err = runCmd(exec.Command(
 		filepath.Join("jarsigner"),
 		"-sigalg", "SHA256withRSA",
@@ -756,6 +966,22 @@ func findJavaC() (string, error) {
 	return "", err
 }
 
+func findKotlinC() (string, error) {
+	kotlinc, err := exec.LookPath("kotlinc")
+	if err == nil {
+		return kotlinc, err
+	}
+	kotlinHome := os.Getenv("KOTLIN_HOME")
+	if kotlinHome == "" {
+		return "", err
+	}
+	kotlinc = filepath.Join(kotlinHome, "bin", "kotlinc"+exeSuffix)
+	if _, serr := os.Stat(kotlinc); serr == nil {
+		return kotlinc, nil
+	}
+	return "", err
+}
+
 func writeJar(jarFile, dir string) (err error) {
 	jar, err := os.Create(jarFile)
 	if err != nil {
@@ -936,6 +1162,15 @@ loop:
 	return bestDir, bestDir != ""
 }
 
+// zipEntry is a pending zipWriter entry. Entries are buffered in memory and
+// only handed to the underlying zip.Writer on Close, so that -reproducible
+// builds can write them out in a fixed, content-independent order.
+type zipEntry struct {
+	name       string
+	buf        bytes.Buffer
+	compressed bool
+}
+
 func newZipWriter(w io.Writer) *zipWriter {
 	return &zipWriter{
 		w: zip.NewWriter(w),
@@ -943,23 +1178,52 @@ func newZipWriter(w io.Writer) *zipWriter {
 }
 
 func (z *zipWriter) Close() error {
-	err := z.w.Close()
 	if z.err == nil {
+		z.err = z.flush()
+	}
+	if err := z.w.Close(); z.err == nil {
 		z.err = err
 	}
 	return z.err
 }
 
+// flush writes all buffered entries to the underlying zip.Writer, each with
+// a deterministic mtime (see zipModTime) and normalized permission bits. In
+// -reproducible mode, entries are sorted by name so the resulting archive
+// doesn't depend on the order packages.Load or filepath.Glob visited files.
+func (z *zipWriter) flush() error {
+	entries := z.entries
+	if *reproducible {
+		sort.Slice(entries, func(i, j int) bool { return entries[i].name < entries[j].name })
+	}
+	mtime := zipModTime()
+	for _, e := range entries {
+		fh := &zip.FileHeader{
+			Name:     e.name,
+			Modified: mtime,
+		}
+		if e.compressed {
+			fh.Method = zip.Deflate
+		}
+		fh.SetMode(zipFileMode(e.name))
+		w, err := z.w.CreateHeader(fh)
+		if err != nil {
+			return err
+		}
+		if _, err := w.Write(e.buf.Bytes()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (z *zipWriter) Create(name string) io.Writer {
 	if z.err != nil {
 		return ioutil.Discard
 	}
-	w, err := z.w.Create(name)
-	if err != nil {
-		z.err = err
-		return ioutil.Discard
-	}
-	return &errWriter{w: w, err: &z.err}
+	e := &zipEntry{name: name, compressed: true}
+	z.entries = append(z.entries, e)
+	return &errWriter{w: &e.buf, err: &z.err}
 }
 
 func (z *zipWriter) Store(name, file string) {
@@ -980,21 +1244,58 @@ func (z *zipWriter) add(name, file string, compressed bool) {
 		return
 	}
 	defer f.Close()
-	fh := &zip.FileHeader{
-		Name: name,
-	}
-	if compressed {
-		fh.Method = zip.Deflate
-	}
-	w, err := z.w.CreateHeader(fh)
-	if err != nil {
+	e := &zipEntry{name: name, compressed: compressed}
+	if _, err := io.Copy(&e.buf, f); err != nil {
 		z.err = err
 		return
 	}
-	if _, err := io.Copy(w, f); err != nil {
-		z.err = err
-		return
+	z.entries = append(z.entries, e)
+}
+
+// zipModTime returns the timestamp reproducible builds stamp on every zip
+// entry. It honors SOURCE_DATE_EPOCH (as seconds since the Unix epoch, per
+// the https://reproducible-builds.org/specs/source-date-epoch/ convention),
+// falling back to the oldest timestamp the zip format supports.
+func zipModTime() time.Time {
+	if !*reproducible {
+		return time.Now()
+	}
+	if epoch := os.Getenv("SOURCE_DATE_EPOCH"); epoch != "" {
+		if secs, err := strconv.ParseInt(epoch, 10, 64); err == nil {
+			return time.Unix(secs, 0).UTC()
+		}
+	}
+	return time.Date(1980, time.January, 1, 0, 0, 0, 0, time.UTC)
+}
+
+// zipFileMode normalizes permission bits so they don't depend on the host's
+// umask: native libraries need to be executable, everything else is a plain
+// non-executable file.
+func zipFileMode(name string) os.FileMode {
+	if strings.HasSuffix(name, ".so") {
+		return 0755
+	}
+	return 0644
+}
+
+// reproducibleGoArgs returns the extra `go build` flags that strip
+// machine-specific inputs, such as absolute source paths, from the
+// resulting binary.
+func reproducibleGoArgs() []string {
+	if !*reproducible {
+		return nil
+	}
+	return []string{"-trimpath"}
+}
+
+// reproducibleLdflags appends a linker flag clearing the build id Go
+// otherwise embeds in every binary, which would make libgio.so differ
+// between two builds of identical source.
+func reproducibleLdflags(ldflags string) string {
+	if !*reproducible {
+		return ldflags
 	}
+	return ldflags + " -buildid="
 }
 
 func (w *errWriter) Write(p []byte) (n int, err error) {
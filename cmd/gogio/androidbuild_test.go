@@ -0,0 +1,111 @@
+// SPDX-License-Identifier: Unlicense OR MIT
+
+package main
+
+import (
+	"archive/zip"
+	"bytes"
+	"testing"
+)
+
+// zipBytes builds a zip with newZipWriter from entries added in the given
+// order and returns the resulting archive.
+func zipBytes(t *testing.T, entries map[string]string, order []string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	z := newZipWriter(&buf)
+	for _, name := range order {
+		w := z.Create(name)
+		if _, err := w.Write([]byte(entries[name])); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := z.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// TestZipWriterReproducible asserts that, with -reproducible set, the same
+// entries zipped in two different append orders produce byte-identical
+// archives: that's the entire point of the feature, and a regression here
+// would silently reintroduce non-determinism.
+func TestZipWriterReproducible(t *testing.T) {
+	old := *reproducible
+	*reproducible = true
+	defer func() { *reproducible = old }()
+
+	entries := map[string]string{
+		"classes.dex":         "dex bytes",
+		"lib/arm64-v8a.so":    "native bytes",
+		"AndroidManifest.xml": "manifest bytes",
+	}
+	gotA := zipBytes(t, entries, []string{"classes.dex", "lib/arm64-v8a.so", "AndroidManifest.xml"})
+	gotB := zipBytes(t, entries, []string{"AndroidManifest.xml", "lib/arm64-v8a.so", "classes.dex"})
+	if !bytes.Equal(gotA, gotB) {
+		t.Fatalf("zips built from the same entries in different orders differ")
+	}
+}
+
+// TestZipWriterReproducibleContents asserts the reproducible archive still
+// contains the entries that were added to it, with the normalized mtime and
+// mode reproducibility requires.
+func TestZipWriterReproducibleContents(t *testing.T) {
+	old := *reproducible
+	*reproducible = true
+	defer func() { *reproducible = old }()
+
+	data := zipBytes(t, map[string]string{
+		"lib/arm64-v8a/libgio.so": "native",
+		"res/values.xml":          "values",
+	}, []string{"res/values.xml", "lib/arm64-v8a/libgio.so"})
+
+	r, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	byName := make(map[string]*zip.File)
+	for _, f := range r.File {
+		byName[f.Name] = f
+	}
+	so, ok := byName["lib/arm64-v8a/libgio.so"]
+	if !ok {
+		t.Fatal("missing lib/arm64-v8a/libgio.so entry")
+	}
+	if so.Mode() != zipFileMode(so.Name) {
+		t.Errorf("libgio.so mode is %v, want %v", so.Mode(), zipFileMode(so.Name))
+	}
+	if !so.Modified.Equal(zipModTime()) {
+		t.Errorf("libgio.so mtime is %v, want %v", so.Modified, zipModTime())
+	}
+}
+
+func TestZipFileMode(t *testing.T) {
+	if zipFileMode("lib/arm64-v8a/libgio.so") != 0755 {
+		t.Errorf(".so files should be executable")
+	}
+	if zipFileMode("res/values.xml") != 0644 {
+		t.Errorf("non-.so files should not be executable")
+	}
+}
+
+func TestReproducibleGoArgsAndLdflags(t *testing.T) {
+	old := *reproducible
+	defer func() { *reproducible = old }()
+
+	*reproducible = true
+	if args := reproducibleGoArgs(); len(args) == 0 {
+		t.Error("reproducibleGoArgs returned nothing with -reproducible set")
+	}
+	if got := reproducibleLdflags("-w -s"); got == "-w -s" {
+		t.Error("reproducibleLdflags did not append -buildid= with -reproducible set")
+	}
+
+	*reproducible = false
+	if args := reproducibleGoArgs(); len(args) != 0 {
+		t.Errorf("reproducibleGoArgs returned %v without -reproducible set, want none", args)
+	}
+	if got, want := reproducibleLdflags("-w -s"), "-w -s"; got != want {
+		t.Errorf("reproducibleLdflags changed ldflags to %q without -reproducible set, want unchanged %q", got, want)
+	}
+}
@@ -0,0 +1,188 @@
+// SPDX-License-Identifier: Unlicense OR MIT
+
+package main
+
+import (
+	"archive/zip"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+// writeAbiSplitModules writes one Android App Bundle module per target ABI,
+// each containing only that ABI's native library. Keeping native libraries
+// out of the base module and in their own "abi.<abi>" config splits lets the
+// Play Store serve a device only the .so it can run, instead of every ABI
+// gogio built.
+func writeAbiSplitModules(tmpDir string, bi *buildInfo) error {
+	for _, a := range bi.archs {
+		arch := allArchs[a]
+		libs, err := filepath.Glob(filepath.Join(tmpDir, "jni", arch.jniArch, "*.so"))
+		if err != nil {
+			return err
+		}
+		if len(libs) == 0 {
+			continue
+		}
+		moduleName := "abi." + arch.jniArch
+		moduleFile := filepath.Join(tmpDir, moduleName+".zip")
+		f, err := os.Create(moduleFile)
+		if err != nil {
+			return err
+		}
+		w := zip.NewWriter(f)
+		manifest, err := abiSplitManifest(bi.appID, moduleName, arch.jniArch)
+		if err != nil {
+			w.Close()
+			f.Close()
+			return err
+		}
+		mtime := zipModTime()
+		createEntry := func(name string) (io.Writer, error) {
+			fh := &zip.FileHeader{
+				Name:     name,
+				Method:   zip.Deflate,
+				Modified: mtime,
+			}
+			fh.SetMode(zipFileMode(name))
+			return w.CreateHeader(fh)
+		}
+		mw, err := createEntry("manifest/AndroidManifest.xml")
+		if err != nil {
+			w.Close()
+			f.Close()
+			return err
+		}
+		if _, err := mw.Write(manifest); err != nil {
+			w.Close()
+			f.Close()
+			return err
+		}
+		for _, lib := range libs {
+			lw, err := createEntry(filepath.ToSlash(filepath.Join("lib", arch.jniArch, filepath.Base(lib))))
+			if err != nil {
+				w.Close()
+				f.Close()
+				return err
+			}
+			libData, err := ioutil.ReadFile(lib)
+			if err != nil {
+				w.Close()
+				f.Close()
+				return err
+			}
+			if _, err := lw.Write(libData); err != nil {
+				w.Close()
+				f.Close()
+				return err
+			}
+		}
+		if err := w.Close(); err != nil {
+			f.Close()
+			return err
+		}
+		if err := f.Close(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// abiSplitManifest builds the minimal manifest bundletool expects for a
+// config split: it names the split, marks it as always fused into the base
+// APK for pre-Lollipop devices, and carries no content of its own beyond
+// what its module zip provides.
+func abiSplitManifest(appID, moduleName, abi string) ([]byte, error) {
+	manifest := fmt.Sprintf(`<?xml version="1.0" encoding="utf-8"?>
+<manifest xmlns:android="http://schemas.android.com/apk/res/android"
+	xmlns:dist="http://schemas.android.com/apk/distribution"
+	package="%s"
+	split="config.%s">
+	<dist:module dist:title="%s">
+		<dist:delivery>
+			<dist:install-time/>
+		</dist:delivery>
+		<dist:fusing dist:include="true"/>
+	</dist:module>
+	<application android:hasCode="false"/>
+</manifest>
+`, appID, moduleName, moduleName)
+	return []byte(manifest), nil
+}
+
+// writeBundleConfig writes the BundleConfig.pb bundletool uses to configure
+// how it splits the bundle's base module at serving time. It enables ABI,
+// screen-density, and language splitting so the Play Store generates
+// per-device APKs instead of one universal APK containing every resource
+// and native library.
+//
+// This is a minimal hand-encoding of the subset of bundletool's
+// BundleConfig proto (see aapt2/bundletool's config.proto) needed here,
+// rather than pulling in a full protobuf implementation for three fields.
+func writeBundleConfig(tmpDir string) error {
+	const (
+		dimensionABI           = 1
+		dimensionScreenDensity = 2
+		dimensionLanguage      = 3
+	)
+	splitDimension := func(value int) []byte {
+		var b protoBuf
+		b.varint(1, uint64(value)) // SplitDimension.value (enum)
+		return b.bytes()
+	}
+	var splitsConfig protoBuf
+	splitsConfig.bytes_(1, splitDimension(dimensionABI))
+	splitsConfig.bytes_(1, splitDimension(dimensionScreenDensity))
+	splitsConfig.bytes_(1, splitDimension(dimensionLanguage))
+
+	var optimizations protoBuf
+	optimizations.bytes_(1, splitsConfig.bytes()) // Optimizations.splits_config
+
+	var bundletool protoBuf
+	bundletool.string_(1, "1.0.0") // Bundletool.version
+
+	var cfg protoBuf
+	cfg.bytes_(1, bundletool.bytes())    // BundleConfig.bundletool
+	cfg.bytes_(2, optimizations.bytes()) // BundleConfig.optimizations
+
+	return ioutil.WriteFile(filepath.Join(tmpDir, "BundleConfig.pb"), cfg.bytes(), 0644)
+}
+
+// protoBuf accumulates a protobuf wire-format message using length-delimited
+// (bytes/string) and varint fields only, which is all BundleConfig needs here.
+type protoBuf struct {
+	buf []byte
+}
+
+func (p *protoBuf) tag(field int, wireType byte) {
+	p.uvarint(uint64(field)<<3 | uint64(wireType))
+}
+
+func (p *protoBuf) uvarint(v uint64) {
+	for v >= 0x80 {
+		p.buf = append(p.buf, byte(v)|0x80)
+		v >>= 7
+	}
+	p.buf = append(p.buf, byte(v))
+}
+
+func (p *protoBuf) varint(field int, v uint64) {
+	p.tag(field, 0)
+	p.uvarint(v)
+}
+
+func (p *protoBuf) bytes_(field int, v []byte) {
+	p.tag(field, 2)
+	p.uvarint(uint64(len(v)))
+	p.buf = append(p.buf, v...)
+}
+
+func (p *protoBuf) string_(field int, s string) {
+	p.bytes_(field, []byte(s))
+}
+
+func (p *protoBuf) bytes() []byte {
+	return p.buf
+}
@@ -0,0 +1,129 @@
+// SPDX-License-Identifier: Unlicense OR MIT
+
+package main
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"golang.org/x/image/draw"
+)
+
+// Adaptive icons are defined on a 108dp canvas, of which only the inner
+// 66dp "safe zone" is guaranteed to be visible once the launcher applies its
+// mask shape. The legacy launcher icon variants built by buildIcons cover a
+// 48dp canvas at full bleed, so the adaptive canvas is 108/48 as large.
+const (
+	adaptiveDp = 108
+	legacyDp   = 48
+	safeZoneDp = 66
+)
+
+var adaptiveDensities = []struct {
+	dir  string
+	size int
+}{
+	{"mipmap-hdpi", 72 * adaptiveDp / legacyDp},
+	{"mipmap-xhdpi", 96 * adaptiveDp / legacyDp},
+	{"mipmap-xxhdpi", 144 * adaptiveDp / legacyDp},
+	{"mipmap-xxxhdpi", 192 * adaptiveDp / legacyDp},
+}
+
+// buildAdaptiveIcon generates the API 26+ adaptive icon layers (foreground,
+// background and the API 33 themed monochrome mask) plus the
+// mipmap-anydpi-v26/ic_launcher.xml that ties them together, alongside the
+// legacy square icons written by buildIcons.
+func buildAdaptiveIcon(resDir, icon string) error {
+	f, err := os.Open(icon)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	src, _, err := image.Decode(f)
+	if err != nil {
+		return err
+	}
+	bg, err := parseHexColor(*iconBG)
+	if err != nil {
+		return fmt.Errorf("invalid -icon-background %q: %v", *iconBG, err)
+	}
+
+	for _, d := range adaptiveDensities {
+		foreground := image.NewNRGBA(image.Rectangle{Max: image.Point{X: d.size, Y: d.size}})
+		safeZone := d.size * safeZoneDp / adaptiveDp
+		offset := (d.size - safeZone) / 2
+		dst := image.Rectangle{
+			Min: image.Point{X: offset, Y: offset},
+			Max: image.Point{X: offset + safeZone, Y: offset + safeZone},
+		}
+		draw.CatmullRom.Scale(foreground, dst, src, src.Bounds(), draw.Over, nil)
+		if err := writePNG(filepath.Join(resDir, d.dir, "ic_launcher_foreground.png"), foreground); err != nil {
+			return err
+		}
+
+		background := image.NewNRGBA(image.Rectangle{Max: image.Point{X: d.size, Y: d.size}})
+		draw.Draw(background, background.Bounds(), &image.Uniform{bg}, image.Point{}, draw.Src)
+		if err := writePNG(filepath.Join(resDir, d.dir, "ic_launcher_background.png"), background); err != nil {
+			return err
+		}
+
+		if err := writePNG(filepath.Join(resDir, d.dir, "ic_launcher_monochrome.png"), monochromeMask(foreground)); err != nil {
+			return err
+		}
+	}
+
+	xml := `<?xml version="1.0" encoding="utf-8"?>
+<adaptive-icon xmlns:android="http://schemas.android.com/apk/res/android">
+	<background android:drawable="@mipmap/ic_launcher_background"/>
+	<foreground android:drawable="@mipmap/ic_launcher_foreground"/>
+	<monochrome android:drawable="@mipmap/ic_launcher_monochrome"/>
+</adaptive-icon>
+`
+	dir := filepath.Join(resDir, "mipmap-anydpi-v26")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filepath.Join(dir, "ic_launcher.xml"), []byte(xml), 0660)
+}
+
+// monochromeMask turns the foreground artwork into the single-color alpha
+// mask the themed-icon feature (Android 13+) tints at runtime: white,
+// modulated by the source pixel's luminance and alpha.
+func monochromeMask(src *image.NRGBA) *image.NRGBA {
+	bounds := src.Bounds()
+	mask := image.NewNRGBA(bounds)
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			c := src.NRGBAAt(x, y)
+			lum := (299*uint32(c.R) + 587*uint32(c.G) + 114*uint32(c.B)) / 1000
+			a := uint8(lum * uint32(c.A) / 255)
+			mask.SetNRGBA(x, y, color.NRGBA{R: 255, G: 255, B: 255, A: a})
+		}
+	}
+	return mask
+}
+
+func writePNG(path string, img image.Image) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return png.Encode(f, img)
+}
+
+func parseHexColor(s string) (color.Color, error) {
+	var r, g, b uint8
+	if _, err := fmt.Sscanf(s, "#%02x%02x%02x", &r, &g, &b); err != nil {
+		return nil, err
+	}
+	return color.NRGBA{R: r, G: g, B: b, A: 255}, nil
+}
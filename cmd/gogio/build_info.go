@@ -25,6 +25,11 @@ type buildInfo struct {
 	version  int
 	key      string
 	password string
+	javaPkg  string
+	schemes  []string
+	keyAlias string
+	keyPass  string
+	v4Sig    string
 }
 
 func newBuildInfo(pkgPath string) (*buildInfo, error) {
@@ -51,6 +56,11 @@ func newBuildInfo(pkgPath string) (*buildInfo, error) {
 		version:  *version,
 		key:      *signKey,
 		password: *signPass,
+		javaPkg:  *javaPkg,
+		schemes:  strings.Split(*signScheme, ","),
+		keyAlias: *keyAlias,
+		keyPass:  *keyPass,
+		v4Sig:    *v4SigFile,
 	}
 	return bi, nil
 }
@@ -38,10 +38,24 @@ will output an app directory suitable for a simulator.
 The other buildmode is archive, which will output an .aar library for Android
 or a .framework for iOS and tvOS.
 
+For -target android, the buildmode bind generates an .aar library exposing
+the package as callable Java classes, in the manner of gomobile bind. It
+requires the -javapkg flag to set the Java package for the generated
+bindings, and the gobind tool (golang.org/x/mobile/cmd/gobind) must be
+installed.
+
 The -icon flag specifies a path to a PNG image to use as app icon on iOS and Android.
 If left unspecified, the appicon.png file from the main package is used
 (if it exists).
 
+The -icon-background flag specifies the #RRGGBB background color for the
+Android adaptive icon generated alongside the legacy launcher icon.
+
+The -reproducible flag, enabled by default, makes Android archives
+byte-for-byte reproducible: zip entries get a fixed timestamp and are sorted
+by name, and Go binaries are built with -trimpath and no build id. The
+timestamp honors SOURCE_DATE_EPOCH when set.
+
 The -appid flag specifies the package name for Android or the bundle id for
 iOS and tvOS. A bundle id must be provisioned through Xcode before the gogio
 tool can use it.
@@ -58,5 +72,20 @@ use -mindk 10 to target Windows 10 only, -minsdk 6 for Windows Vista and later.
 The -work flag prints the path to the working directory and suppress
 its deletion.
 
+For -target ios or tvos, passing -tags pprof links in CPU, heap, block and
+mutex profiling. Profiles are flushed to the app's Documents directory
+whenever the app is backgrounded or about to terminate, and on receipt of
+SIGUSR1. The -pprof-cpu and -pprof-mem flags override the default profile
+filenames.
+
+For -target ios or tvos, the -adhoc flag signs the app ad hoc instead of
+searching for a provisioning profile matching -appid; this is implied for
+simulator builds, which cannot use a device provisioning profile. The
+-entitlements flag gives the path to an entitlements plist to use for ad
+hoc signing, instead of a minimal default. The -identity flag selects a
+signing identity by its SHA-1 hash, as reported by
+"security find-identity -v -p codesigning", instead of deriving one from
+a provisioning profile's developer certificate.
+
 The -x flag will print all the external commands executed by the gogio tool.
 `
@@ -64,7 +64,10 @@ func buildIOS(tmpDir, target string, bi *buildInfo) error {
 			return fmt.Errorf("the specified output directory %q does not end in .app or .ipa", out)
 		}
 		if !forDevice {
-			return exeIOS(tmpDir, target, out, bi)
+			if err := exeIOS(tmpDir, target, out, bi); err != nil {
+				return err
+			}
+			return signIOS(bi, tmpDir, out, forDevice)
 		}
 		payload := filepath.Join(tmpDir, "Payload")
 		appDir := filepath.Join(payload, appName+".app")
@@ -74,7 +77,7 @@ func buildIOS(tmpDir, target string, bi *buildInfo) error {
 		if err := exeIOS(tmpDir, target, appDir, bi); err != nil {
 			return err
 		}
-		if err := signIOS(bi, tmpDir, appDir); err != nil {
+		if err := signIOS(bi, tmpDir, appDir, forDevice); err != nil {
 			return err
 		}
 		return zipDir(out, tmpDir, "Payload")
@@ -83,7 +86,15 @@ func buildIOS(tmpDir, target string, bi *buildInfo) error {
 	}
 }
 
-func signIOS(bi *buildInfo, tmpDir, app string) error {
+// signIOS signs app for running on a device or, if forDevice is false, a
+// simulator. Simulator builds are always signed ad hoc, since they cannot
+// use a device provisioning profile; device builds are signed ad hoc only
+// when -adhoc is given, and otherwise require a provisioning profile
+// matching bi.appID.
+func signIOS(bi *buildInfo, tmpDir, app string, forDevice bool) error {
+	if *adhocSign || !forDevice {
+		return signIOSAdHoc(bi, tmpDir, app)
+	}
 	home, err := os.UserHomeDir()
 	if err != nil {
 		return err
@@ -130,28 +141,86 @@ func signIOS(bi *buildInfo, tmpDir, app string) error {
 		if err := copyFile(embedded, prov); err != nil {
 			return err
 		}
-		certDER, err := runCmdRaw(exec.Command("/usr/libexec/PlistBuddy", "-c", "Print:DeveloperCertificates:0", provInfo))
+		entitlementsXML, err := runCmd(exec.Command("/usr/libexec/PlistBuddy", "-x", "-c", "Print:Entitlements", provInfo))
 		if err != nil {
 			return err
 		}
-		// Omit trailing newline.
-		certDER = certDER[:len(certDER)-1]
-		entitlements, err := runCmd(exec.Command("/usr/libexec/PlistBuddy", "-x", "-c", "Print:Entitlements", provInfo))
-		if err != nil {
+		entFile := filepath.Join(tmpDir, "entitlements.plist")
+		if err := ioutil.WriteFile(entFile, []byte(entitlementsXML), 0660); err != nil {
 			return err
 		}
-		entFile := filepath.Join(tmpDir, "entitlements.plist")
-		if err := ioutil.WriteFile(entFile, []byte(entitlements), 0660); err != nil {
+		idHex := *signIdentity
+		if idHex == "" {
+			certDER, err := runCmdRaw(exec.Command("/usr/libexec/PlistBuddy", "-c", "Print:DeveloperCertificates:0", provInfo))
+			if err != nil {
+				return err
+			}
+			// Omit trailing newline.
+			certDER = certDER[:len(certDER)-1]
+			identity := sha1.Sum(certDER)
+			idHex = hex.EncodeToString(identity[:])
+		} else if err := checkIdentity(idHex); err != nil {
 			return err
 		}
-		identity := sha1.Sum(certDER)
-		idHex := hex.EncodeToString(identity[:])
 		_, err = runCmd(exec.Command("codesign", "-s", idHex, "-v", "--entitlements", entFile, app))
 		return err
 	}
 	return fmt.Errorf("sign: no valid provisioning profile found for bundle id %q among %v", bi.appID, avail)
 }
 
+// signIOSAdHoc signs app without a provisioning profile, using -identity
+// if given or ad hoc signing (identity "-") otherwise.
+func signIOSAdHoc(bi *buildInfo, tmpDir, app string) error {
+	idHex := "-"
+	if *signIdentity != "" {
+		if err := checkIdentity(*signIdentity); err != nil {
+			return err
+		}
+		idHex = *signIdentity
+	}
+	entFile, err := adHocEntitlements(tmpDir)
+	if err != nil {
+		return err
+	}
+	_, err = runCmd(exec.Command("codesign", "-s", idHex, "--entitlements", entFile, app))
+	return err
+}
+
+// checkIdentity reports an error if identity is not among the codesigning
+// identities available in the keychain.
+func checkIdentity(identity string) error {
+	identities, err := runCmd(exec.Command("security", "find-identity", "-v", "-p", "codesigning"))
+	if err != nil {
+		return err
+	}
+	if !strings.Contains(identities, identity) {
+		return fmt.Errorf("sign: no codesigning identity %q in the keychain", identity)
+	}
+	return nil
+}
+
+// adHocEntitlements returns the path to the entitlements plist to use for
+// ad hoc signing: the file named by -entitlements if given, or a minimal
+// default template otherwise.
+func adHocEntitlements(tmpDir string) (string, error) {
+	if *entitlements != "" {
+		return *entitlements, nil
+	}
+	const defaultEntitlements = `<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
+<plist version="1.0">
+<dict>
+	<key>get-task-allow</key>
+	<true/>
+</dict>
+</plist>`
+	entFile := filepath.Join(tmpDir, "entitlements.plist")
+	if err := ioutil.WriteFile(entFile, []byte(defaultEntitlements), 0660); err != nil {
+		return "", err
+	}
+	return entFile, nil
+}
+
 func exeIOS(tmpDir, target, app string, bi *buildInfo) error {
 	if bi.appID == "" {
 		return errors.New("app id is empty; use -appid to set it")
@@ -163,10 +232,10 @@ func exeIOS(tmpDir, target, app string, bi *buildInfo) error {
 		return err
 	}
 	mainm := filepath.Join(tmpDir, "main.m")
-	const mainmSrc = `@import UIKit;
+	mainmSrc := `@import UIKit;
 @import Gio;
 
-@interface GioAppDelegate : UIResponder <UIApplicationDelegate>
+` + pprofDelegateDecls(bi) + `@interface GioAppDelegate : UIResponder <UIApplicationDelegate>
 @property (strong, nonatomic) UIWindow *window;
 @end
 
@@ -178,7 +247,7 @@ func exeIOS(tmpDir, target, app string, bi *buildInfo) error {
 	[self.window makeKeyAndVisible];
 	return YES;
 }
-@end
+` + pprofDelegateMethods(bi) + `@end
 
 int main(int argc, char * argv[]) {
 	@autoreleasepool {
@@ -436,7 +505,7 @@ func archiveIOS(tmpDir, target, frameworkRoot string, bi *buildInfo) error {
 		cmd := exec.Command(
 			"go",
 			"build",
-			"-ldflags=-s -w "+bi.ldflags,
+			"-ldflags=-s -w "+bi.ldflags+pprofLdflags(bi),
 			"-buildmode=c-archive",
 			"-o", lib,
 			"-tags", tags,
@@ -479,7 +548,79 @@ func archiveIOS(tmpDir, target, frameworkRoot string, bi *buildInfo) error {
     export *
 }`, framework)
 	moduleFile := filepath.Join(frameworkDir, "Modules", "module.modulemap")
-	return ioutil.WriteFile(moduleFile, []byte(module), 0644)
+	if err := ioutil.WriteFile(moduleFile, []byte(module), 0644); err != nil {
+		return err
+	}
+	if !hasTag(tags, "pprof") {
+		return nil
+	}
+	// Let third-party embedders that drive their own app delegate in Swift
+	// or Objective-C trigger a profile flush without linking against the
+	// Go package that defines it.
+	profileHeader := filepath.Join(frameworkDir, "Headers", "GioProfile.h")
+	const profileHeaderSrc = `// Flushes the CPU, heap, block and mutex profiles started by the pprof
+// build tag to the app's Documents directory. Call from an embedder's own
+// applicationWillTerminate: or applicationDidEnterBackground:.
+extern void gio_profileFlush(void);
+`
+	return ioutil.WriteFile(profileHeader, []byte(profileHeaderSrc), 0644)
+}
+
+// hasTag reports whether the space separated tags string contains tag.
+func hasTag(tags, tag string) bool {
+	for _, t := range strings.Fields(tags) {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// pprofLdflags returns extra -ldflags that override the default pprof
+// profile filenames, if -tags pprof and -pprof-cpu or -pprof-mem were
+// given.
+func pprofLdflags(bi *buildInfo) string {
+	if !hasTag(bi.tags, "pprof") {
+		return ""
+	}
+	var flags string
+	if *pprofCPU != "" {
+		flags += " -X gioui.org/app/internal/profile.cpuProfile=" + *pprofCPU
+	}
+	if *pprofMem != "" {
+		flags += " -X gioui.org/app/internal/profile.memProfile=" + *pprofMem
+	}
+	return flags
+}
+
+// pprofDelegateDecls returns the forward declarations for the exported Go
+// functions that pprofDelegateMethods calls into, if -tags pprof was
+// given.
+func pprofDelegateDecls(bi *buildInfo) string {
+	if !hasTag(bi.tags, "pprof") {
+		return ""
+	}
+	return `extern void onWillTerminate(void);
+extern void onDidEnterBackground(void);
+
+`
+}
+
+// pprofDelegateMethods returns the Objective-C methods that flush pending
+// profiles before the app might be killed, if -tags pprof was given. iOS
+// apps rarely get a chance to run cleanup code on exit, so the flush also
+// runs when the app is merely backgrounded.
+func pprofDelegateMethods(bi *buildInfo) string {
+	if !hasTag(bi.tags, "pprof") {
+		return ""
+	}
+	return `- (void)applicationWillTerminate:(UIApplication *)application {
+	onWillTerminate();
+}
+- (void)applicationDidEnterBackground:(UIApplication *)application {
+	onDidEnterBackground();
+}
+`
 }
 
 func supportsGOOS(wantGoos string) (bool, error) {
@@ -25,8 +25,9 @@ var (
 	target        = flag.String("target", "", "specify target (ios, tvos, android, js).\n")
 	archNames     = flag.String("arch", "", "specify architecture(s) to include (arm, arm64, amd64).")
 	minsdk        = flag.Int("minsdk", 16, "specify minimum supported Android platform sdk version (e.g. 28 for android28 a.k.a. Android 9 Pie).")
-	buildMode     = flag.String("buildmode", "exe", "specify buildmode (archive, exe)")
+	buildMode     = flag.String("buildmode", "exe", "specify buildmode (archive, exe, bind)")
 	destPath      = flag.String("o", "", "output file or directory.\nFor -target ios or tvos, use the .app suffix to target simulators.")
+	javaPkg       = flag.String("javapkg", "", "Java package for generated bindings (for -target android -buildmode=bind)")
 	appID         = flag.String("appid", "", "app identifier (for -buildmode=exe)")
 	version       = flag.Int("version", 1, "app version (for -buildmode=exe)")
 	printCommands = flag.Bool("x", false, "print the commands")
@@ -35,6 +36,19 @@ var (
 	extraLdflags  = flag.String("ldflags", "", "extra flags to the Go linker")
 	extraTags     = flag.String("tags", "", "extra tags to the Go tool")
 	iconPath      = flag.String("icon", "", "Specify an icon for iOS and Android")
+	iconBG        = flag.String("icon-background", "#FFFFFF", "background color for the Android adaptive icon, as a #RRGGBB hex string")
+	signKey       = flag.String("signkey", "", "path to the keystore used to sign Android apps and app bundles.\nIf unspecified, a debug keystore is used or generated.")
+	signPass      = flag.String("signpass", "", "password for the Android signing keystore")
+	signScheme    = flag.String("signing-scheme", "v1,v2", "comma separated list of APK signature scheme versions to enable (v1, v2, v3, v4)")
+	keyAlias      = flag.String("key-alias", "", "alias of the signing key in the keystore.\nAuto-detected if the keystore has exactly one entry.")
+	keyPass       = flag.String("key-pass", "", "password for the signing key, if different from the keystore password")
+	v4SigFile     = flag.String("v4-signature-file", "", "output path for the v4 signature file used by `adb install --incremental`.\nDefaults to the APK path with an added .idsig suffix.")
+	reproducible  = flag.Bool("reproducible", true, "build archives with deterministic timestamps, ordering and no embedded build ids.\nHonors SOURCE_DATE_EPOCH and builds with -trimpath.")
+	pprofCPU      = flag.String("pprof-cpu", "", "for -target ios or tvos with -tags pprof, the CPU profile filename written to the app's Documents directory.\nDefaults to cpu.pprof.")
+	pprofMem      = flag.String("pprof-mem", "", "for -target ios or tvos with -tags pprof, the heap profile filename written to the app's Documents directory.\nDefaults to mem.pprof.")
+	adhocSign     = flag.Bool("adhoc", false, "for -target ios or tvos, sign the app ad hoc instead of searching for a matching provisioning profile.\nImplied for simulator builds.")
+	entitlements  = flag.String("entitlements", "", "path to an entitlements plist for ad hoc signing (for -target ios or tvos with -adhoc).\nDefaults to a minimal template.")
+	signIdentity  = flag.String("identity", "", "SHA-1 hash of the signing identity to use (for -target ios or tvos), looked up with `security find-identity -v -p codesigning`.\nDefaults to the provisioning profile's developer certificate, or ad hoc signing with -adhoc.")
 )
 
 func main() {
@@ -73,6 +87,13 @@ func flagValidate() error {
 	}
 	switch *buildMode {
 	case "archive", "exe":
+	case "bind":
+		if *target != "android" {
+			return errors.New("-buildmode=bind is only supported for -target android")
+		}
+		if *javaPkg == "" {
+			return errors.New("-buildmode=bind requires -javapkg")
+		}
 	default:
 		return fmt.Errorf("invalid -buildmode %s", *buildMode)
 	}
@@ -0,0 +1,76 @@
+// SPDX-License-Identifier: Unlicense OR MIT
+
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// respCache persists fetched avatar images and the last successfully
+// fetched contributor list under os.UserCacheDir, so a rate-limited or
+// offline run can still show the last fetched list when
+// fetchContributors fails.
+type respCache struct {
+	dir string
+}
+
+// cachedUser is the subset of user that can survive a round trip
+// through JSON; avatar images are cached separately, as the image bytes
+// the server returned.
+type cachedUser struct {
+	Login, Name, Company, AvatarSrc string
+}
+
+func newRespCache() (*respCache, error) {
+	base, err := os.UserCacheDir()
+	if err != nil {
+		return nil, fmt.Errorf("newRespCache: %v", err)
+	}
+	dir := filepath.Join(base, "gio-gophers")
+	if err := os.MkdirAll(filepath.Join(dir, "avatars"), 0o755); err != nil {
+		return nil, fmt.Errorf("newRespCache: %v", err)
+	}
+	return &respCache{dir: dir}, nil
+}
+
+// contributors returns the most recently cached contributor list, if
+// any.
+func (c *respCache) contributors() ([]cachedUser, error) {
+	body, err := os.ReadFile(filepath.Join(c.dir, "contributors.json"))
+	if err != nil {
+		return nil, err
+	}
+	var users []cachedUser
+	if err := json.Unmarshal(body, &users); err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+func (c *respCache) putContributors(users []cachedUser) error {
+	body, err := json.Marshal(users)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(c.dir, "contributors.json"), body, 0o644)
+}
+
+// avatar opens the cached, undecoded avatar image for url, if present.
+func (c *respCache) avatar(url string) (io.ReadCloser, error) {
+	return os.Open(c.avatarPath(url))
+}
+
+func (c *respCache) putAvatar(url string, png []byte) error {
+	return os.WriteFile(c.avatarPath(url), png, 0o644)
+}
+
+func (c *respCache) avatarPath(url string) string {
+	sum := sha256.Sum256([]byte(url))
+	return filepath.Join(c.dir, "avatars", hex.EncodeToString(sum[:])+".png")
+}
@@ -5,13 +5,16 @@ package main
 // A Gio program that displays Go contributors from GitHub. See https://gioui.org for more information.
 
 import (
+	"bytes"
 	"context"
 	"flag"
 	"fmt"
 	"image"
+	"io"
 	"log"
 	"net/http"
 	"os"
+	"time"
 
 	"golang.org/x/oauth2"
 
@@ -23,6 +26,7 @@ import (
 	"gioui.org/unit"
 
 	"github.com/google/go-github/v24/github"
+	"github.com/shurcooL/githubv4"
 
 	_ "image/jpeg"
 	_ "image/png"
@@ -37,8 +41,11 @@ type App struct {
 
 	updateUsers   chan []*user
 	commitsResult chan []*github.Commit
+	status        chan string
 	ctx           context.Context
 	ctxCancel     context.CancelFunc
+
+	cache *respCache
 }
 
 var (
@@ -87,6 +94,9 @@ func (a *App) run() error {
 		case commits := <-a.commitsResult:
 			a.ui.selectedUser.commits = commits
 			a.w.Invalidate()
+		case status := <-a.status:
+			a.ui.status = status
+			a.w.Invalidate()
 		case e := <-a.w.Events():
 			switch e := e.(type) {
 			case key.Event:
@@ -138,6 +148,12 @@ func newApp(w *app.Window) *App {
 		w:             w,
 		updateUsers:   make(chan []*user),
 		commitsResult: make(chan []*github.Commit, 1),
+		status:        make(chan string, 1),
+	}
+	if cache, err := newRespCache(); err != nil {
+		fmt.Fprintf(os.Stderr, "github: disk cache unavailable: %v\n", err)
+	} else {
+		a.cache = cache
 	}
 	fetch := func(u string) {
 		a.fetchCommits(a.ctx, u)
@@ -146,6 +162,13 @@ func newApp(w *app.Window) *App {
 	return a
 }
 
+func (a *App) notifyStatus(msg string) {
+	select {
+	case a.status <- msg:
+	default:
+	}
+}
+
 func githubClient(ctx context.Context) *github.Client {
 	var tc *http.Client
 	if *token != "" {
@@ -157,74 +180,200 @@ func githubClient(ctx context.Context) *github.Client {
 	return github.NewClient(tc)
 }
 
+// githubv4Client builds a GraphQL client whose transport reports rate
+// limit state on a.status and retries with exponential backoff when
+// the quota is exhausted.
+func (a *App) githubv4Client() *githubv4.Client {
+	base := http.DefaultTransport
+	if *token != "" {
+		ts := oauth2.StaticTokenSource(&oauth2.Token{AccessToken: *token})
+		base = &oauth2.Transport{Source: ts, Base: base}
+	}
+	hc := &http.Client{Transport: &rateLimitTransport{base: base, status: a.status}}
+	return githubv4.NewClient(hc)
+}
+
+// contributorsQuery fetches login, name, company and avatar URL for
+// every contributor of a repository in a single paginated query,
+// instead of one REST call per contributor.
+type contributorsQuery struct {
+	Repository struct {
+		Contributors struct {
+			Nodes []struct {
+				Login     githubv4.String
+				Name      githubv4.String
+				Company   githubv4.String
+				AvatarURL githubv4.String `graphql:"avatarUrl(size: 96)"`
+			}
+			PageInfo struct {
+				HasNextPage githubv4.Boolean
+				EndCursor   githubv4.String
+			}
+		} `graphql:"contributors(first: 100, after: $cursor)"`
+	} `graphql:"repository(owner: $owner, name: $name)"`
+}
+
 func (a *App) fetchContributors() {
-	client := githubClient(a.ctx)
-	cons, _, err := client.Repositories.ListContributors(a.ctx, "golang", "go", nil)
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "github: failed to fetch contributors: %v\n", err)
-		return
+	client := a.githubv4Client()
+	vars := map[string]interface{}{
+		"owner":  githubv4.String("golang"),
+		"name":   githubv4.String("go"),
+		"cursor": (*githubv4.String)(nil),
 	}
 	var users []*user
-	userErrs := make(chan error, len(cons))
-	avatarErrs := make(chan error, len(cons))
-	for _, con := range cons {
-		con := con
-		avatar := con.GetAvatarURL()
-		if avatar == "" {
-			continue
-		}
-		u := &user{
-			login: con.GetLogin(),
-		}
-		users = append(users, u)
-		go func() {
-			guser, _, err := client.Users.Get(a.ctx, u.login)
-			if err != nil {
-				avatarErrs <- err
+	for {
+		var q contributorsQuery
+		if err := client.Query(a.ctx, &q, vars); err != nil {
+			fmt.Fprintf(os.Stderr, "github: failed to fetch contributors: %v\n", err)
+			if a.cache == nil {
 				return
 			}
-			u.name = guser.GetName()
-			u.company = guser.GetCompany()
-			avatarErrs <- nil
-		}()
+			cached, cerr := a.cache.contributors()
+			if cerr != nil {
+				return
+			}
+			a.notifyStatus("GitHub unreachable, showing cached contributors")
+			for _, cu := range cached {
+				users = append(users, &user{login: cu.Login, name: cu.Name, company: cu.Company, avatarSrc: cu.AvatarSrc})
+			}
+			break
+		}
+		for _, n := range q.Repository.Contributors.Nodes {
+			if n.AvatarURL == "" || n.Name == "" {
+				continue
+			}
+			users = append(users, &user{
+				login:     string(n.Login),
+				name:      string(n.Name),
+				company:   string(n.Company),
+				avatarSrc: string(n.AvatarURL),
+			})
+		}
+		if !bool(q.Repository.Contributors.PageInfo.HasNextPage) {
+			break
+		}
+		vars["cursor"] = githubv4.NewString(q.Repository.Contributors.PageInfo.EndCursor)
+	}
+	if a.cache != nil && len(users) > 0 {
+		cached := make([]cachedUser, len(users))
+		for i, u := range users {
+			cached[i] = cachedUser{Login: u.login, Name: u.name, Company: u.company, AvatarSrc: u.avatarSrc}
+		}
+		if err := a.cache.putContributors(cached); err != nil {
+			fmt.Fprintf(os.Stderr, "github: failed to cache contributors: %v\n", err)
+		}
+	}
+	avatarErrs := make(chan error, len(users))
+	for _, u := range users {
+		u := u
 		go func() {
-			a, err := fetchImage(avatar)
-			if a != nil {
-				u.avatar = a
+			img, err := a.fetchImage(u.avatarSrc)
+			if img != nil {
+				u.avatar = img
 			}
-			userErrs <- err
+			avatarErrs <- err
 		}()
 	}
-	for i := 0; i < len(cons); i++ {
-		if err := <-userErrs; err != nil {
-			fmt.Fprintf(os.Stderr, "github: failed to fetch user: %v\n", err)
-		}
+	for range users {
 		if err := <-avatarErrs; err != nil {
 			fmt.Fprintf(os.Stderr, "github: failed to fetch avatar: %v\n", err)
 		}
 	}
-	// Drop users with no avatar or name.
+	// Drop users with no avatar.
 	for i := len(users) - 1; i >= 0; i-- {
-		if u := users[i]; u.name == "" || u.avatar.Bounds().Size() == (image.Point{}) {
+		if u := users[i]; u.avatar == nil || u.avatar.Bounds().Size() == (image.Point{}) {
 			users = append(users[:i], users[i+1:]...)
 		}
 	}
 	a.updateUsers <- users
 }
 
-func fetchImage(url string) (image.Image, error) {
+// fetchImage loads an avatar, preferring the on-disk cache over the
+// network.
+func (a *App) fetchImage(url string) (image.Image, error) {
+	if a.cache != nil {
+		if f, err := a.cache.avatar(url); err == nil {
+			defer f.Close()
+			if img, _, err := image.Decode(f); err == nil {
+				return img, nil
+			}
+		}
+	}
 	resp, err := http.Get(url)
 	if err != nil {
 		return nil, fmt.Errorf("fetchImage: http.Get(%q): %v", url, err)
 	}
 	defer resp.Body.Close()
-	img, _, err := image.Decode(resp.Body)
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("fetchImage: read %q: %v", url, err)
+	}
+	img, _, err := image.Decode(bytes.NewReader(body))
 	if err != nil {
 		return nil, fmt.Errorf("fetchImage: image decode failed: %v", err)
 	}
+	if a.cache != nil {
+		if err := a.cache.putAvatar(url, body); err != nil {
+			fmt.Fprintf(os.Stderr, "github: failed to cache avatar: %v\n", err)
+		}
+	}
 	return img, nil
 }
 
+// rateLimitTransport reports the GitHub rate limit state of every
+// response on status, and retries requests that failed because the
+// quota is exhausted with exponential backoff.
+type rateLimitTransport struct {
+	base   http.RoundTripper
+	status chan<- string
+}
+
+func (t *rateLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	const maxAttempts = 5
+	delay := time.Second
+	for attempt := 0; ; attempt++ {
+		resp, err := t.base.RoundTrip(req)
+		if err != nil {
+			return resp, err
+		}
+		if resp.Header.Get("X-RateLimit-Remaining") != "0" || attempt >= maxAttempts-1 {
+			if remaining := resp.Header.Get("X-RateLimit-Remaining"); remaining != "" {
+				t.notify(fmt.Sprintf("GitHub rate limit: %s remaining", remaining))
+			}
+			return resp, nil
+		}
+		resp.Body.Close()
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req = req.Clone(req.Context())
+			req.Body = body
+		}
+		wait := delay
+		if retry := resp.Header.Get("Retry-After"); retry != "" {
+			if secs, err := time.ParseDuration(retry + "s"); err == nil {
+				wait = secs
+			}
+		}
+		t.notify(fmt.Sprintf("GitHub rate limit hit, retrying in %s", wait))
+		select {
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		case <-time.After(wait):
+		}
+		delay *= 2
+	}
+}
+
+func (t *rateLimitTransport) notify(msg string) {
+	select {
+	case t.status <- msg:
+	default:
+	}
+}
+
 func (a *App) fetchCommits(ctx context.Context, user string) {
 	go func() {
 		gh := githubClient(ctx)
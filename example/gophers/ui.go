@@ -47,6 +47,9 @@ type UI struct {
 	profiling   bool
 	profile     profile.Event
 	lastMallocs uint64
+
+	// status reports GitHub rate-limit and retry state.
+	status string
 }
 
 type userPage struct {
@@ -56,11 +59,12 @@ type userPage struct {
 }
 
 type user struct {
-	name     string
-	login    string
-	company  string
-	avatar   image.Image
-	avatarOp paint.ImageOp
+	name      string
+	login     string
+	company   string
+	avatarSrc string
+	avatar    image.Image
+	avatarOp  paint.ImageOp
 }
 
 var theme *material.Theme
@@ -232,6 +236,17 @@ func (u *UI) layoutUsers(gtx layout.Context) {
 						}),
 					)
 				}),
+				layout.Rigid(func(gtx C) D {
+					if u.status == "" {
+						return D{}
+					}
+					in := layout.Inset{Right: unit.Dp(8), Bottom: unit.Dp(8), Left: unit.Dp(8)}
+					return in.Layout(gtx, func(gtx C) D {
+						lbl := material.Caption(theme, u.status)
+						lbl.Color = rgb(0xcc8800)
+						return lbl.Layout(gtx)
+					})
+				}),
 				layout.Flexed(1, func(gtx C) D {
 					gtx.Constraints.Min.X = gtx.Constraints.Max.X
 					return u.layoutContributors(gtx)
@@ -28,14 +28,88 @@ import (
 // The duration is somewhat arbitrary.
 const doubleClickDuration = 200 * time.Millisecond
 
+// The duration is somewhat arbitrary.
+const defaultLongPressDuration = 500 * time.Millisecond
+
+// Recognizer is implemented by gesture detectors, such as Click, Drag,
+// Scroll, Hover, LongPress and DoubleTap, that turn low level pointer
+// events into higher level gesture events. It documents the Add/Update
+// convention shared by the recognizers in this package so custom
+// recognizers can be registered for pointer events the same way.
+//
+// Each recognizer is independent: there is no registry that owns a set
+// of recognizers for an area, no mutual-exclusion declaration between
+// them, and no single event type they all report. A widget that wants
+// both a Click and a LongPress on the same area runs both recognizers'
+// Update against the same input.Source and decides for itself which
+// gesture to act on, the same way widget.Clickable and widget.Selectable
+// already do. Two-finger and multi-pointer gestures (TwoFingerTap, Pan,
+// Pinch/Zoom, Rotate, velocity-tracking Swipe) are not implemented:
+// recognizers here only ever track a single pointer.ID at a time, and
+// adding multi-pointer tracking, a unified GestureEvent and a Filter
+// opt-in mechanism wired into input.Router is a larger, separate change
+// than this package attempts.
+type Recognizer interface {
+	// Add adds the recognizer's event filters to the operation list.
+	Add(ops *op.Ops)
+}
+
+var (
+	_ Recognizer = (*Hover)(nil)
+	_ Recognizer = (*Click)(nil)
+	_ Recognizer = (*Drag)(nil)
+	_ Recognizer = (*Scroll)(nil)
+	_ Recognizer = (*LongPress)(nil)
+	_ Recognizer = (*DoubleTap)(nil)
+)
+
 // Hover detects the hover gesture for a pointer area.
 type Hover struct {
+	// Delay overrides how long the pointer must dwell inside the area,
+	// without moving more than the slop threshold, before Events
+	// reports a HoverIntent. The zero value uses a default delay.
+	Delay time.Duration
+
 	// entered tracks whether the pointer is inside the gesture.
 	entered bool
 	// pid is the pointer.ID.
 	pid pointer.ID
+
+	// intent tracks whether a HoverIntent has already fired for the
+	// current hover.
+	intent bool
+	// start is the position intent dwell time is measured from.
+	start f32.Point
+	// since is when start was last reset.
+	since time.Time
 }
 
+// HoverKind is the kind of a HoverEvent.
+type HoverKind uint8
+
+const (
+	// HoverBegin is reported when the pointer enters the area.
+	HoverBegin HoverKind = iota
+	// HoverEnd is reported when the pointer leaves the area, or the
+	// gesture is cancelled by a press, a scroll or a focus change.
+	HoverEnd
+	// HoverIntent is reported once the pointer has dwelled inside the
+	// area for Delay without moving more than the slop threshold.
+	HoverIntent
+)
+
+// HoverEvent represents a hover state transition reported by
+// Hover.Events.
+type HoverEvent struct {
+	Kind     HoverKind
+	Position image.Point
+}
+
+func (HoverEvent) ImplementsEvent() {}
+
+// The duration is somewhat arbitrary.
+const defaultHoverIntentDuration = 500 * time.Millisecond
+
 // Add the gesture to detect hovering over the current pointer area.
 func (h *Hover) Add(ops *op.Ops) {
 	event.Op(ops, h)
@@ -72,9 +146,95 @@ func (h *Hover) Update(q input.Source) bool {
 	return h.entered
 }
 
+// Events updates hover-intent state and reports the next HoverEvent,
+// if any: HoverBegin when the pointer enters the area, HoverIntent
+// once it has dwelled there for Delay without moving more than the
+// slop threshold, and HoverEnd when it leaves the area or the intent
+// is cancelled by a press, a scroll or the area losing focus.
+//
+// The caller supplies the current time so a HoverIntent event can fire
+// even without new pointer events; Events requests an invalidation
+// while a dwell timer is pending. Call either Update or Events during
+// a frame, not both: they track state independently.
+func (h *Hover) Events(cfg unit.Metric, q input.Source, t time.Time) (HoverEvent, bool) {
+	for {
+		ev, ok := q.Event(
+			pointer.Filter{
+				Target: h,
+				Kinds:  pointer.Enter | pointer.Leave | pointer.Cancel | pointer.Move | pointer.Press | pointer.Scroll,
+			},
+			key.FocusFilter{Target: h},
+		)
+		if !ok {
+			break
+		}
+		switch e := ev.(type) {
+		case key.FocusEvent:
+			if !e.Focus && h.entered {
+				h.entered = false
+				h.intent = false
+				return HoverEvent{Kind: HoverEnd}, true
+			}
+		case pointer.Event:
+			switch e.Kind {
+			case pointer.Enter:
+				if h.entered {
+					break
+				}
+				h.entered = true
+				h.intent = false
+				h.pid = e.PointerID
+				h.start = e.Position
+				h.since = t
+				return HoverEvent{Kind: HoverBegin, Position: e.Position.Round()}, true
+			case pointer.Leave, pointer.Cancel:
+				if !h.entered || e.PointerID != h.pid {
+					break
+				}
+				h.entered = false
+				h.intent = false
+				return HoverEvent{Kind: HoverEnd, Position: e.Position.Round()}, true
+			case pointer.Press, pointer.Scroll:
+				if !h.entered {
+					break
+				}
+				h.entered = false
+				h.intent = false
+				return HoverEvent{Kind: HoverEnd, Position: e.Position.Round()}, true
+			case pointer.Move:
+				if !h.entered || h.intent || e.PointerID != h.pid {
+					break
+				}
+				diff := e.Position.Sub(h.start)
+				slop := cfg.Dp(touchSlop)
+				if diff.X*diff.X+diff.Y*diff.Y > float32(slop*slop) {
+					h.start = e.Position
+					h.since = t
+				}
+			}
+		}
+	}
+	if h.entered && !h.intent {
+		d := h.Delay
+		if d == 0 {
+			d = defaultHoverIntentDuration
+		}
+		if t.Sub(h.since) >= d {
+			h.intent = true
+			return HoverEvent{Kind: HoverIntent, Position: h.start.Round()}, true
+		}
+		q.Execute(op.InvalidateCmd{})
+	}
+	return HoverEvent{}, false
+}
+
 // Click detects click gestures in the form
 // of ClickEvents.
 type Click struct {
+	// ClickThreshold overrides the maximum duration between two
+	// clicks for them to be combined into a single multi-click
+	// ClickEvent. The zero value uses a default threshold.
+	ClickThreshold time.Duration
 	// clickedAt is the timestamp at which
 	// the last click occurred.
 	clickedAt time.Duration
@@ -229,7 +389,11 @@ func (c *Click) Update(q input.Source) (ClickEvent, bool) {
 				break
 			}
 			c.pressed = true
-			if e.Time-c.clickedAt < doubleClickDuration {
+			threshold := c.ClickThreshold
+			if threshold == 0 {
+				threshold = doubleClickDuration
+			}
+			if e.Time-c.clickedAt < threshold {
 				c.clicks++
 			} else {
 				c.clicks = 1
@@ -443,6 +607,160 @@ func (d *Drag) Dragging() bool { return d.dragging }
 // Pressed returns whether a pointer is pressing.
 func (d *Drag) Pressed() bool { return d.pressed }
 
+// LongPress detects the long-press gesture: a pointer pressed and held
+// in place for at least Duration.
+type LongPress struct {
+	// Duration overrides how long a pointer must be held for the
+	// gesture to fire. The zero value uses a default duration.
+	Duration time.Duration
+
+	pressed   bool
+	fired     bool
+	pid       pointer.ID
+	start     f32.Point
+	pressedAt time.Time
+	source    pointer.Source
+	modifiers key.Modifiers
+}
+
+// LongPressEvent is reported once a LongPress gesture fires.
+type LongPressEvent struct {
+	Position  image.Point
+	Source    pointer.Source
+	Modifiers key.Modifiers
+}
+
+func (LongPressEvent) ImplementsEvent() {}
+
+// Add the handler to the operation list to receive long-press events.
+func (l *LongPress) Add(ops *op.Ops) {
+	event.Op(ops, l)
+}
+
+// Update state and report a LongPressEvent once the gesture fires. The
+// caller supplies the current time so the gesture can fire even in the
+// absence of new pointer events.
+func (l *LongPress) Update(cfg unit.Metric, q input.Source, t time.Time) (LongPressEvent, bool) {
+	for {
+		ev, ok := q.Event(pointer.Filter{
+			Target: l,
+			Kinds:  pointer.Press | pointer.Drag | pointer.Release | pointer.Cancel,
+		})
+		if !ok {
+			break
+		}
+		e, ok := ev.(pointer.Event)
+		if !ok {
+			continue
+		}
+		switch e.Kind {
+		case pointer.Press:
+			if e.Source == pointer.Mouse && e.Buttons != pointer.ButtonPrimary {
+				break
+			}
+			l.pressed = true
+			l.fired = false
+			l.pid = e.PointerID
+			l.start = e.Position
+			l.pressedAt = t
+			l.source = e.Source
+			l.modifiers = e.Modifiers
+		case pointer.Drag:
+			if !l.pressed || e.PointerID != l.pid {
+				break
+			}
+			diff := e.Position.Sub(l.start)
+			slop := cfg.Dp(touchSlop)
+			if diff.X*diff.X+diff.Y*diff.Y > float32(slop*slop) {
+				l.pressed = false
+			}
+		case pointer.Release, pointer.Cancel:
+			if e.PointerID == l.pid {
+				l.pressed = false
+			}
+		}
+	}
+	if l.pressed && !l.fired {
+		d := l.Duration
+		if d == 0 {
+			d = defaultLongPressDuration
+		}
+		if t.Sub(l.pressedAt) >= d {
+			l.fired = true
+			q.Execute(pointer.GrabCmd{Tag: l, ID: l.pid})
+			return LongPressEvent{Position: l.start.Round(), Source: l.source, Modifiers: l.modifiers}, true
+		}
+		q.Execute(op.InvalidateCmd{})
+	}
+	return LongPressEvent{}, false
+}
+
+// DoubleTap detects a double-tap (or double-click) gesture: two presses
+// of the same pointer in quick succession, with no Drag recognizer
+// involved. Unlike Click's NumClicks, which reports every click and
+// lets the caller compare against 2, DoubleTap reports only the
+// double-tap itself, for callers that have no use for single clicks.
+type DoubleTap struct {
+	// Threshold overrides the maximum duration between the two presses
+	// for them to count as a double-tap. The zero value uses a default
+	// threshold, the same as Click.ClickThreshold's default.
+	Threshold time.Duration
+
+	pressedAt time.Duration
+	taps      int
+	pid       pointer.ID
+}
+
+// DoubleTapEvent is reported once a DoubleTap gesture fires.
+type DoubleTapEvent struct {
+	Position  image.Point
+	Source    pointer.Source
+	Modifiers key.Modifiers
+}
+
+func (DoubleTapEvent) ImplementsEvent() {}
+
+// Add the handler to the operation list to receive double-tap events.
+func (d *DoubleTap) Add(ops *op.Ops) {
+	event.Op(ops, d)
+}
+
+// Update state and report a DoubleTapEvent once the gesture fires.
+func (d *DoubleTap) Update(q input.Source) (DoubleTapEvent, bool) {
+	for {
+		ev, ok := q.Event(pointer.Filter{
+			Target: d,
+			Kinds:  pointer.Press,
+		})
+		if !ok {
+			break
+		}
+		e, ok := ev.(pointer.Event)
+		if !ok || e.Kind != pointer.Press {
+			continue
+		}
+		if e.Source == pointer.Mouse && e.Buttons != pointer.ButtonPrimary {
+			continue
+		}
+		threshold := d.Threshold
+		if threshold == 0 {
+			threshold = doubleClickDuration
+		}
+		if d.taps > 0 && e.PointerID == d.pid && e.Time-d.pressedAt < threshold {
+			d.taps++
+		} else {
+			d.taps = 1
+		}
+		d.pressedAt = e.Time
+		d.pid = e.PointerID
+		if d.taps == 2 {
+			d.taps = 0
+			return DoubleTapEvent{Position: e.Position.Round(), Source: e.Source, Modifiers: e.Modifiers}, true
+		}
+	}
+	return DoubleTapEvent{}, false
+}
+
 func (a Axis) String() string {
 	switch a {
 	case Horizontal:
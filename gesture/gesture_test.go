@@ -13,6 +13,7 @@ import (
 	"gioui.org/io/pointer"
 	"gioui.org/op"
 	"gioui.org/op/clip"
+	"gioui.org/unit"
 )
 
 func TestHover(t *testing.T) {
@@ -100,6 +101,207 @@ func TestMouseClicks(t *testing.T) {
 	}
 }
 
+func TestHoverIntent(t *testing.T) {
+	now := time.Now()
+
+	t.Run("fires after delay", func(t *testing.T) {
+		var h Hover
+		var ops op.Ops
+		h.Add(&ops)
+
+		var r input.Router
+		h.Events(unit.Metric{}, r.Source(), now)
+		r.Frame(&ops)
+		r.Queue(pointer.Event{Kind: pointer.Enter, Position: f32.Pt(10, 10)})
+
+		ev, ok := h.Events(unit.Metric{}, r.Source(), now)
+		if !ok || ev.Kind != HoverBegin {
+			t.Fatalf("got %v, %v; want HoverBegin", ev, ok)
+		}
+		if _, ok := h.Events(unit.Metric{}, r.Source(), now.Add(defaultHoverIntentDuration-1)); ok {
+			t.Fatal("hover intent fired too early")
+		}
+		ev, ok = h.Events(unit.Metric{}, r.Source(), now.Add(defaultHoverIntentDuration))
+		if !ok || ev.Kind != HoverIntent {
+			t.Fatalf("got %v, %v; want HoverIntent", ev, ok)
+		}
+	})
+
+	t.Run("cancelled by press", func(t *testing.T) {
+		var h Hover
+		var ops op.Ops
+		h.Add(&ops)
+
+		var r input.Router
+		h.Events(unit.Metric{}, r.Source(), now)
+		r.Frame(&ops)
+		r.Queue(pointer.Event{Kind: pointer.Enter, Position: f32.Pt(10, 10)})
+		h.Events(unit.Metric{}, r.Source(), now)
+
+		r.Queue(pointer.Event{Kind: pointer.Press, Position: f32.Pt(10, 10)})
+		ev, ok := h.Events(unit.Metric{}, r.Source(), now)
+		if !ok || ev.Kind != HoverEnd {
+			t.Fatalf("got %v, %v; want HoverEnd", ev, ok)
+		}
+		if _, ok := h.Events(unit.Metric{}, r.Source(), now.Add(defaultHoverIntentDuration)); ok {
+			t.Fatal("hover intent should have been cancelled by the press")
+		}
+	})
+
+	t.Run("cancelled by movement", func(t *testing.T) {
+		var h Hover
+		var ops op.Ops
+		h.Add(&ops)
+
+		var r input.Router
+		h.Events(unit.Metric{}, r.Source(), now)
+		r.Frame(&ops)
+		r.Queue(pointer.Event{Kind: pointer.Enter, Position: f32.Pt(10, 10)})
+		h.Events(unit.Metric{}, r.Source(), now)
+
+		r.Queue(pointer.Event{Kind: pointer.Move, Position: f32.Pt(100, 100)})
+		h.Events(unit.Metric{}, r.Source(), now.Add(defaultHoverIntentDuration/2))
+
+		if _, ok := h.Events(unit.Metric{}, r.Source(), now.Add(defaultHoverIntentDuration)); ok {
+			t.Fatal("hover intent should have been reset by the movement")
+		}
+	})
+}
+
+func TestLongPress(t *testing.T) {
+	now := time.Now()
+	press := pointer.Event{
+		Kind:    pointer.Press,
+		Source:  pointer.Mouse,
+		Buttons: pointer.ButtonPrimary,
+		Time:    0,
+	}
+
+	t.Run("fires after duration", func(t *testing.T) {
+		var l LongPress
+		var ops op.Ops
+		l.Add(&ops)
+
+		var r input.Router
+		l.Update(unit.Metric{}, r.Source(), now)
+		r.Frame(&ops)
+		r.Queue(press)
+
+		if _, ok := l.Update(unit.Metric{}, r.Source(), now); ok {
+			t.Fatal("long press fired too early")
+		}
+		if _, ok := l.Update(unit.Metric{}, r.Source(), now.Add(defaultLongPressDuration-1)); ok {
+			t.Fatal("long press fired too early")
+		}
+		ev, ok := l.Update(unit.Metric{}, r.Source(), now.Add(defaultLongPressDuration))
+		if !ok {
+			t.Fatal("expected long press event")
+		}
+		if ev.Source != pointer.Mouse {
+			t.Errorf("got source %v, expected %v", ev.Source, pointer.Mouse)
+		}
+	})
+
+	t.Run("cancelled by movement", func(t *testing.T) {
+		var l LongPress
+		var ops op.Ops
+		l.Add(&ops)
+
+		var r input.Router
+		l.Update(unit.Metric{}, r.Source(), now)
+		r.Frame(&ops)
+		r.Queue(press)
+		l.Update(unit.Metric{}, r.Source(), now)
+
+		drag := press
+		drag.Kind = pointer.Drag
+		drag.Position = f32.Pt(100, 100)
+		r.Queue(drag)
+
+		if _, ok := l.Update(unit.Metric{}, r.Source(), now.Add(defaultLongPressDuration)); ok {
+			t.Fatal("long press should have been cancelled by movement")
+		}
+	})
+
+	t.Run("cancelled by release", func(t *testing.T) {
+		var l LongPress
+		var ops op.Ops
+		l.Add(&ops)
+
+		var r input.Router
+		l.Update(unit.Metric{}, r.Source(), now)
+		r.Frame(&ops)
+		r.Queue(press)
+		l.Update(unit.Metric{}, r.Source(), now)
+
+		release := press
+		release.Kind = pointer.Release
+		r.Queue(release)
+
+		if _, ok := l.Update(unit.Metric{}, r.Source(), now.Add(defaultLongPressDuration)); ok {
+			t.Fatal("long press should have been cancelled by release")
+		}
+	})
+}
+
+func TestDoubleTap(t *testing.T) {
+	press := pointer.Event{
+		Kind:    pointer.Press,
+		Source:  pointer.Mouse,
+		Buttons: pointer.ButtonPrimary,
+	}
+
+	t.Run("fires on two quick presses", func(t *testing.T) {
+		var d DoubleTap
+		var ops op.Ops
+		d.Add(&ops)
+
+		var r input.Router
+		d.Update(r.Source())
+		r.Frame(&ops)
+
+		first := press
+		first.Time = 0
+		r.Queue(first)
+		if _, ok := d.Update(r.Source()); ok {
+			t.Fatal("double-tap fired after a single press")
+		}
+
+		second := press
+		second.Time = doubleClickDuration - 1
+		r.Queue(second)
+		ev, ok := d.Update(r.Source())
+		if !ok {
+			t.Fatal("expected a double-tap event")
+		}
+		if ev.Source != pointer.Mouse {
+			t.Errorf("got source %v, expected %v", ev.Source, pointer.Mouse)
+		}
+	})
+
+	t.Run("does not fire when presses are too far apart", func(t *testing.T) {
+		var d DoubleTap
+		var ops op.Ops
+		d.Add(&ops)
+
+		var r input.Router
+		d.Update(r.Source())
+		r.Frame(&ops)
+
+		first := press
+		first.Time = 0
+		r.Queue(first)
+		d.Update(r.Source())
+
+		second := press
+		second.Time = doubleClickDuration + 1
+		r.Queue(second)
+		if _, ok := d.Update(r.Source()); ok {
+			t.Fatal("double-tap fired despite the gap exceeding the threshold")
+		}
+	})
+}
+
 func mouseClickEvents(times ...time.Duration) []event.Event {
 	press := pointer.Event{
 		Kind:    pointer.Press,
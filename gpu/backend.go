@@ -26,6 +26,25 @@ type Backend interface {
 	NewProgram(vertexShader, fragmentShader ShaderSources) (Program, error)
 	NewInputLayout(vertexShader ShaderSources, layout []InputDesc) (InputLayout, error)
 
+	// NewComputeProgram compiles and links a single compute shader.
+	// It panics on backends that don't advertise
+	// FeatureCompute.
+	NewComputeProgram(shader ShaderSources) (Program, error)
+	BindStorageBuffer(binding int, buf Buffer)
+	BindImageTexture(unit int, tex Texture, access AccessType, format TextureFormat)
+	DispatchCompute(x, y, z int)
+	MemoryBarrier()
+
+	// NewFramebufferMSAA builds a render target from desc, supporting
+	// multisampling and multiple color attachments. BlitFramebuffer
+	// resolves src into dst, e.g. to downsample an MSAA target.
+	NewFramebufferMSAA(desc FramebufferDesc) (Framebuffer, error)
+	BlitFramebuffer(dst, src Framebuffer, srcRect, dstRect image.Rectangle, filter TextureFilter)
+
+	// NewOccluder creates a GPU occlusion query, used to skip drawing
+	// content hidden behind previously rendered opaque geometry.
+	NewOccluder() Occluder
+
 	DepthFunc(f DepthFunc)
 	ClearColor(r, g, b, a float32)
 	ClearDepth(d float32)
@@ -39,8 +58,23 @@ type Backend interface {
 	BlendFunc(sfactor, dfactor BlendFactor)
 }
 
+// FramebufferDesc describes a (possibly multisampled, multi-attachment)
+// render target for NewFramebufferMSAA.
+type FramebufferDesc struct {
+	Color   []Texture
+	Depth   int
+	Samples int
+	SRGB    bool
+}
+
 type ShaderSources struct {
-	GLES2       string
+	GLES2 string
+	// GLSL is a version-less GLSL body shared by GL 3.3, GLES 2 and
+	// GLES 3.1: the backend prepends a #version line and a set of
+	// VSIN/VSOUT/FSIN/FRAGCOLOR portability macros appropriate for
+	// the version it detected at NewBackend time. Backends that
+	// don't support picking a preamble fall back to GLES2.
+	GLSL        string
 	HLSL        []byte
 	Uniforms    []UniformLocation
 	UniformSize int
@@ -104,6 +138,10 @@ type DepthFunc uint8
 
 type Features uint
 
+// AccessType describes how a compute shader invocation is allowed to
+// access a bound image.
+type AccessType uint8
+
 type Caps struct {
 	Features       Features
 	MaxTextureSize int
@@ -138,6 +176,18 @@ type Timer interface {
 	Release()
 }
 
+// Occluder is a GPU occlusion query: it reports how many samples of
+// the geometry drawn between Begin and End passed the depth test,
+// so the caller can skip drawing content it fully hides.
+type Occluder interface {
+	Begin()
+	End()
+	// Samples returns the number of samples that passed, and whether
+	// the result is available yet.
+	Samples() (samples uint64, ready bool)
+	Release()
+}
+
 type Texture interface {
 	Upload(img *image.RGBA)
 	Release()
@@ -176,6 +226,24 @@ const (
 
 const (
 	FeatureTimers Features = iota
+	// FeatureCompute is set when the backend supports compute
+	// shaders: NewComputeProgram, BindStorageBuffer,
+	// BindImageTexture, DispatchCompute and MemoryBarrier.
+	FeatureCompute Features = 1 << iota
+	// FeatureOcclusion is set when NewOccluder returns a query backed
+	// by real GPU counters. Otherwise it falls back to an
+	// approximation.
+	FeatureOcclusion
+	// FeatureFramebufferMultisample is set when NewFramebufferMSAA
+	// supports desc.Samples > 1 and BlitFramebuffer is implemented.
+	// Both panic on backends that don't advertise it, such as GLES 2.
+	FeatureFramebufferMultisample
+)
+
+const (
+	AccessReadOnly AccessType = iota
+	AccessWriteOnly
+	AccessReadWrite
 )
 
 const (
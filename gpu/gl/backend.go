@@ -27,7 +27,26 @@ type Backend struct {
 	// Single channel alpha textures.
 	alphaTriple textureTriple
 	srgbaTriple textureTriple
-}
+	// preamble is prepended to every gpu.ShaderSources.GLSL body
+	// before compilation; see shaderPreamble.
+	preamble string
+	// vertexRing backs dynamic (non-immutable) vertex buffer
+	// uploads; see ringBuffer.
+	vertexRing *ringBuffer
+	// driver identifies the GL implementation, for keying cache's
+	// entries; see driverString.
+	driver string
+	// cache stores linked program binaries across runs, avoiding a
+	// GLSL recompile on every NewProgram call. It is nil if
+	// NewDiskProgramCache failed, in which case CreateProgram falls
+	// back to compiling from source every time.
+	cache ProgramCache
+}
+
+// ringBufferSize is the capacity of Backend.vertexRing. It is sized
+// generously for a single frame of Gio's path and glyph vertex data;
+// allocations larger than this fall back to a plain BufferData call.
+const ringBufferSize = 4 * 1024 * 1024
 
 // State tracking.
 type glstate struct {
@@ -50,6 +69,15 @@ type gpuTimer struct {
 	obj   Query
 }
 
+// gpuOccluder implements gpu.Occluder with an ANY_SAMPLES_PASSED
+// query when available, falling back to a 1x1 ReadPixels when the
+// backend lacks occlusion queries.
+type gpuOccluder struct {
+	backend  *Backend
+	obj      Query
+	fallback [4]byte
+}
+
 type gpuTexture struct {
 	backend *Backend
 	obj     Texture
@@ -59,10 +87,12 @@ type gpuTexture struct {
 }
 
 type gpuFramebuffer struct {
-	backend *Backend
-	obj      Framebuffer
-	hasDepth bool
-	depthBuf Renderbuffer
+	backend   *Backend
+	obj       Framebuffer
+	hasDepth  bool
+	depthBuf  Renderbuffer
+	hasMSAA   bool
+	msaaColor Renderbuffer
 }
 
 type gpuBuffer struct {
@@ -74,6 +104,14 @@ type gpuBuffer struct {
 	version   int
 	// For emulation of uniform buffers.
 	data []byte
+	// ringOffset is where this buffer's most recent Upload landed
+	// in backend.vertexRing, valid only when ringBacked is set.
+	ringOffset int
+	// ringBacked is set for dynamic vertex buffers bump-allocated
+	// from backend.vertexRing rather than owning their own storage.
+	// Immutable buffers are never ring-backed: their contents must
+	// outlive the ring's next wraparound.
+	ringBacked bool
 }
 
 type gpuProgram struct {
@@ -134,10 +172,26 @@ func NewBackend(f Functions) (*Backend, error) {
 		alphaTriple: alphaTripleFor(ver),
 		srgbaTriple: srgbaTriple,
 	}
+	b.preamble = shaderPreamble(ver)
+	b.driver = driverString(f)
+	if cache, err := NewDiskProgramCache(); err == nil {
+		b.cache = cache
+	}
+	persistentMapping := hasExtension(exts, "GL_EXT_buffer_storage") && ver[0] >= 3 && ver[1] >= 1
+	b.vertexRing = newRingBuffer(f, ARRAY_BUFFER, ringBufferSize, persistentMapping)
 	b.defFBO = &gpuFramebuffer{backend: b, obj: defFBO}
 	if hasExtension(exts, "GL_EXT_disjoint_timer_query_webgl2") || hasExtension(exts, "GL_EXT_disjoint_timer_query") {
 		b.feats.Features |= gpu.FeatureTimers
 	}
+	if ver[0] >= 3 && ver[1] >= 1 {
+		b.feats.Features |= gpu.FeatureCompute
+	}
+	if ver[0] >= 3 || hasExtension(exts, "GL_EXT_occlusion_query_boolean") {
+		b.feats.Features |= gpu.FeatureOcclusion
+	}
+	if ver[0] >= 3 {
+		b.feats.Features |= gpu.FeatureFramebufferMultisample
+	}
 	b.feats.MaxTextureSize = f.GetInteger(MAX_TEXTURE_SIZE)
 	return b, nil
 }
@@ -145,10 +199,13 @@ func NewBackend(f Functions) (*Backend, error) {
 func (b *Backend) BeginFrame() {
 	// Assume GL state is reset between frames.
 	b.state = glstate{}
+	pushDebugGroup(b.funcs, "frame")
 }
 
 func (b *Backend) EndFrame() {
 	b.funcs.ActiveTexture(TEXTURE0)
+	b.vertexRing.endFrame()
+	popDebugGroup(b.funcs)
 }
 
 func (b *Backend) Caps() gpu.Caps {
@@ -162,6 +219,17 @@ func (b *Backend) NewTimer() gpu.Timer {
 	}
 }
 
+// NewOccluder creates a GPU occlusion query. When the backend lacks
+// gpu.FeatureOcclusion, it falls back to a 1x1 scissored ReadPixels
+// as an approximation: Samples reports at most 1.
+func (b *Backend) NewOccluder() gpu.Occluder {
+	o := &gpuOccluder{backend: b}
+	if b.feats.Features.Has(gpu.FeatureOcclusion) {
+		o.obj = b.funcs.CreateQuery()
+	}
+	return o
+}
+
 func (b *Backend) IsTimeContinuous() bool {
 	return b.funcs.GetInteger(GPU_DISJOINT_EXT) == FALSE
 }
@@ -202,6 +270,96 @@ func (b *Backend) NewFramebuffer(tex gpu.Texture, depthBits int) (gpu.Framebuffe
 	return fbo, nil
 }
 
+// NewFramebufferMSAA builds a (possibly multisampled, multi-attachment)
+// render target from desc. When desc.Samples > 1, color attachment 0
+// is backed by a multisample renderbuffer sized like desc.Color[0]
+// rather than by desc.Color directly, and the other entries of
+// desc.Color are not attached; resolve it into desc.Color[0] with
+// BlitFramebuffer.
+// It panics if desc.Samples > 1 and the backend doesn't advertise
+// gpu.FeatureFramebufferMultisample.
+func (b *Backend) NewFramebufferMSAA(desc gpu.FramebufferDesc) (gpu.Framebuffer, error) {
+	glErr(b.funcs)
+	if len(desc.Color) == 0 {
+		return nil, errors.New("gl: NewFramebufferMSAA needs at least one color attachment")
+	}
+	if desc.Samples > 1 && !b.feats.Features.Has(gpu.FeatureFramebufferMultisample) {
+		panic("multisampled framebuffers are not supported")
+	}
+	fb := b.funcs.CreateFramebuffer()
+	fbo := &gpuFramebuffer{backend: b, obj: fb}
+	b.BindFramebuffer(fbo)
+	if err := glErr(b.funcs); err != nil {
+		fbo.Release()
+		return nil, err
+	}
+	gltex := desc.Color[0].(*gpuTexture)
+	if desc.Samples > 1 {
+		triple := gltex.triple
+		if desc.SRGB {
+			triple = b.srgbaTriple
+		}
+		rb := b.funcs.CreateRenderbuffer()
+		b.funcs.BindRenderbuffer(RENDERBUFFER, rb)
+		b.funcs.RenderbufferStorageMultisample(RENDERBUFFER, desc.Samples, Enum(triple.internalFormat), gltex.width, gltex.height)
+		b.funcs.FramebufferRenderbuffer(FRAMEBUFFER, COLOR_ATTACHMENT0, RENDERBUFFER, rb)
+		fbo.hasMSAA = true
+		fbo.msaaColor = rb
+	} else {
+		for i, tex := range desc.Color {
+			t := tex.(*gpuTexture)
+			b.funcs.FramebufferTexture2D(FRAMEBUFFER, COLOR_ATTACHMENT0+Enum(i), TEXTURE_2D, t.obj, 0)
+		}
+	}
+	if desc.Depth > 0 {
+		size := Enum(DEPTH_COMPONENT16)
+		switch {
+		case desc.Depth > 24:
+			size = DEPTH_COMPONENT32F
+		case desc.Depth > 16:
+			size = DEPTH_COMPONENT24
+		}
+		depthBuf := b.funcs.CreateRenderbuffer()
+		b.funcs.BindRenderbuffer(RENDERBUFFER, depthBuf)
+		if desc.Samples > 1 {
+			b.funcs.RenderbufferStorageMultisample(RENDERBUFFER, desc.Samples, size, gltex.width, gltex.height)
+		} else {
+			b.funcs.RenderbufferStorage(RENDERBUFFER, size, gltex.width, gltex.height)
+		}
+		b.funcs.FramebufferRenderbuffer(FRAMEBUFFER, DEPTH_ATTACHMENT, RENDERBUFFER, depthBuf)
+		fbo.depthBuf = depthBuf
+		fbo.hasDepth = true
+	}
+	if err := glErr(b.funcs); err != nil {
+		fbo.Release()
+		return nil, err
+	}
+	if st := b.funcs.CheckFramebufferStatus(FRAMEBUFFER); st != FRAMEBUFFER_COMPLETE {
+		fbo.Release()
+		return nil, fmt.Errorf("incomplete framebuffer, status = 0x%x, err = %d", st, b.funcs.GetError())
+	}
+	return fbo, nil
+}
+
+// BlitFramebuffer resolves srcRect of src into dstRect of dst, e.g.
+// to resolve a multisampled render target built by NewFramebufferMSAA.
+// It panics if the backend doesn't advertise
+// gpu.FeatureFramebufferMultisample, since glBlitFramebuffer doesn't
+// exist on GLES 2; callers on such backends must avoid multisampling
+// in the first place rather than resolving it away here.
+func (b *Backend) BlitFramebuffer(dst, src gpu.Framebuffer, srcRect, dstRect image.Rectangle, filter gpu.TextureFilter) {
+	if !b.feats.Features.Has(gpu.FeatureFramebufferMultisample) {
+		panic("BlitFramebuffer is not supported")
+	}
+	b.funcs.BindFramebuffer(READ_FRAMEBUFFER, src.(*gpuFramebuffer).obj)
+	b.funcs.BindFramebuffer(DRAW_FRAMEBUFFER, dst.(*gpuFramebuffer).obj)
+	b.funcs.BlitFramebuffer(
+		srcRect.Min.X, srcRect.Min.Y, srcRect.Max.X, srcRect.Max.Y,
+		dstRect.Min.X, dstRect.Min.Y, dstRect.Max.X, dstRect.Max.Y,
+		COLOR_BUFFER_BIT, Enum(toTexFilter(filter)),
+	)
+}
+
 func (b *Backend) DefaultFramebuffer() gpu.Framebuffer {
 	return b.defFBO
 }
@@ -240,7 +398,12 @@ func (b *Backend) NewBuffer(typ gpu.BufferBinding, size int) (gpu.Buffer, error)
 		// GLES 2 doesn't support uniform buffers.
 		buf.data = make([]byte, size)
 	}
-	if typ&^gpu.BufferBindingUniforms != 0 {
+	if typ == gpu.BufferBindingVertices && size <= b.vertexRing.cap {
+		// Dynamic vertex buffers are bump-allocated from the shared
+		// ring instead of getting their own storage; see Upload.
+		buf.obj = b.vertexRing.obj
+		buf.ringBacked = true
+	} else if typ&^gpu.BufferBindingUniforms != 0 {
 		buf.obj = b.funcs.CreateBuffer()
 		if err := glErr(b.funcs); err != nil {
 			buf.Release()
@@ -263,13 +426,6 @@ func (b *Backend) NewImmutableBuffer(typ gpu.BufferBinding, data []byte) (gpu.Bu
 	return buf, nil
 }
 
-func glErr(f Functions) error {
-	if st := f.GetError(); st != NO_ERROR {
-		return fmt.Errorf("glGetError: %#x", st)
-	}
-	return nil
-}
-
 func (b *Backend) bindTexture(unit int, t *gpuTexture) {
 	if b.state.texUnits[unit] != t {
 		b.funcs.ActiveTexture(TEXTURE0 + Enum(unit))
@@ -416,12 +572,49 @@ func (b *Backend) NewInputLayout(vs gpu.ShaderSources, layout []gpu.InputDesc) (
 	}, nil
 }
 
+// shaderPreamble returns the #version line and VSIN/VSOUT/FSIN/
+// FRAGCOLOR portability macros that let a single gpu.ShaderSources.GLSL
+// body compile as GL 3.3, GLES 3.1 or GLES 2, instead of shadergen
+// having to emit one variant per flavour.
+func shaderPreamble(ver [2]int) string {
+	switch {
+	case ver[0] >= 3 && ver[1] >= 1:
+		return `#version 300 es
+#define VSIN(i) layout(location = i) in
+#define VSOUT out
+#define FSIN in
+out vec4 fragColor;
+#define FRAGCOLOR(c) fragColor = (c)
+`
+	case ver[0] >= 3:
+		return `#version 330
+#define VSIN(i) layout(location = i) in
+#define VSOUT out
+#define FSIN in
+out vec4 fragColor;
+#define FRAGCOLOR(c) fragColor = (c)
+`
+	default:
+		return `#version 100
+#define VSIN(i) attribute
+#define VSOUT varying
+#define FSIN varying
+#define FRAGCOLOR(c) gl_FragColor = (c)
+#define texture texture2D
+`
+	}
+}
+
 func (b *Backend) NewProgram(vssrc, fssrc gpu.ShaderSources) (gpu.Program, error) {
 	attr := make([]string, len(vssrc.Inputs))
 	for _, inp := range vssrc.Inputs {
 		attr[inp.Location] = inp.Name
 	}
-	p, err := CreateProgram(b.funcs, vssrc.GLES2, fssrc.GLES2, attr)
+	vs, fs := vssrc.GLES2, fssrc.GLES2
+	if vssrc.GLSL != "" && fssrc.GLSL != "" {
+		vs, fs = b.preamble+vssrc.GLSL, b.preamble+fssrc.GLSL
+	}
+	p, err := CreateProgram(b.funcs, b.cache, b.driver, ShaderSource{GLSL: vs}, ShaderSource{GLSL: fs}, attr)
 	if err != nil {
 		return nil, err
 	}
@@ -541,6 +734,10 @@ func (b *gpuBuffer) Upload(data []byte) {
 	if b.typ&gpu.BufferBindingUniforms != 0 {
 		copy(b.data, data)
 	}
+	if b.ringBacked {
+		b.ringOffset = b.backend.vertexRing.alloc(data)
+		return
+	}
 	if b.typ&^gpu.BufferBindingUniforms != 0 {
 		firstBinding := firstBufferType(b.typ)
 		b.backend.funcs.BindBuffer(firstBinding, b.obj)
@@ -549,7 +746,9 @@ func (b *gpuBuffer) Upload(data []byte) {
 }
 
 func (b *gpuBuffer) Release() {
-	if b.typ&^gpu.BufferBindingUniforms != 0 {
+	// Ring-backed vertex buffers share backend.vertexRing's object
+	// and don't own it.
+	if !b.ringBacked && b.typ&^gpu.BufferBindingUniforms != 0 {
 		b.backend.funcs.DeleteBuffer(b.obj)
 	}
 }
@@ -559,7 +758,7 @@ func (b *Backend) BindVertexBuffer(buf gpu.Buffer, stride, offset int) {
 	if gbuf.typ&gpu.BufferBindingVertices == 0 {
 		panic("not a vertex buffer")
 	}
-	b.state.buffer = bufferBinding{buf: gbuf, stride: stride, offset: offset}
+	b.state.buffer = bufferBinding{buf: gbuf, stride: stride, offset: offset + gbuf.ringOffset}
 }
 
 func (b *Backend) setupVertexArrays() {
@@ -609,6 +808,9 @@ func (b *Backend) BindFramebuffer(fbo gpu.Framebuffer) {
 func (f *gpuFramebuffer) Invalidate() {
 	f.backend.BindFramebuffer(f)
 	f.backend.funcs.InvalidateFramebuffer(FRAMEBUFFER, COLOR_ATTACHMENT0)
+	if f.hasDepth {
+		f.backend.funcs.InvalidateFramebuffer(FRAMEBUFFER, DEPTH_ATTACHMENT)
+	}
 }
 
 func (f *gpuFramebuffer) Release() {
@@ -616,6 +818,9 @@ func (f *gpuFramebuffer) Release() {
 	if f.hasDepth {
 		f.backend.funcs.DeleteRenderbuffer(f.depthBuf)
 	}
+	if f.hasMSAA {
+		f.backend.funcs.DeleteRenderbuffer(f.msaaColor)
+	}
 }
 
 func toTexFilter(f gpu.TextureFilter) int {
@@ -675,6 +880,44 @@ func (t *gpuTimer) Duration() (time.Duration, bool) {
 	return time.Duration(nanos), true
 }
 
+func (o *gpuOccluder) Begin() {
+	if o.obj.Valid() {
+		o.backend.funcs.BeginQuery(ANY_SAMPLES_PASSED, o.obj)
+	}
+}
+
+func (o *gpuOccluder) End() {
+	if o.obj.Valid() {
+		o.backend.funcs.EndQuery(ANY_SAMPLES_PASSED)
+		return
+	}
+	// Fallback: whatever was drawn since Begin is still in the
+	// current scissor rect and framebuffer; read the single pixel
+	// it was restricted to.
+	o.backend.funcs.ReadPixels(0, 0, 1, 1, RGBA, UNSIGNED_BYTE, o.fallback[:])
+}
+
+func (o *gpuOccluder) Samples() (samples uint64, ready bool) {
+	if !o.obj.Valid() {
+		for _, c := range o.fallback {
+			if c != 0 {
+				return 1, true
+			}
+		}
+		return 0, true
+	}
+	if o.backend.funcs.GetQueryObjectuiv(o.obj, QUERY_RESULT_AVAILABLE) != TRUE {
+		return 0, false
+	}
+	return uint64(o.backend.funcs.GetQueryObjectuiv(o.obj, QUERY_RESULT)), true
+}
+
+func (o *gpuOccluder) Release() {
+	if o.obj.Valid() {
+		o.backend.funcs.DeleteQuery(o.obj)
+	}
+}
+
 func (b *Backend) BindInputLayout(l gpu.InputLayout) {
 	b.state.layout = l.(*gpuInputLayout)
 }
@@ -759,7 +1002,81 @@ func firstBufferType(typ gpu.BufferBinding) Enum {
 		return ARRAY_BUFFER
 	case typ&gpu.BufferBindingUniforms != 0:
 		return UNIFORM_BUFFER
+	case typ&gpu.BufferBindingStorage != 0:
+		return SHADER_STORAGE_BUFFER
 	default:
 		panic("unsupported buffer type")
 	}
 }
+
+func accessEnum(access gpu.AccessType) Enum {
+	switch access {
+	case gpu.AccessReadOnly:
+		return READ_ONLY
+	case gpu.AccessWriteOnly:
+		return WRITE_ONLY
+	case gpu.AccessReadWrite:
+		return READ_WRITE
+	default:
+		panic("unsupported access type")
+	}
+}
+
+// NewComputeProgram compiles and links src as a standalone compute
+// shader program. It panics if the backend doesn't advertise
+// gpu.FeatureCompute.
+func (b *Backend) NewComputeProgram(src gpu.ShaderSources) (gpu.Program, error) {
+	if !b.feats.Features.Has(gpu.FeatureCompute) {
+		panic("compute shaders are not supported")
+	}
+	sh := b.funcs.CreateShader(COMPUTE_SHADER)
+	if !sh.Valid() {
+		return nil, errors.New("gl: CreateShader failed for the compute shader")
+	}
+	b.funcs.ShaderSource(sh, src.GLES2)
+	b.funcs.CompileShader(sh)
+	if b.funcs.GetShaderi(sh, COMPILE_STATUS) == 0 {
+		log := b.funcs.GetShaderInfoLog(sh)
+		b.funcs.DeleteShader(sh)
+		return nil, fmt.Errorf("gl: compute shader compilation failed: %s", strings.TrimSpace(log))
+	}
+	defer b.funcs.DeleteShader(sh)
+	prog := b.funcs.CreateProgram()
+	if !prog.Valid() {
+		return nil, errors.New("gl: CreateProgram failed for the compute program")
+	}
+	b.funcs.AttachShader(prog, sh)
+	b.funcs.LinkProgram(prog)
+	if b.funcs.GetProgrami(prog, LINK_STATUS) == 0 {
+		log := b.funcs.GetProgramInfoLog(prog)
+		b.funcs.DeleteProgram(prog)
+		return nil, fmt.Errorf("gl: compute program link failed: %s", strings.TrimSpace(log))
+	}
+	return &gpuProgram{backend: b, obj: prog}, nil
+}
+
+// BindStorageBuffer binds buf to the indexed SSBO binding point.
+func (b *Backend) BindStorageBuffer(binding int, buf gpu.Buffer) {
+	gbuf := buf.(*gpuBuffer)
+	if gbuf.typ&gpu.BufferBindingStorage == 0 {
+		panic("not a storage buffer")
+	}
+	b.funcs.BindBufferBase(SHADER_STORAGE_BUFFER, binding, gbuf.obj)
+}
+
+// BindImageTexture binds unit of tex, in the given format, for the
+// access pattern the following DispatchCompute call will use.
+func (b *Backend) BindImageTexture(unit int, tex gpu.Texture, access gpu.AccessType, format gpu.TextureFormat) {
+	gtex := tex.(*gpuTexture)
+	b.funcs.BindImageTexture(unit, gtex.obj, 0, false, 0, accessEnum(access), Enum(gtex.triple.internalFormat))
+}
+
+func (b *Backend) DispatchCompute(x, y, z int) {
+	pushDebugGroup(b.funcs, "compute")
+	b.funcs.DispatchCompute(x, y, z)
+	popDebugGroup(b.funcs)
+}
+
+func (b *Backend) MemoryBarrier() {
+	b.funcs.MemoryBarrier(ALL_BARRIER_BITS)
+}
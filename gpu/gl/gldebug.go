@@ -0,0 +1,54 @@
+// SPDX-License-Identifier: Unlicense OR MIT
+
+package gl
+
+import (
+	"fmt"
+	"os"
+)
+
+// debugEnabled controls the KHR_debug-assisted error checking done by
+// glErr and the frame group labels pushed by pushDebugGroup. It is
+// read once from the GIODEBUG environment variable, since toggling it
+// per build would require a build tag for every platform that already
+// has one for its Functions implementation.
+var debugEnabled = os.Getenv("GIODEBUG") != ""
+
+// glErr drains the GL error queue. In debug mode it keeps draining
+// until the queue is empty and panics naming every accumulated error,
+// instead of returning just the first one; this catches errors whose
+// originating call did not bother to check glErr itself.
+func glErr(f Functions) error {
+	st := f.GetError()
+	if st == NO_ERROR {
+		return nil
+	}
+	if !debugEnabled {
+		return fmt.Errorf("glGetError: %s", st)
+	}
+	errs := []Enum{st}
+	for {
+		st := f.GetError()
+		if st == NO_ERROR {
+			break
+		}
+		errs = append(errs, st)
+	}
+	panic(fmt.Sprintf("gl: %v", errs))
+}
+
+// pushDebugGroup labels the GL calls that follow it, up to the
+// matching popDebugGroup, as belonging to the named phase. It is a
+// no-op unless debug mode is enabled, so callers don't need to guard
+// every call site with their own debugEnabled check.
+func pushDebugGroup(f Functions, name string) {
+	if debugEnabled {
+		f.PushDebugGroup(DEBUG_SOURCE_APPLICATION, 0, name)
+	}
+}
+
+func popDebugGroup(f Functions) {
+	if debugEnabled {
+		f.PopDebugGroup()
+	}
+}
@@ -0,0 +1,217 @@
+// SPDX-License-Identifier: Unlicense OR MIT
+
+package gl
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ShaderSource is a single compilation unit for CreateProgram. Exactly
+// one of GLSL, Binary or SPIRV should be set; CreateProgram compiles
+// SPIRV if it is set and GLSL otherwise.
+type ShaderSource struct {
+	// GLSL is compiled with glCompileShader, the slow path every GL
+	// ES 2 driver supports.
+	GLSL string
+	// Binary is a pre-linked program, as previously returned by
+	// glGetProgramBinary, together with the Format glProgramBinary
+	// expects it in. CreateProgram does not load it: it only feeds the
+	// cache key, and linked binaries are reused through the
+	// ProgramCache instead.
+	Binary []byte
+	Format Enum
+	// SPIRV is a SPIR-V module consumed with glShaderBinary and
+	// glSpecializeShader, available on GL ES 3.2 and GL 4.6 drivers.
+	SPIRV []byte
+}
+
+// ProgramCache persists linked program binaries across runs, keyed by
+// the hash of their source and the driver they were linked against.
+// Embedders can supply their own implementation to e.g. ship
+// precompiled shaders in their binary; NewDiskProgramCache is the
+// default, backed by os.UserCacheDir.
+type ProgramCache interface {
+	Load(key string) (binary []byte, format Enum, ok bool)
+	Store(key string, binary []byte, format Enum)
+}
+
+// CreateProgram links vs and fs into a program. If cache has a binary
+// stored under the hash of vs, fs and driver, it is handed to
+// glProgramBinary and the GLSL compilation is skipped entirely;
+// otherwise the program is compiled and linked normally, and the
+// result is read back with glGetProgramBinary and stored in cache for
+// next time.
+func CreateProgram(f Functions, cache ProgramCache, driver string, vs, fs ShaderSource, attribs []string) (Program, error) {
+	key := cacheKey(driver, vs, fs)
+	if cache != nil {
+		if bin, format, ok := cache.Load(key); ok {
+			if p, err := programFromBinary(f, bin, format); err == nil {
+				return p, nil
+			}
+			// The cached binary was rejected, likely because of a
+			// driver update. Fall through and recompile.
+		}
+	}
+	p, err := linkProgram(f, vs, fs, attribs)
+	if err != nil {
+		return Program{}, err
+	}
+	if cache != nil {
+		if bin, format, err := f.GetProgramBinary(p); err == nil {
+			cache.Store(key, bin, format)
+		}
+	}
+	return p, nil
+}
+
+func programFromBinary(f Functions, bin []byte, format Enum) (Program, error) {
+	p := f.CreateProgram()
+	if !p.Valid() {
+		return Program{}, errors.New("gl: CreateProgram failed")
+	}
+	f.ProgramBinary(p, format, bin)
+	if f.GetProgrami(p, LINK_STATUS) == 0 {
+		f.DeleteProgram(p)
+		return Program{}, errors.New("gl: driver rejected cached program binary")
+	}
+	return p, nil
+}
+
+func linkProgram(f Functions, vs, fs ShaderSource, attribs []string) (Program, error) {
+	vsh, err := compileShader(f, VERTEX_SHADER, vs)
+	if err != nil {
+		return Program{}, err
+	}
+	defer f.DeleteShader(vsh)
+	fsh, err := compileShader(f, FRAGMENT_SHADER, fs)
+	if err != nil {
+		return Program{}, err
+	}
+	defer f.DeleteShader(fsh)
+	p := f.CreateProgram()
+	if !p.Valid() {
+		return Program{}, errors.New("gl: CreateProgram failed")
+	}
+	f.AttachShader(p, vsh)
+	f.AttachShader(p, fsh)
+	for i, a := range attribs {
+		f.BindAttribLocation(p, Attrib(i), a)
+	}
+	f.LinkProgram(p)
+	if f.GetProgrami(p, LINK_STATUS) == 0 {
+		log := f.GetProgramInfoLog(p)
+		f.DeleteProgram(p)
+		return Program{}, fmt.Errorf("gl: program link failed: %s", strings.TrimSpace(log))
+	}
+	return p, nil
+}
+
+func compileShader(f Functions, typ Enum, src ShaderSource) (Shader, error) {
+	sh := f.CreateShader(typ)
+	if !sh.Valid() {
+		return Shader{}, errors.New("gl: CreateShader failed")
+	}
+	if len(src.SPIRV) > 0 {
+		f.ShaderBinary([]Shader{sh}, SHADER_BINARY_FORMAT_SPIR_V, src.SPIRV)
+		f.SpecializeShader(sh, "main", nil, nil)
+	} else {
+		f.ShaderSource(sh, src.GLSL)
+		f.CompileShader(sh)
+	}
+	if f.GetShaderi(sh, COMPILE_STATUS) == 0 {
+		log := f.GetShaderInfoLog(sh)
+		f.DeleteShader(sh)
+		return Shader{}, fmt.Errorf("gl: shader compilation failed: %s", strings.TrimSpace(log))
+	}
+	return sh, nil
+}
+
+// driverString identifies the GL implementation a program was linked
+// against, so NewBackend can key ProgramCache entries by it and never
+// hand a cached binary from one driver to another.
+func driverString(f Functions) string {
+	return f.GetString(VENDOR) + "\x00" + f.GetString(RENDERER) + "\x00" + f.GetString(VERSION)
+}
+
+func cacheKey(driver string, vs, fs ShaderSource) string {
+	h := sha256.New()
+	io.WriteString(h, driver)
+	h.Write([]byte{0})
+	writeSourceHash(h, vs)
+	h.Write([]byte{0})
+	writeSourceHash(h, fs)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func writeSourceHash(h io.Writer, src ShaderSource) {
+	switch {
+	case len(src.SPIRV) > 0:
+		h.Write(src.SPIRV)
+	case len(src.Binary) > 0:
+		h.Write(src.Binary)
+	default:
+		io.WriteString(h, src.GLSL)
+	}
+}
+
+// diskProgramCache is the default ProgramCache, storing binaries as
+// plain files under os.UserCacheDir.
+type diskProgramCache struct {
+	dir string
+}
+
+// NewDiskProgramCache returns a ProgramCache backed by
+// os.UserCacheDir. It returns an error only if the cache directory
+// could not be created; callers should treat that as non-fatal and
+// fall back to a nil ProgramCache, which CreateProgram accepts.
+func NewDiskProgramCache() (ProgramCache, error) {
+	base, err := os.UserCacheDir()
+	if err != nil {
+		return nil, fmt.Errorf("gl: NewDiskProgramCache: %w", err)
+	}
+	dir := filepath.Join(base, "gio-shaders")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("gl: NewDiskProgramCache: %w", err)
+	}
+	return &diskProgramCache{dir: dir}, nil
+}
+
+func (c *diskProgramCache) Load(key string) (binary []byte, format Enum, ok bool) {
+	bin, err := os.ReadFile(c.path(key))
+	if err != nil || len(bin) < 4 {
+		return nil, 0, false
+	}
+	format = Enum(be32(bin))
+	return bin[4:], format, true
+}
+
+func (c *diskProgramCache) Store(key string, binary []byte, format Enum) {
+	buf := make([]byte, 4+len(binary))
+	putBE32(buf, uint32(format))
+	copy(buf[4:], binary)
+	// Best-effort: a failed write just means the next startup
+	// recompiles instead of reusing the cached program.
+	os.WriteFile(c.path(key), buf, 0o644)
+}
+
+func (c *diskProgramCache) path(key string) string {
+	return filepath.Join(c.dir, key+".bin")
+}
+
+func be32(b []byte) uint32 {
+	return uint32(b[0])<<24 | uint32(b[1])<<16 | uint32(b[2])<<8 | uint32(b[3])
+}
+
+func putBE32(b []byte, v uint32) {
+	b[0] = byte(v >> 24)
+	b[1] = byte(v >> 16)
+	b[2] = byte(v >> 8)
+	b[3] = byte(v)
+}
@@ -0,0 +1,94 @@
+// SPDX-License-Identifier: Unlicense OR MIT
+
+package gl
+
+// ringBuffer bump-allocates sub-ranges of a single, persistent GL
+// buffer object, modelled on Dawn's OpenGL backend. It replaces
+// reallocating storage with BufferData on every dynamic Upload: one
+// large buffer is allocated once, and each Upload claims the next
+// free range instead.
+//
+// When persistent mapping is available (GLES 3.1 / GL 4.4 and up),
+// the ring is mapped once with MAP_PERSISTENT_BIT|MAP_COHERENT_BIT
+// and writes go straight into that slice. Otherwise each allocation
+// falls back to BufferSubData, which is still cheaper than the
+// STATIC_DRAW reupload it replaces since the driver no longer has to
+// reallocate backing storage.
+type ringBuffer struct {
+	funcs      Functions
+	target     Enum
+	obj        Buffer
+	cap        int
+	head       int
+	persistent []byte
+	pending    []ringFence
+}
+
+// ringFence records that the GPU may still be reading the ring up to
+// the point it was created.
+type ringFence struct {
+	sync Sync
+}
+
+func newRingBuffer(funcs Functions, target Enum, capacity int, persistentMapping bool) *ringBuffer {
+	obj := funcs.CreateBuffer()
+	funcs.BindBuffer(target, obj)
+	r := &ringBuffer{funcs: funcs, target: target, obj: obj, cap: capacity}
+	access := Enum(MAP_WRITE_BIT)
+	if persistentMapping {
+		access |= MAP_PERSISTENT_BIT | MAP_COHERENT_BIT
+	}
+	funcs.BufferData(target, make([]byte, capacity), DYNAMIC_DRAW)
+	if persistentMapping {
+		r.persistent = funcs.MapBufferRange(target, 0, capacity, access)
+	}
+	return r
+}
+
+// alloc claims the next free range of len(data) bytes, stalling the
+// CPU on wraparound until the GPU has finished every frame fenced so
+// far, and returns the ring offset the caller should bind at.
+func (r *ringBuffer) alloc(data []byte) (offset int) {
+	size := len(data)
+	if size > r.cap {
+		panic("gl: ring buffer allocation larger than its capacity")
+	}
+	if r.head+size > r.cap {
+		r.head = 0
+		r.waitPending()
+	}
+	offset = r.head
+	r.head += size
+	if r.persistent != nil {
+		copy(r.persistent[offset:], data)
+	} else {
+		r.funcs.BindBuffer(r.target, r.obj)
+		r.funcs.BufferSubData(r.target, offset, data)
+	}
+	return offset
+}
+
+// endFrame fences the ring's current contents so a future wraparound
+// knows to wait for the GPU to finish with them.
+func (r *ringBuffer) endFrame() {
+	r.pending = append(r.pending, ringFence{sync: r.funcs.FenceSync(SYNC_GPU_COMMANDS_COMPLETE, 0)})
+}
+
+func (r *ringBuffer) waitPending() {
+	for _, f := range r.pending {
+		r.funcs.ClientWaitSync(f.sync, SYNC_FLUSH_COMMANDS_BIT, ^uint64(0))
+		r.funcs.DeleteSync(f.sync)
+	}
+	r.pending = r.pending[:0]
+}
+
+func (r *ringBuffer) release() {
+	if r.persistent != nil {
+		r.funcs.BindBuffer(r.target, r.obj)
+		r.funcs.UnmapBuffer(r.target)
+	}
+	for _, f := range r.pending {
+		r.funcs.DeleteSync(f.sync)
+	}
+	r.funcs.DeleteBuffer(r.obj)
+}
@@ -9,6 +9,7 @@ import (
 	"os/exec"
 	"path/filepath"
 	"sort"
+	"strconv"
 	"strings"
 
 	"gioui.org/gpu/internal/driver"
@@ -31,7 +32,14 @@ type Metadata struct {
 	StorageBuffers []driver.StorageBufferBinding
 }
 
-// Convert converts input data to the target shader.
+// Convert converts input data to the target shader. For a .comp shader,
+// lang and profile select the cross-compiled target the same way as for
+// vertex and fragment shaders (e.g. "hlsl"/"50" for D3D11 UAVs, "vulkan" for
+// SPIR-V storage buffers, "glsl"/"430" for GL SSBOs); glslcc preserves the
+// binding and block size reflected in Metadata.StorageBuffers across all of
+// them, so callers only need to bind driver.StorageBufferBinding.Binding
+// consistently regardless of target. Only the GL path is implemented by a
+// Device in this tree; the D3D11 and Vulkan backends are not present.
 func (glslcc *GLSLCC) Convert(path, variant string, input []byte, lang, profile string) (_ string, _ Metadata, err error) {
 	base := glslcc.WorkDir.Path(filepath.Base(path), variant, lang, profile)
 	pathin := base + ".in"
@@ -159,7 +167,7 @@ func (glslcc *GLSLCC) parseReflection(jsonData []byte) (Metadata, error) {
 
 	inputRef := reflect.VS.Inputs
 	for _, input := range inputRef {
-		dataType, dataSize, err := parseDataType(input.Type)
+		dataType, dataSize, _, err := parseDataType(input.Type)
 		if err != nil {
 			return info, fmt.Errorf("parseReflection: %v", err)
 		}
@@ -188,7 +196,7 @@ func (glslcc *GLSLCC) parseReflection(jsonData []byte) (Metadata, error) {
 			Binding: block.Binding,
 		})
 		for _, member := range block.Members {
-			dataType, size, err := parseDataType(member.Type)
+			dataType, size, array, err := parseDataType(member.Type)
 			if err != nil {
 				return info, fmt.Errorf("parseReflection: %v", err)
 			}
@@ -198,6 +206,7 @@ func (glslcc *GLSLCC) parseReflection(jsonData []byte) (Metadata, error) {
 				Type:   dataType,
 				Size:   size,
 				Offset: blockOffset + member.Offset,
+				Array:  array,
 			})
 		}
 		blockOffset += block.Size
@@ -217,6 +226,7 @@ func (glslcc *GLSLCC) parseReflection(jsonData []byte) (Metadata, error) {
 
 	for _, sb := range reflect.CS.StorageBuffers {
 		info.StorageBuffers = append(info.StorageBuffers, driver.StorageBufferBinding{
+			Name:      sb.Name,
 			Binding:   sb.Binding,
 			BlockSize: sb.BlockSize,
 		})
@@ -225,25 +235,54 @@ func (glslcc *GLSLCC) parseReflection(jsonData []byte) (Metadata, error) {
 	return info, nil
 }
 
-func parseDataType(t string) (driver.DataType, int, error) {
-	switch t {
+// parseDataType parses a glslcc reflection type string such as "float4",
+// "int2", "mat4" or an array thereof such as "float4[8]". The returned array
+// count is 0 for non-array types.
+func parseDataType(t string) (driver.DataType, int, int, error) {
+	base := t
+	array := 0
+	if i := strings.IndexByte(t, '['); i >= 0 {
+		if !strings.HasSuffix(t, "]") {
+			return 0, 0, 0, fmt.Errorf("unsupported input data type: %s", t)
+		}
+		n, err := strconv.Atoi(t[i+1 : len(t)-1])
+		if err != nil || n <= 0 {
+			return 0, 0, 0, fmt.Errorf("unsupported input data type: %s", t)
+		}
+		base, array = t[:i], n
+	}
+	switch base {
 	case "float":
-		return driver.DataTypeFloat, 1, nil
+		return driver.DataTypeFloat, 1, array, nil
 	case "float2":
-		return driver.DataTypeFloat, 2, nil
+		return driver.DataTypeFloat, 2, array, nil
 	case "float3":
-		return driver.DataTypeFloat, 3, nil
+		return driver.DataTypeFloat, 3, array, nil
 	case "float4":
-		return driver.DataTypeFloat, 4, nil
+		return driver.DataTypeFloat, 4, array, nil
 	case "int":
-		return driver.DataTypeInt, 1, nil
+		return driver.DataTypeInt, 1, array, nil
 	case "int2":
-		return driver.DataTypeInt, 2, nil
+		return driver.DataTypeInt, 2, array, nil
 	case "int3":
-		return driver.DataTypeInt, 3, nil
+		return driver.DataTypeInt, 3, array, nil
 	case "int4":
-		return driver.DataTypeInt, 4, nil
+		return driver.DataTypeInt, 4, array, nil
+	case "uint":
+		return driver.DataTypeUint, 1, array, nil
+	case "uint2":
+		return driver.DataTypeUint, 2, array, nil
+	case "uint3":
+		return driver.DataTypeUint, 3, array, nil
+	case "uint4":
+		return driver.DataTypeUint, 4, array, nil
+	case "mat2":
+		return driver.DataTypeMat2, 2, array, nil
+	case "mat3":
+		return driver.DataTypeMat3, 3, array, nil
+	case "mat4":
+		return driver.DataTypeMat4, 4, array, nil
 	default:
-		return 0, 0, fmt.Errorf("unsupported input data type: %s", t)
+		return 0, 0, 0, fmt.Errorf("unsupported input data type: %s", t)
 	}
 }
@@ -0,0 +1,133 @@
+// SPDX-License-Identifier: Unlicense OR MIT
+
+package main
+
+import (
+	"testing"
+
+	"gioui.org/gpu/internal/driver"
+)
+
+func TestParseDataType(t *testing.T) {
+	tests := []struct {
+		in    string
+		typ   driver.DataType
+		size  int
+		array int
+	}{
+		{"float", driver.DataTypeFloat, 1, 0},
+		{"float2", driver.DataTypeFloat, 2, 0},
+		{"float3", driver.DataTypeFloat, 3, 0},
+		{"float4", driver.DataTypeFloat, 4, 0},
+		{"int", driver.DataTypeInt, 1, 0},
+		{"int4", driver.DataTypeInt, 4, 0},
+		{"uint", driver.DataTypeUint, 1, 0},
+		{"uint3", driver.DataTypeUint, 3, 0},
+		{"mat2", driver.DataTypeMat2, 2, 0},
+		{"mat3", driver.DataTypeMat3, 3, 0},
+		{"mat4", driver.DataTypeMat4, 4, 0},
+		{"float4[8]", driver.DataTypeFloat, 4, 8},
+		{"int[16]", driver.DataTypeInt, 1, 16},
+		{"mat4[2]", driver.DataTypeMat4, 4, 2},
+	}
+	for _, tc := range tests {
+		typ, size, array, err := parseDataType(tc.in)
+		if err != nil {
+			t.Errorf("parseDataType(%q): unexpected error: %v", tc.in, err)
+			continue
+		}
+		if typ != tc.typ || size != tc.size || array != tc.array {
+			t.Errorf("parseDataType(%q) = (%v, %d, %d), want (%v, %d, %d)", tc.in, typ, size, array, tc.typ, tc.size, tc.array)
+		}
+	}
+}
+
+func TestParseDataTypeInvalid(t *testing.T) {
+	for _, in := range []string{"double", "float5", "float4[", "float4[0]", "float4[x]"} {
+		if _, _, _, err := parseDataType(in); err == nil {
+			t.Errorf("parseDataType(%q): expected error, got none", in)
+		}
+	}
+}
+
+func TestParseReflectionUniformTypes(t *testing.T) {
+	const reflectJSON = `{
+		"vs": {
+			"uniform_buffers": [
+				{
+					"id": 0,
+					"name": "Block",
+					"binding": 0,
+					"block_size": 112,
+					"members": [
+						{"name": "pos", "type": "float4", "offset": 0},
+						{"name": "transform", "type": "mat4", "offset": 16},
+						{"name": "flags", "type": "int", "offset": 80},
+						{"name": "mask", "type": "uint2", "offset": 84},
+						{"name": "weights", "type": "float4[2]", "offset": 96}
+					]
+				}
+			]
+		}
+	}`
+
+	glslcc := &GLSLCC{}
+	meta, err := glslcc.parseReflection([]byte(reflectJSON))
+	if err != nil {
+		t.Fatalf("parseReflection: %v", err)
+	}
+	if len(meta.Uniforms.Blocks) != 1 || meta.Uniforms.Blocks[0].Name != "Block" {
+		t.Fatalf("unexpected blocks: %+v", meta.Uniforms.Blocks)
+	}
+	want := []driver.UniformLocation{
+		{Name: "_0.pos", Type: driver.DataTypeFloat, Size: 4, Offset: 0},
+		{Name: "_0.transform", Type: driver.DataTypeMat4, Size: 4, Offset: 16},
+		{Name: "_0.flags", Type: driver.DataTypeInt, Size: 1, Offset: 80},
+		{Name: "_0.mask", Type: driver.DataTypeUint, Size: 2, Offset: 84},
+		{Name: "_0.weights", Type: driver.DataTypeFloat, Size: 4, Offset: 96, Array: 2},
+	}
+	locs := meta.Uniforms.Locations
+	if len(locs) != len(want) {
+		t.Fatalf("got %d locations, want %d", len(locs), len(want))
+	}
+	for i, w := range want {
+		if locs[i] != w {
+			t.Errorf("location %d = %+v, want %+v", i, locs[i], w)
+		}
+	}
+	if meta.Uniforms.Size != 112 {
+		t.Errorf("Uniforms.Size = %d, want 112", meta.Uniforms.Size)
+	}
+}
+
+// TestParseReflectionStorageBuffers covers the reflection glslcc emits for a
+// compute shader's storage buffers, e.g. the input and output buffers of a
+// prefix-sum pass.
+func TestParseReflectionStorageBuffers(t *testing.T) {
+	const reflectJSON = `{
+		"cs": {
+			"storage_buffers": [
+				{"id": 0, "name": "InputBuf", "binding": 0, "block_size": 4},
+				{"id": 1, "name": "OutputBuf", "binding": 1, "block_size": 4}
+			]
+		}
+	}`
+
+	glslcc := &GLSLCC{}
+	meta, err := glslcc.parseReflection([]byte(reflectJSON))
+	if err != nil {
+		t.Fatalf("parseReflection: %v", err)
+	}
+	want := []driver.StorageBufferBinding{
+		{Name: "InputBuf", Binding: 0, BlockSize: 4},
+		{Name: "OutputBuf", Binding: 1, BlockSize: 4},
+	}
+	if len(meta.StorageBuffers) != len(want) {
+		t.Fatalf("got %d storage buffers, want %d", len(meta.StorageBuffers), len(want))
+	}
+	for i, w := range want {
+		if meta.StorageBuffers[i] != w {
+			t.Errorf("storage buffer %d = %+v, want %+v", i, meta.StorageBuffers[i], w)
+		}
+	}
+}
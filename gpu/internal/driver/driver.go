@@ -43,7 +43,6 @@ type Device interface {
 	BindImageTexture(unit int, texture Texture, access AccessBits, format TextureFormat)
 	BindVertexUniforms(buf Buffer)
 	BindFragmentUniforms(buf Buffer)
-	BindStorageBuffer(binding int, buf Buffer)
 
 	CopyTexture(dst Texture, dstOrigin image.Point, src Framebuffer, srcRect image.Rectangle)
 	MemoryBarrier()
@@ -92,6 +91,77 @@ type BlendDesc struct {
 	SrcFactor, DstFactor BlendFactor
 }
 
+// UniformsReflection describes the uniform blocks and members of a shader,
+// as reported by shader cross-compiler reflection.
+type UniformsReflection struct {
+	Blocks    []UniformBlock
+	Locations []UniformLocation
+	Size      int
+}
+
+type UniformBlock struct {
+	Name    string
+	Binding int
+}
+
+// UniformLocation describes a single uniform variable within a block.
+type UniformLocation struct {
+	Name string
+	Type DataType
+	// Size is the number of vector components (1-4), or the number of
+	// columns for a matrix type (2-4).
+	Size   int
+	Offset int
+	// Array is the number of elements if Name refers to an array uniform,
+	// or 0 for a scalar, vector or matrix uniform.
+	Array int
+}
+
+type InputLocation struct {
+	// For GLSL.
+	Name     string
+	Location int
+	// For HLSL.
+	Semantic      string
+	SemanticIndex int
+
+	Type DataType
+	Size int
+}
+
+type TextureBinding struct {
+	Name    string
+	Binding int
+}
+
+// StorageBufferBinding describes a shader storage buffer binding, as
+// reported by shader cross-compiler reflection for a compute shader. Binding
+// is the index backends bind the corresponding Buffer to, e.g. through
+// Program.SetStorageBuffer or the GL SHADER_STORAGE_BUFFER binding point.
+type StorageBufferBinding struct {
+	Name      string
+	Binding   int
+	BlockSize int
+}
+
+// DataType is the scalar or vector element type of a uniform or vertex
+// input, as reported by shader reflection.
+type DataType uint8
+
+const (
+	DataTypeFloat DataType = iota
+	DataTypeInt
+	DataTypeUint
+	DataTypeShort
+	// DataTypeMat2, DataTypeMat3 and DataTypeMat4 are column-major square
+	// matrices; Size on their UniformLocation is the column count and each
+	// column occupies a 16-byte (vec4) slot in std140 layout, so mat3
+	// occupies 3x vec4 rather than 3x vec3.
+	DataTypeMat2
+	DataTypeMat3
+	DataTypeMat4
+)
+
 type AccessBits uint8
 
 type BlendFactor uint8
@@ -125,6 +195,9 @@ type FragmentShader interface {
 
 type Program interface {
 	Release()
+	// SetStorageBuffer binds buf to binding for the next DispatchCompute,
+	// as reported by shader reflection in StorageBufferBinding.Binding.
+	SetStorageBuffer(binding int, buf Buffer)
 }
 
 type Buffer interface {
@@ -159,6 +232,10 @@ const (
 	BufferBindingFramebuffer
 	BufferBindingShaderStorageRead
 	BufferBindingShaderStorageWrite
+	// BufferBindingShaderStorage is the usage for a storage buffer bound
+	// for both reading and writing, the common case for a compute shader's
+	// input/output buffer.
+	BufferBindingShaderStorage = BufferBindingShaderStorageRead | BufferBindingShaderStorageWrite
 )
 
 const (
@@ -148,6 +148,18 @@ type uniformLocation struct {
 	size    int
 }
 
+// std140Stride returns the byte distance between consecutive elements of an
+// array of typ in std140 layout: vec4 (16 bytes) for scalars and vectors,
+// and size vec4s for a DataTypeMatN of size N columns.
+func std140Stride(typ driver.DataType, size int) int {
+	switch typ {
+	case driver.DataTypeMat2, driver.DataTypeMat3, driver.DataTypeMat4:
+		return size * 16
+	default:
+		return 16
+	}
+}
+
 type gpuInputLayout struct {
 	inputs []driver.InputLocation
 	layout []driver.InputDesc
@@ -998,14 +1010,18 @@ func (b *Backend) NewProgram(vertShader, fragShader driver.ShaderSources) (drive
 	return gpuProg, nil
 }
 
-func lookupUniform(funcs *gl.Functions, p gl.Program, loc driver.UniformLocation) uniformLocation {
-	u := funcs.GetUniformLocation(p, loc.Name)
+func lookupUniform(funcs *gl.Functions, p gl.Program, name string, offset int, typ driver.DataType, size int) uniformLocation {
+	u := funcs.GetUniformLocation(p, name)
 	if !u.Valid() {
-		panic(fmt.Errorf("uniform %q not found", loc.Name))
+		panic(fmt.Errorf("uniform %q not found", name))
 	}
-	return uniformLocation{uniform: u, offset: loc.Offset, typ: loc.Type, size: loc.Size}
+	return uniformLocation{uniform: u, offset: offset, typ: typ, size: size}
 }
 
+// SetStorageBuffer binds buffer to binding, the index reported as
+// driver.StorageBufferBinding.Binding by GLSLCC reflection for the compute
+// shader's storage blocks. DispatchCompute binds each entry to
+// GL_SHADER_STORAGE_BUFFER at the same index right before dispatching.
 func (p *gpuProgram) SetStorageBuffer(binding int, buffer driver.Buffer) {
 	buf := buffer.(*gpuBuffer)
 	if buf.typ&driver.BufferBindingShaderStorage == 0 {
@@ -1047,9 +1063,20 @@ func (p *gpuProgram) Release() {
 }
 
 func (u *uniformsTracker) setup(funcs *gl.Functions, p gl.Program, uniformSize int, uniforms []driver.UniformLocation) {
-	u.locs = make([]uniformLocation, len(uniforms))
-	for i, uniform := range uniforms {
-		u.locs[i] = lookupUniform(funcs, p, uniform)
+	u.locs = u.locs[:0]
+	for _, uniform := range uniforms {
+		if uniform.Array == 0 {
+			u.locs = append(u.locs, lookupUniform(funcs, p, uniform.Name, uniform.Offset, uniform.Type, uniform.Size))
+			continue
+		}
+		// Array uniforms don't have a single GLSL location: each element is
+		// looked up and uploaded independently, at its own std140 stride.
+		stride := std140Stride(uniform.Type, uniform.Size)
+		for i := 0; i < uniform.Array; i++ {
+			name := fmt.Sprintf("%s[%d]", uniform.Name, i)
+			offset := uniform.Offset + i*stride
+			u.locs = append(u.locs, lookupUniform(funcs, p, name, offset, uniform.Type, uniform.Size))
+		}
 	}
 	u.size = uniformSize
 }
@@ -1093,6 +1120,58 @@ func (p *uniformsTracker) update(funcs *gl.Functions) {
 			data := data[:16]
 			v := *(*[4]float32)(unsafe.Pointer(&data[0]))
 			funcs.Uniform4f(u.uniform, v[0], v[1], v[2], v[3])
+		case u.typ == driver.DataTypeInt && u.size == 1:
+			data := data[:4]
+			v := *(*[1]int32)(unsafe.Pointer(&data[0]))
+			funcs.Uniform1i(u.uniform, int(v[0]))
+		case u.typ == driver.DataTypeInt && u.size == 2:
+			data := data[:8]
+			v := *(*[2]int32)(unsafe.Pointer(&data[0]))
+			funcs.Uniform2i(u.uniform, v[0], v[1])
+		case u.typ == driver.DataTypeInt && u.size == 3:
+			data := data[:12]
+			v := *(*[3]int32)(unsafe.Pointer(&data[0]))
+			funcs.Uniform3i(u.uniform, v[0], v[1], v[2])
+		case u.typ == driver.DataTypeInt && u.size == 4:
+			data := data[:16]
+			v := *(*[4]int32)(unsafe.Pointer(&data[0]))
+			funcs.Uniform4i(u.uniform, v[0], v[1], v[2], v[3])
+		case u.typ == driver.DataTypeUint && u.size == 1:
+			data := data[:4]
+			v := *(*[1]uint32)(unsafe.Pointer(&data[0]))
+			funcs.Uniform1ui(u.uniform, v[0])
+		case u.typ == driver.DataTypeUint && u.size == 2:
+			data := data[:8]
+			v := *(*[2]uint32)(unsafe.Pointer(&data[0]))
+			funcs.Uniform2ui(u.uniform, v[0], v[1])
+		case u.typ == driver.DataTypeUint && u.size == 3:
+			data := data[:12]
+			v := *(*[3]uint32)(unsafe.Pointer(&data[0]))
+			funcs.Uniform3ui(u.uniform, v[0], v[1], v[2])
+		case u.typ == driver.DataTypeUint && u.size == 4:
+			data := data[:16]
+			v := *(*[4]uint32)(unsafe.Pointer(&data[0]))
+			funcs.Uniform4ui(u.uniform, v[0], v[1], v[2], v[3])
+		case u.typ == driver.DataTypeMat2:
+			data := data[:2*16]
+			// Each std140 column fills a vec4 slot; UniformMatrixNfv
+			// takes the N components of each column packed together.
+			var v [2 * 2]float32
+			for c := 0; c < 2; c++ {
+				copy(v[c*2:], (*[4]float32)(unsafe.Pointer(&data[c*16]))[:2])
+			}
+			funcs.UniformMatrix2fv(u.uniform, v[:])
+		case u.typ == driver.DataTypeMat3:
+			data := data[:3*16]
+			var v [3 * 3]float32
+			for c := 0; c < 3; c++ {
+				copy(v[c*3:], (*[4]float32)(unsafe.Pointer(&data[c*16]))[:3])
+			}
+			funcs.UniformMatrix3fv(u.uniform, v[:])
+		case u.typ == driver.DataTypeMat4:
+			data := data[:4*16]
+			v := *(*[4 * 4]float32)(unsafe.Pointer(&data[0]))
+			funcs.UniformMatrix4fv(u.uniform, v[:])
 		default:
 			panic("unsupported uniform data type or size")
 		}
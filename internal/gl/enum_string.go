@@ -0,0 +1,28 @@
+// SPDX-License-Identifier: Unlicense OR MIT
+
+package gl
+
+import "strconv"
+
+// enumNames maps the subset of Enum values that show up in error
+// reports and debug labels to their symbolic names. It is not
+// exhaustive; String falls back to the numeric value for anything
+// not listed here.
+var enumNames = map[Enum]string{
+	NO_ERROR:                      "NO_ERROR",
+	INVALID_ENUM:                  "INVALID_ENUM",
+	INVALID_VALUE:                 "INVALID_VALUE",
+	INVALID_OPERATION:             "INVALID_OPERATION",
+	OUT_OF_MEMORY:                 "OUT_OF_MEMORY",
+	INVALID_FRAMEBUFFER_OPERATION: "INVALID_FRAMEBUFFER_OPERATION",
+	FRAMEBUFFER_COMPLETE:          "FRAMEBUFFER_COMPLETE",
+}
+
+// String returns the symbolic name of e, if known, and its
+// hexadecimal value otherwise.
+func (e Enum) String() string {
+	if name, ok := enumNames[e]; ok {
+		return name
+	}
+	return "0x" + strconv.FormatUint(uint64(e), 16)
+}
@@ -21,8 +21,16 @@ func translateArgType(s string) (f string, offset string) {
 		return "gioLoadInt32", "8"
 	case "[]byte":
 		return "gioLoadSlice", "24"
+	case "[]float32":
+		return "gioLoadFloat32Slice", "24"
+	case "[]int32":
+		return "gioLoadInt32Slice", "24"
+	case "[]uint32":
+		return "gioLoadUint32Slice", "24"
 	case "float", "float64", "float32":
 		return "gioLoadFloat64", "8"
+	case "js.Value", "Buffer", "Framebuffer", "Program", "Renderbuffer", "Shader", "Texture", "Query", "Uniform", "VertexArray", "Object":
+		return "gioLoadObject", "8"
 	default:
 		return "gioLoadObject", "8"
 	}
@@ -34,11 +42,28 @@ func translateResultType(s string) (f string, offset string) {
 		return "gioSetInt64", "8"
 	case "[4]float32", "[4]int":
 		return "gioSetArray4", "32"
+	case "js.Value", "Buffer", "Framebuffer", "Program", "Renderbuffer", "Shader", "Texture", "Query", "Uniform", "VertexArray", "Object":
+		return "gioSetObject", "8"
 	default:
 		return "gioSetObject", "8"
 	}
 }
 
+// splitResultTypes splits a trimmed result type such as "int" or the tuple
+// "(int, bool)" into its component types. A single, non-tuple type is
+// returned as a slice of length 1.
+func splitResultTypes(result string) []string {
+	if !strings.HasPrefix(result, "(") || !strings.HasSuffix(result, ")") {
+		return []string{result}
+	}
+	inner := strings.TrimSuffix(strings.TrimPrefix(result, "("), ")")
+	parts := strings.Split(inner, ",")
+	for i, p := range parts {
+		parts[i] = strings.TrimSpace(p)
+	}
+	return parts
+}
+
 func main() {
 
 	data, err := ioutil.ReadFile("Z:\\gio\\gio-3\\internal\\gl\\gl_syscall_js.go")
@@ -61,6 +86,11 @@ func main() {
 		panic(err)
 	}
 
+	test, err := os.Create("Z:\\gio\\gio-3\\internal\\gl\\gl_unsafe_js_test.go")
+	if err != nil {
+		panic(err)
+	}
+
 	findFunctions := regexp.MustCompile(`\(f \*FunctionCaller\) (\w+)\((.*)\) (.*?){`).FindAllSubmatch(data, -1)
 	findCalls := regexp.MustCompile(`f.Ctx.Call\((.*)\)`).FindAllSubmatch(data, -1)
 
@@ -68,6 +98,7 @@ func main() {
 
 	writeHeader(js)
 	writeGoHeader(golang)
+	writeTestHeader(test)
 
 	asm.WriteString(`// SPDX-License-Identifier: Unlicense OR MIT
 
@@ -179,10 +210,25 @@ func main() {
 		if len(v) > 3 {
 			result := strings.TrimSpace(string(v[3]))
 			if len(result) > 0 {
-				f, _ := translateResultType(result)
-
-				resultGoType = result
-				resultJS = fmt.Sprintf("%s((go._inst.exports.getsp() >>> 0)+%s, r)", f, offset)
+				results := splitResultTypes(result)
+				if len(results) == 1 {
+					f, _ := translateResultType(results[0])
+					resultGoType = results[0]
+					resultJS = fmt.Sprintf("%s((go._inst.exports.getsp() >>> 0)+%s, r)", f, offset)
+				} else {
+					// Multiple return values are unpacked from the JS call's
+					// array-like result, one gioSet* write per value at
+					// consecutive stack slots following the arguments.
+					resultGoType = "(" + strings.Join(results, ", ") + ")"
+					var sets []string
+					resOffset := offset
+					for i, rt := range results {
+						f, o := translateResultType(rt)
+						sets = append(sets, fmt.Sprintf("            %s((go._inst.exports.getsp() >>> 0)+%s, r[%d]);", f, resOffset, i))
+						resOffset += "+" + o
+					}
+					resultJS = strings.TrimSpace(strings.Join(sets, "\n"))
+				}
 				resultGo = "return "
 			}
 			if strings.Contains(string(v[1]), "Delete") {
@@ -219,6 +265,8 @@ func %s
   RET
 
 `, "asm"+string(v[1]))
+
+		writeTestCase(test, string(v[1]), strings.Replace(strings.Replace(call[0], `"`, "", -1), `)`, ``, -1), asmArgs)
 	}
 	writeEnd(js)
 }
@@ -273,6 +321,30 @@ func writeHeader(f io.StringWriter) {
         }
         return s
     }
+    // gioLoadFloat32Slice, gioLoadInt32Slice and gioLoadUint32Slice view the
+    // Go slice directly in go._inst.exports.mem.buffer, like gioLoadSlice,
+    // so uploading a slice never copies it.
+    const gioLoadFloat32Slice = (addr) => {
+        const len = gioLoadInt64(addr + 8)
+        if (len === 0) {
+            return null
+        }
+        return new Float32Array(go._inst.exports.mem.buffer, gioLoadInt64(addr), len)
+    }
+    const gioLoadInt32Slice = (addr) => {
+        const len = gioLoadInt64(addr + 8)
+        if (len === 0) {
+            return null
+        }
+        return new Int32Array(go._inst.exports.mem.buffer, gioLoadInt64(addr), len)
+    }
+    const gioLoadUint32Slice = (addr) => {
+        const len = gioLoadInt64(addr + 8)
+        if (len === 0) {
+            return null
+        }
+        return new Uint32Array(go._inst.exports.mem.buffer, gioLoadInt64(addr), len)
+    }
     const gioLoadFloat64 = (addr) => {
         return go.mem.getFloat64(addr + 8, true);
     }
@@ -339,3 +411,108 @@ func NewFunctionCaller(ctx Context) *FunctionCaller {
 `)
 
 }
+
+// writeTestHeader starts gl_unsafe_js_test.go, which round-trips every
+// generated wrapper through a fake WebGL context: each case calls the
+// wrapper with sample arguments and checks that the underlying JS method
+// was invoked with the same name and argument count the generator derived
+// from gl_syscall_js.go, catching offset or stride mistakes that would
+// otherwise silently miscompile.
+func writeTestHeader(file io.StringWriter) {
+	file.WriteString(`//+build unsafe
+// SPDX-License-Identifier: Unlicense OR MIT
+
+package gl
+
+import (
+	"syscall/js"
+	"testing"
+)
+
+// fakeWebGL records the name and argument count of the last call made
+// through it, standing in for the real WebGL context object that
+// globalThis.setUnsafeGL normally receives.
+func newFakeWebGL(t *testing.T, record func(method string, args []js.Value)) js.Value {
+	get := js.FuncOf(func(this js.Value, args []js.Value) any {
+		method := args[1].String()
+		call := js.FuncOf(func(this js.Value, args []js.Value) any {
+			record(method, args)
+			return js.Undefined()
+		})
+		t.Cleanup(call.Release)
+		return call
+	})
+	t.Cleanup(get.Release)
+	traps := js.Global().Get("Object").New()
+	traps.Set("get", get)
+	return js.Global().Get("Proxy").New(js.Global().Get("Object").New(), traps)
+}
+
+func roundtrip(t *testing.T, method string, argc int, call func()) {
+	t.Helper()
+	var gotMethod string
+	var gotArgc int
+	js.Global().Call("setUnsafeGL", newFakeWebGL(t, func(m string, args []js.Value) {
+		gotMethod, gotArgc = m, len(args)
+	}))
+	call()
+	if gotMethod != method {
+		t.Errorf("call invoked webgl method %q, want %q", gotMethod, method)
+	}
+	if gotArgc != argc {
+		t.Errorf("call passed %d arguments, want %d", gotArgc, argc)
+	}
+}
+`)
+}
+
+// sampleArg returns a literal Go expression producing an arbitrary value of
+// type t, for use as a wrapper call argument in a generated test case.
+func sampleArg(t string) string {
+	switch t {
+	case "bool":
+		return "true"
+	case "string":
+		return `"name"`
+	case "int", "int64", "uint", "uintptr", "int32", "uint32":
+		return t + "(1)"
+	case "Attrib", "Enum":
+		return t + "(1)"
+	case "float32", "float64":
+		return t + "(1)"
+	case "[]byte":
+		return "[]byte{1}"
+	case "[]float32":
+		return "[]float32{1}"
+	case "[]int32":
+		return "[]int32{1}"
+	case "[]uint32":
+		return "[]uint32{1}"
+	default:
+		// Handle types (Buffer, Framebuffer, Program, ...) are js.Value
+		// wrappers; the zero value round-trips as the JS null handle.
+		return t + "{}"
+	}
+}
+
+// writeTestCase emits one round-trip test case for the generated wrapper
+// named fn, which is expected to call the JS method jsMethod with
+// len(argTypes) arguments.
+func writeTestCase(file io.Writer, fn, jsMethod string, argTypes []string) {
+	args := make([]string, len(argTypes))
+	for i, a := range argTypes {
+		parts := strings.SplitN(a, " ", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		args[i] = sampleArg(strings.TrimSpace(parts[1]))
+	}
+	fmt.Fprintf(file, `
+func TestUnsafe%s(t *testing.T) {
+	f := &FunctionCaller{}
+	roundtrip(t, %q, %d, func() {
+		f.%s(%s)
+	})
+}
+`, fn, jsMethod, len(args), fn, strings.Join(args, ", "))
+}
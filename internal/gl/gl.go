@@ -9,6 +9,7 @@ type (
 
 const (
 	ALL_BARRIER_BITS                      = 0xffffffff
+	ANY_SAMPLES_PASSED                    = 0x8C2F
 	ARRAY_BUFFER                          = 0x8892
 	BACK                                  = 0x0405
 	BLEND                                 = 0xbe2
@@ -40,13 +41,21 @@ const (
 	HALF_FLOAT                            = 0x140b
 	HALF_FLOAT_OES                        = 0x8d61
 	INFO_LOG_LENGTH                       = 0x8B84
+	INVALID_ENUM                          = 0x0500
+	INVALID_FRAMEBUFFER_OPERATION         = 0x0506
 	INVALID_INDEX                         = ^uint(0)
+	INVALID_OPERATION                     = 0x0502
+	INVALID_VALUE                         = 0x0501
+	OUT_OF_MEMORY                         = 0x0505
 	GREATER                               = 0x204
 	GEQUAL                                = 0x206
 	LINEAR                                = 0x2601
 	LINK_STATUS                           = 0x8b82
 	LUMINANCE                             = 0x1909
 	MAP_READ_BIT                          = 0x0001
+	MAP_WRITE_BIT                         = 0x0002
+	MAP_PERSISTENT_BIT                    = 0x0040
+	MAP_COHERENT_BIT                      = 0x0080
 	MAX_TEXTURE_SIZE                      = 0xd33
 	NEAREST                               = 0x2600
 	NO_ERROR                              = 0x0
@@ -70,8 +79,10 @@ const (
 	RGB                                   = 0x1907
 	RGBA                                  = 0x1908
 	RGBA8                                 = 0x8058
+	SAMPLES_PASSED                        = 0x8914
 	SHADER_STORAGE_BUFFER                 = 0x90D2
 	SHORT                                 = 0x1402
+	SRC_ALPHA                             = 0x0302
 	SRGB                                  = 0x8c40
 	SRGB_ALPHA_EXT                        = 0x8c42
 	SRGB8                                 = 0x8c41
@@ -100,4 +111,15 @@ const (
 	// EXT_disjoint_timer_query
 	TIME_ELAPSED_EXT = 0x88BF
 	GPU_DISJOINT_EXT = 0x8FBB
+
+	// KHR_debug
+	DEBUG_SOURCE_APPLICATION = 0x824A
+
+	// Sync objects, for fencing ring-buffer wraparound.
+	SYNC_GPU_COMMANDS_COMPLETE = 0x9117
+	SYNC_FLUSH_COMMANDS_BIT    = 0x00000001
+	ALREADY_SIGNALED           = 0x911A
+	TIMEOUT_EXPIRED            = 0x911B
+	CONDITION_SATISFIED        = 0x911C
+	WAIT_FAILED                = 0x911D
 )
@@ -276,6 +276,39 @@ func (f *FunctionCaller) Uniform3f(dst Uniform, v0, v1, v2 float32) {
 func (f *FunctionCaller) Uniform4f(dst Uniform, v0, v1, v2, v3 float32) {
 	f.Ctx.Call("uniform4f", js.Value(dst), v0, v1, v2, v3)
 }
+func (f *FunctionCaller) Uniform2i(dst Uniform, v0, v1 int32) {
+	f.Ctx.Call("uniform2i", js.Value(dst), v0, v1)
+}
+func (f *FunctionCaller) Uniform3i(dst Uniform, v0, v1, v2 int32) {
+	f.Ctx.Call("uniform3i", js.Value(dst), v0, v1, v2)
+}
+func (f *FunctionCaller) Uniform4i(dst Uniform, v0, v1, v2, v3 int32) {
+	f.Ctx.Call("uniform4i", js.Value(dst), v0, v1, v2, v3)
+}
+func (f *FunctionCaller) Uniform1ui(dst Uniform, v0 uint32) {
+	f.Ctx.Call("uniform1ui", js.Value(dst), v0)
+}
+func (f *FunctionCaller) Uniform2ui(dst Uniform, v0, v1 uint32) {
+	f.Ctx.Call("uniform2ui", js.Value(dst), v0, v1)
+}
+func (f *FunctionCaller) Uniform3ui(dst Uniform, v0, v1, v2 uint32) {
+	f.Ctx.Call("uniform3ui", js.Value(dst), v0, v1, v2)
+}
+func (f *FunctionCaller) Uniform4ui(dst Uniform, v0, v1, v2, v3 uint32) {
+	f.Ctx.Call("uniform4ui", js.Value(dst), v0, v1, v2, v3)
+}
+
+// UniformMatrix2fv, UniformMatrix3fv and UniformMatrix4fv upload a slice of
+// column-major NxN matrices.
+func (f *FunctionCaller) UniformMatrix2fv(dst Uniform, value []float32) {
+	f.Ctx.Call("uniformMatrix2fv", js.Value(dst), false, f.float32ArrayOf(value))
+}
+func (f *FunctionCaller) UniformMatrix3fv(dst Uniform, value []float32) {
+	f.Ctx.Call("uniformMatrix3fv", js.Value(dst), false, f.float32ArrayOf(value))
+}
+func (f *FunctionCaller) UniformMatrix4fv(dst Uniform, value []float32) {
+	f.Ctx.Call("uniformMatrix4fv", js.Value(dst), false, f.float32ArrayOf(value))
+}
 func (f *FunctionCaller) UseProgram(p Program) {
 	f.Ctx.Call("useProgram", js.Value(p))
 }
@@ -296,6 +329,17 @@ func (f *FunctionCaller) byteArrayOf(data []byte) js.Value {
 	return ba
 }
 
+func (f *FunctionCaller) float32ArrayOf(data []float32) js.Value {
+	if len(data) == 0 {
+		return js.Null()
+	}
+	arr := js.Global().Get("Float32Array").New(len(data))
+	for i, v := range data {
+		arr.SetIndex(i, v)
+	}
+	return arr
+}
+
 func (f *FunctionCaller) resizeByteBuffer(n int) {
 	if n == 0 {
 		return
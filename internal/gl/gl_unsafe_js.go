@@ -547,7 +547,77 @@ func (f *FunctionCaller) Uniform4f(dst Uniform, v0, v1, v2, v3 float32) {
 }
 
 //go:noescape
-func asmUseProgram(p Program) 
+func asmUniform2i(dst Uniform, v0 int, v1 int)
+
+func (f *FunctionCaller) Uniform2i(dst Uniform, v0, v1 int32) {
+	asmUniform2i(dst, int(v0), int(v1))
+}
+
+//go:noescape
+func asmUniform3i(dst Uniform, v0 int, v1 int, v2 int)
+
+func (f *FunctionCaller) Uniform3i(dst Uniform, v0, v1, v2 int32) {
+	asmUniform3i(dst, int(v0), int(v1), int(v2))
+}
+
+//go:noescape
+func asmUniform4i(dst Uniform, v0 int, v1 int, v2 int, v3 int)
+
+func (f *FunctionCaller) Uniform4i(dst Uniform, v0, v1, v2, v3 int32) {
+	asmUniform4i(dst, int(v0), int(v1), int(v2), int(v3))
+}
+
+//go:noescape
+func asmUniform1ui(dst Uniform, v0 uint)
+
+func (f *FunctionCaller) Uniform1ui(dst Uniform, v0 uint32) {
+	asmUniform1ui(dst, uint(v0))
+}
+
+//go:noescape
+func asmUniform2ui(dst Uniform, v0 uint, v1 uint)
+
+func (f *FunctionCaller) Uniform2ui(dst Uniform, v0, v1 uint32) {
+	asmUniform2ui(dst, uint(v0), uint(v1))
+}
+
+//go:noescape
+func asmUniform3ui(dst Uniform, v0 uint, v1 uint, v2 uint)
+
+func (f *FunctionCaller) Uniform3ui(dst Uniform, v0, v1, v2 uint32) {
+	asmUniform3ui(dst, uint(v0), uint(v1), uint(v2))
+}
+
+//go:noescape
+func asmUniform4ui(dst Uniform, v0 uint, v1 uint, v2 uint, v3 uint)
+
+func (f *FunctionCaller) Uniform4ui(dst Uniform, v0, v1, v2, v3 uint32) {
+	asmUniform4ui(dst, uint(v0), uint(v1), uint(v2), uint(v3))
+}
+
+//go:noescape
+func asmUniformMatrix2fv(dst Uniform, value []float32)
+
+func (f *FunctionCaller) UniformMatrix2fv(dst Uniform, value []float32) {
+	asmUniformMatrix2fv(dst, value)
+}
+
+//go:noescape
+func asmUniformMatrix3fv(dst Uniform, value []float32)
+
+func (f *FunctionCaller) UniformMatrix3fv(dst Uniform, value []float32) {
+	asmUniformMatrix3fv(dst, value)
+}
+
+//go:noescape
+func asmUniformMatrix4fv(dst Uniform, value []float32)
+
+func (f *FunctionCaller) UniformMatrix4fv(dst Uniform, value []float32) {
+	asmUniformMatrix4fv(dst, value)
+}
+
+//go:noescape
+func asmUseProgram(p Program)
 
 func (f *FunctionCaller) UseProgram(p Program) {
 	asmUseProgram(p)
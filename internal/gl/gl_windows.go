@@ -108,9 +108,19 @@ func loadGLESv2Procs() error {
 		"glUniformBlockBinding":                 &_glUniformBlockBinding,
 		"glUniform1f":                           &_glUniform1f,
 		"glUniform1i":                           &_glUniform1i,
+		"glUniform1ui":                          &_glUniform1ui,
 		"glUniform2f":                           &_glUniform2f,
+		"glUniform2i":                           &_glUniform2i,
+		"glUniform2ui":                          &_glUniform2ui,
 		"glUniform3f":                           &_glUniform3f,
+		"glUniform3i":                           &_glUniform3i,
+		"glUniform3ui":                          &_glUniform3ui,
 		"glUniform4f":                           &_glUniform4f,
+		"glUniform4i":                           &_glUniform4i,
+		"glUniform4ui":                          &_glUniform4ui,
+		"glUniformMatrix2fv":                    &_glUniformMatrix2fv,
+		"glUniformMatrix3fv":                    &_glUniformMatrix3fv,
+		"glUniformMatrix4fv":                    &_glUniformMatrix4fv,
 		"glUseProgram":                          &_glUseProgram,
 		"glVertexAttribPointer":                 &_glVertexAttribPointer,
 		"glViewport":                            &_glViewport,
@@ -208,9 +218,19 @@ var (
 	_glUniformBlockBinding                 *windows.Proc
 	_glUniform1f                           *windows.Proc
 	_glUniform1i                           *windows.Proc
+	_glUniform1ui                          *windows.Proc
 	_glUniform2f                           *windows.Proc
+	_glUniform2i                           *windows.Proc
+	_glUniform2ui                          *windows.Proc
 	_glUniform3f                           *windows.Proc
+	_glUniform3i                           *windows.Proc
+	_glUniform3ui                          *windows.Proc
 	_glUniform4f                           *windows.Proc
+	_glUniform4i                           *windows.Proc
+	_glUniform4ui                          *windows.Proc
+	_glUniformMatrix2fv                    *windows.Proc
+	_glUniformMatrix3fv                    *windows.Proc
+	_glUniformMatrix4fv                    *windows.Proc
 	_glUseProgram                          *windows.Proc
 	_glVertexAttribPointer                 *windows.Proc
 	_glViewport                            *windows.Proc
@@ -625,6 +645,30 @@ func (f *Functions) MapBufferRange(target Enum, offset, length int, access Enum)
 	panic("not implemented")
 }
 
+func (f *Functions) FenceSync(condition, flags Enum) Sync {
+	panic("not implemented")
+}
+
+func (f *Functions) ClientWaitSync(sync Sync, flags Enum, timeout uint64) Enum {
+	panic("not implemented")
+}
+
+func (f *Functions) DeleteSync(sync Sync) {
+	panic("not implemented")
+}
+
+func (f *Functions) BindBufferRange(target Enum, index int, buf Buffer, offset, size int) {
+	panic("not implemented")
+}
+
+func (f *Functions) RenderbufferStorageMultisample(target Enum, samples int, internalformat Enum, width, height int) {
+	panic("not implemented")
+}
+
+func (f *Functions) BlitFramebuffer(sx0, sy0, sx1, sy1, dx0, dy0, dx1, dy1 int, mask, filter Enum) {
+	panic("not implemented")
+}
+
 func (f *Functions) ReadPixels(x, y, width, height int, format, ty Enum, data []byte) {
 	d0 := &data[0]
 	syscall.Syscall9(_glReadPixels.Addr(), 7, uintptr(x), uintptr(y), uintptr(width), uintptr(height), uintptr(format), uintptr(ty), uintptr(unsafe.Pointer(d0)), 0, 0)
@@ -688,6 +732,57 @@ func (c *Functions) Uniform4f(dst Uniform, v0, v1, v2, v3 float32) {
 	syscall.Syscall6(_glUniform4f.Addr(), 5, uintptr(dst.V), uintptr(math.Float32bits(v0)), uintptr(math.Float32bits(v1)), uintptr(math.Float32bits(v2)), uintptr(math.Float32bits(v3)), 0)
 }
 
+func (c *Functions) Uniform2i(dst Uniform, v0, v1 int32) {
+	syscall.Syscall6(_glUniform2i.Addr(), 3, uintptr(dst.V), uintptr(v0), uintptr(v1), 0, 0, 0)
+}
+
+func (c *Functions) Uniform3i(dst Uniform, v0, v1, v2 int32) {
+	syscall.Syscall6(_glUniform3i.Addr(), 4, uintptr(dst.V), uintptr(v0), uintptr(v1), uintptr(v2), 0, 0)
+}
+
+func (c *Functions) Uniform4i(dst Uniform, v0, v1, v2, v3 int32) {
+	syscall.Syscall6(_glUniform4i.Addr(), 5, uintptr(dst.V), uintptr(v0), uintptr(v1), uintptr(v2), uintptr(v3), 0)
+}
+
+func (c *Functions) Uniform1ui(dst Uniform, v0 uint32) {
+	syscall.Syscall(_glUniform1ui.Addr(), 2, uintptr(dst.V), uintptr(v0), 0)
+}
+
+func (c *Functions) Uniform2ui(dst Uniform, v0, v1 uint32) {
+	syscall.Syscall6(_glUniform2ui.Addr(), 3, uintptr(dst.V), uintptr(v0), uintptr(v1), 0, 0, 0)
+}
+
+func (c *Functions) Uniform3ui(dst Uniform, v0, v1, v2 uint32) {
+	syscall.Syscall6(_glUniform3ui.Addr(), 4, uintptr(dst.V), uintptr(v0), uintptr(v1), uintptr(v2), 0, 0)
+}
+
+func (c *Functions) Uniform4ui(dst Uniform, v0, v1, v2, v3 uint32) {
+	syscall.Syscall6(_glUniform4ui.Addr(), 5, uintptr(dst.V), uintptr(v0), uintptr(v1), uintptr(v2), uintptr(v3), 0)
+}
+
+// UniformMatrix2fv, UniformMatrix3fv and UniformMatrix4fv upload a slice of
+// column-major NxN matrices. count is derived from len(value).
+func (c *Functions) UniformMatrix2fv(dst Uniform, value []float32) {
+	count := len(value) / 4
+	v0 := &value[0]
+	syscall.Syscall6(_glUniformMatrix2fv.Addr(), 4, uintptr(dst.V), uintptr(count), 0, uintptr(unsafe.Pointer(v0)), 0, 0)
+	issue34474KeepAlive(v0)
+}
+
+func (c *Functions) UniformMatrix3fv(dst Uniform, value []float32) {
+	count := len(value) / 9
+	v0 := &value[0]
+	syscall.Syscall6(_glUniformMatrix3fv.Addr(), 4, uintptr(dst.V), uintptr(count), 0, uintptr(unsafe.Pointer(v0)), 0, 0)
+	issue34474KeepAlive(v0)
+}
+
+func (c *Functions) UniformMatrix4fv(dst Uniform, value []float32) {
+	count := len(value) / 16
+	v0 := &value[0]
+	syscall.Syscall6(_glUniformMatrix4fv.Addr(), 4, uintptr(dst.V), uintptr(count), 0, uintptr(unsafe.Pointer(v0)), 0, 0)
+	issue34474KeepAlive(v0)
+}
+
 func (c *Functions) UseProgram(p Program) {
 	syscall.Syscall(_glUseProgram.Addr(), 1, uintptr(p.V), 0, 0)
 }
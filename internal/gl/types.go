@@ -10,11 +10,16 @@ type (
 	Shader       struct{ V uint }
 	Texture      struct{ V uint }
 	Query        struct{ V uint }
+	Sync         struct{ V uintptr }
 	Uniform      struct{ V int }
 	VertexArray  struct{ V uint }
 	Object       struct{ V uint }
 )
 
+func (s Sync) Valid() bool {
+	return s.V != 0
+}
+
 func (u Framebuffer) Valid() bool {
 	return u.V != 0
 }
@@ -34,3 +39,7 @@ func (s Shader) Valid() bool {
 func (a VertexArray) Valid() bool {
 	return a.V != 0
 }
+
+func (q Query) Valid() bool {
+	return q.V != 0
+}
@@ -77,6 +77,7 @@ const (
 	TypeSemanticSelected
 	TypeSemanticEnabled
 	TypeActionInput
+	TypeKeyBinding
 )
 
 type StackID struct {
@@ -153,6 +154,7 @@ const (
 	TypeSemanticSelectedLen = 2
 	TypeSemanticEnabledLen  = 2
 	TypeActionInputLen      = 1 + 1
+	TypeKeyBindingLen       = 1 + 4 + 1
 )
 
 func (op *ClipOp) Decode(data []byte) {
@@ -169,6 +171,34 @@ func (op *ClipOp) Decode(data []byte) {
 	op.Shape = Shape(data[18])
 }
 
+// Data returns the raw, encoded op stream. It does not include the
+// out-of-band refs and stringRefs accompanying some ops.
+func Data(o *Ops) []byte {
+	return o.data
+}
+
+// Refs returns the out-of-band references accompanying the op stream, one
+// per ref parameter passed to a Write call, in the order they were written.
+func Refs(o *Ops) []any {
+	return o.refs
+}
+
+// Version returns the number of times o has been Reset.
+func Version(o *Ops) uint32 {
+	return o.version
+}
+
+// Load replaces the contents of o with data, refs and version, as produced
+// by a prior call to Data, Refs and Version. It is used to reconstruct an
+// Ops from a serialized form; o must not have any macros or stacks pushed.
+func Load(o *Ops, data []byte, refs []any, version uint32) {
+	*o = Ops{
+		version: version,
+		data:    data,
+		refs:    refs,
+	}
+}
+
 func Reset(o *Ops) {
 	o.macroStack = stack{}
 	o.stacks = [_StackKind]stack{}
@@ -426,6 +456,7 @@ var opProps = [0x100]opProp{
 	TypeSemanticSelected: {Size: TypeSemanticSelectedLen, NumRefs: 0},
 	TypeSemanticEnabled:  {Size: TypeSemanticEnabledLen, NumRefs: 0},
 	TypeActionInput:      {Size: TypeActionInputLen, NumRefs: 0},
+	TypeKeyBinding:       {Size: TypeKeyBindingLen, NumRefs: 2},
 }
 
 func (t OpType) props() (size, numRefs uint32) {
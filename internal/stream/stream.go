@@ -0,0 +1,180 @@
+// SPDX-License-Identifier: Unlicense OR MIT
+
+// Package stream provides io.Writer and io.Reader wrappers for producing
+// and consuming content-addressed, reproducible streams without buffering
+// them in memory.
+package stream
+
+import (
+	"hash"
+	"io"
+)
+
+// Writer wraps an io.Writer with sticky-error semantics: once a Write
+// fails, every subsequent Write is a no-op that returns the same error, so
+// callers can perform a long sequence of writes and check the error once at
+// the end. It also tracks the number of bytes successfully written and, if
+// Hash is set, tees them through a hash.Hash.
+type Writer struct {
+	W    io.Writer
+	Hash hash.Hash
+
+	n   int64
+	err error
+}
+
+// Write implements io.Writer. A short write, one where the underlying
+// writer reports n < len(p) without an error, is promoted to
+// io.ErrShortWrite: callers encoding length-prefixed records can otherwise
+// mistake a short write for success and go on to emit a corrupt record.
+func (w *Writer) Write(p []byte) (int, error) {
+	if w.err != nil {
+		return 0, w.err
+	}
+	n, err := w.W.Write(p)
+	w.n += int64(n)
+	if w.Hash != nil && n > 0 {
+		w.Hash.Write(p[:n])
+	}
+	if err == nil && n < len(p) {
+		err = io.ErrShortWrite
+	}
+	if err != nil {
+		w.err = err
+	}
+	return n, err
+}
+
+// WriteString writes s like Write, using the underlying writer's
+// WriteString method if it implements io.StringWriter to avoid the
+// string-to-[]byte conversion on the hot path.
+func (w *Writer) WriteString(s string) (int, error) {
+	if w.err != nil {
+		return 0, w.err
+	}
+	var n int
+	var err error
+	if sw, ok := w.W.(io.StringWriter); ok {
+		n, err = sw.WriteString(s)
+	} else {
+		n, err = w.W.Write([]byte(s))
+	}
+	w.n += int64(n)
+	if w.Hash != nil && n > 0 {
+		io.WriteString(w.Hash, s[:n])
+	}
+	if err == nil && n < len(s) {
+		err = io.ErrShortWrite
+	}
+	if err != nil {
+		w.err = err
+	}
+	return n, err
+}
+
+// WriteByte writes c like Write, using the underlying writer's WriteByte
+// method if it implements io.ByteWriter to avoid allocating a one-byte
+// slice on the hot path.
+func (w *Writer) WriteByte(c byte) error {
+	if w.err != nil {
+		return w.err
+	}
+	var err error
+	if bw, ok := w.W.(io.ByteWriter); ok {
+		err = bw.WriteByte(c)
+	} else {
+		_, err = w.W.Write([]byte{c})
+	}
+	if err != nil {
+		w.err = err
+		return err
+	}
+	w.n++
+	if w.Hash != nil {
+		w.Hash.Write([]byte{c})
+	}
+	return nil
+}
+
+// Count returns the number of bytes successfully written so far.
+func (w *Writer) Count() int64 {
+	return w.n
+}
+
+// Err returns the sticky error, if any.
+func (w *Writer) Err() error {
+	return w.err
+}
+
+// Sum appends the hash of the bytes written so far to b and returns the
+// result, or returns nil if Hash is unset.
+func (w *Writer) Sum(b []byte) []byte {
+	if w.Hash == nil {
+		return nil
+	}
+	return w.Hash.Sum(b)
+}
+
+// mustWrite is the panic value used by Must and recovered by Catch, so a
+// failing Must deep in a hot encoding loop can unwind straight to the
+// entry point without every call along the way checking an error return.
+type mustWrite struct{ err error }
+
+// Must writes p like Write, panicking with a value recoverable by Catch
+// instead of returning an error. It lets a hot encoding loop such as
+// op.Marshal's ref walk skip the per-write error check and branch only
+// once, at the top, via a deferred Catch.
+func (w *Writer) Must(p []byte) {
+	if _, err := w.Write(p); err != nil {
+		panic(mustWrite{err})
+	}
+}
+
+// Catch recovers a panic raised by Must and stores the error it carries in
+// *err. It must be deferred by any function using Must. Panics that did
+// not originate from Must are re-raised.
+func Catch(err *error) {
+	r := recover()
+	if r == nil {
+		return
+	}
+	mw, ok := r.(mustWrite)
+	if !ok {
+		panic(r)
+	}
+	*err = mw.err
+}
+
+// Reader wraps an io.Reader with sticky-error semantics, the mirror image
+// of Writer: once a Read fails, every subsequent Read is a no-op that
+// returns the same error, so callers can perform a long sequence of reads
+// and check the error once at the end.
+type Reader struct {
+	R io.Reader
+
+	n   int64
+	err error
+}
+
+// Read implements io.Reader.
+func (r *Reader) Read(p []byte) (int, error) {
+	if r.err != nil {
+		return 0, r.err
+	}
+	n, err := r.R.Read(p)
+	r.n += int64(n)
+	if err != nil {
+		r.err = err
+	}
+	return n, err
+}
+
+// Count returns the number of bytes successfully read so far.
+func (r *Reader) Count() int64 {
+	return r.n
+}
+
+// Err returns the sticky error, if any.
+func (r *Reader) Err() error {
+	return r.err
+}
@@ -0,0 +1,190 @@
+// SPDX-License-Identifier: Unlicense OR MIT
+
+package stream
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"io"
+	"testing"
+)
+
+type errWriter struct{ err error }
+
+func (w errWriter) Write(p []byte) (int, error) {
+	return 0, w.err
+}
+
+type errReader struct{ err error }
+
+func (r errReader) Read(p []byte) (int, error) {
+	return 0, r.err
+}
+
+func TestWriterStickyError(t *testing.T) {
+	wantErr := errors.New("boom")
+	w := &Writer{W: errWriter{wantErr}}
+	if _, err := w.Write([]byte("a")); err != wantErr {
+		t.Fatalf("Write returned %v, want %v", err, wantErr)
+	}
+	if _, err := w.Write([]byte("b")); err != wantErr {
+		t.Fatalf("second Write returned %v, want the sticky %v", err, wantErr)
+	}
+	if err := w.Err(); err != wantErr {
+		t.Fatalf("Err returned %v, want %v", err, wantErr)
+	}
+	if n := w.Count(); n != 0 {
+		t.Fatalf("Count is %d after a failing Write, want 0", n)
+	}
+}
+
+func TestWriterCountAndHash(t *testing.T) {
+	var buf bytes.Buffer
+	h := sha256.New()
+	w := &Writer{W: &buf, Hash: h}
+	if _, err := w.Write([]byte("hello ")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := w.Write([]byte("world")); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := w.Count(), int64(len("hello world")); got != want {
+		t.Errorf("Count is %d, want %d", got, want)
+	}
+	want := sha256.Sum256([]byte("hello world"))
+	if got := w.Sum(nil); !bytes.Equal(got, want[:]) {
+		t.Errorf("Sum is %x, want %x", got, want)
+	}
+}
+
+func TestWriterSumWithoutHash(t *testing.T) {
+	w := &Writer{W: new(bytes.Buffer)}
+	if got := w.Sum(nil); got != nil {
+		t.Errorf("Sum with no Hash set returned %x, want nil", got)
+	}
+}
+
+func TestReaderStickyError(t *testing.T) {
+	wantErr := errors.New("boom")
+	r := &Reader{R: errReader{wantErr}}
+	buf := make([]byte, 4)
+	if _, err := r.Read(buf); err != wantErr {
+		t.Fatalf("Read returned %v, want %v", err, wantErr)
+	}
+	if _, err := r.Read(buf); err != wantErr {
+		t.Fatalf("second Read returned %v, want the sticky %v", err, wantErr)
+	}
+	if err := r.Err(); err != wantErr {
+		t.Fatalf("Err returned %v, want %v", err, wantErr)
+	}
+}
+
+func TestReaderCount(t *testing.T) {
+	r := &Reader{R: bytes.NewReader([]byte("hello world"))}
+	buf := make([]byte, 5)
+	if _, err := io.ReadFull(r, buf); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := r.Count(), int64(5); got != want {
+		t.Errorf("Count is %d, want %d", got, want)
+	}
+}
+
+// shortWriter reports success for fewer bytes than it was given, without
+// returning an error, the case Write and WriteString must promote to
+// io.ErrShortWrite.
+type shortWriter struct{}
+
+func (shortWriter) Write(p []byte) (int, error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+	return len(p) - 1, nil
+}
+
+func TestWriterPromotesShortWrite(t *testing.T) {
+	w := &Writer{W: shortWriter{}}
+	if _, err := w.Write([]byte("ab")); err != io.ErrShortWrite {
+		t.Fatalf("Write returned %v, want %v", err, io.ErrShortWrite)
+	}
+	if err := w.Err(); err != io.ErrShortWrite {
+		t.Fatalf("Err returned %v, want %v", err, io.ErrShortWrite)
+	}
+}
+
+func TestWriterWriteStringPromotesShortWrite(t *testing.T) {
+	w := &Writer{W: shortWriter{}}
+	if _, err := w.WriteString("ab"); err != io.ErrShortWrite {
+		t.Fatalf("WriteString returned %v, want %v", err, io.ErrShortWrite)
+	}
+}
+
+func TestWriterWriteStringAndByte(t *testing.T) {
+	var buf bytes.Buffer
+	w := &Writer{W: &buf}
+	if _, err := w.WriteString("foo"); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.WriteByte('!'); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := buf.String(), "foo!"; got != want {
+		t.Errorf("wrote %q, want %q", got, want)
+	}
+	if got, want := w.Count(), int64(4); got != want {
+		t.Errorf("Count is %d, want %d", got, want)
+	}
+}
+
+func TestWriterWriteByteStickyError(t *testing.T) {
+	wantErr := errors.New("boom")
+	w := &Writer{W: errWriter{wantErr}}
+	if err := w.WriteByte('x'); err != wantErr {
+		t.Fatalf("WriteByte returned %v, want %v", err, wantErr)
+	}
+	if err := w.WriteByte('y'); err != wantErr {
+		t.Fatalf("second WriteByte returned %v, want the sticky %v", err, wantErr)
+	}
+}
+
+func TestMustCatchRecovers(t *testing.T) {
+	wantErr := errors.New("boom")
+	w := &Writer{W: errWriter{wantErr}}
+	err := func() (err error) {
+		defer Catch(&err)
+		w.Must([]byte("x"))
+		t.Fatal("Must did not panic on a failing Write")
+		return nil
+	}()
+	if err != wantErr {
+		t.Fatalf("Catch recovered %v, want %v", err, wantErr)
+	}
+}
+
+func TestMustCatchPassesThroughSuccess(t *testing.T) {
+	var buf bytes.Buffer
+	w := &Writer{W: &buf}
+	err := func() (err error) {
+		defer Catch(&err)
+		w.Must([]byte("ok"))
+		return nil
+	}()
+	if err != nil {
+		t.Fatalf("Catch returned %v for a successful Must, want nil", err)
+	}
+	if got := buf.String(); got != "ok" {
+		t.Errorf("wrote %q, want %q", got, "ok")
+	}
+}
+
+func TestCatchRepanicsOtherPanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("Catch swallowed a panic it didn't raise")
+		}
+	}()
+	var err error
+	defer Catch(&err)
+	panic("unrelated panic")
+}
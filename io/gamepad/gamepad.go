@@ -0,0 +1,51 @@
+// SPDX-License-Identifier: Unlicense OR MIT
+
+// Package gamepad provides connect, disconnect, axis and button events for
+// game controllers.
+package gamepad
+
+import "gioui.org/io/event"
+
+// ID identifies a connected gamepad for the lifetime of its connection. It
+// has no meaning beyond distinguishing one gamepad's Events from another's;
+// a gamepad disconnecting and reconnecting may or may not be assigned the
+// same ID.
+type ID int
+
+// Kind is the kind of change reported by an Event.
+type Kind uint8
+
+const (
+	// Connect is delivered once when a gamepad becomes available, before
+	// any Axis, ButtonPress or ButtonRelease event carrying its ID.
+	Connect Kind = iota
+	// Disconnect is delivered once when a gamepad is no longer available.
+	// No further events carry its ID until a later Connect reuses it.
+	Disconnect
+	// Axis is delivered when an analog axis, such as a stick or trigger,
+	// changes value.
+	Axis
+	// ButtonPress is delivered when a digital button is pressed.
+	ButtonPress
+	// ButtonRelease is delivered when a digital button is released.
+	ButtonRelease
+)
+
+// Event is generated when a gamepad connects, disconnects, or reports a
+// changed axis or button while connected.
+type Event struct {
+	ID   ID
+	Kind Kind
+
+	// Name identifies the device as reported by the platform. It is only
+	// set for Connect.
+	Name string
+	// Index is the axis index for Kind == Axis, or the button index for
+	// Kind == ButtonPress and Kind == ButtonRelease.
+	Index int
+	// Value is the new value of the Axis numbered Index, in [-1, 1].
+	// It is only set for Kind == Axis.
+	Value float32
+}
+
+func (Event) ImplementsEvent() {}
@@ -28,6 +28,20 @@ type keyQueue struct {
 	order    []event.Tag
 	dirOrder []dirFocusEntry
 	hint     key.InputHint
+	// bindings is the per-frame table of registered key.BindingOps, keyed
+	// by their shortcut exactly as declared.
+	bindings map[key.Shortcut][]keyBindingEntry
+	// group is the id of the most recently dispatched scoped key.Event,
+	// used to tag the taggedEvents delivered for it so that a
+	// key.ConsumeOp can find and drop its remaining, undelivered siblings.
+	group int
+}
+
+// keyBindingEntry is a registered key.BindingOp, without the shortcut that
+// keys it in keyQueue.bindings.
+type keyBindingEntry struct {
+	tag   event.Tag
+	scope key.Scope
 }
 
 // keyState is the input state related to key events.
@@ -48,6 +62,11 @@ type keyHandler struct {
 	orderPlusOne int
 	dirOrder     int
 	trans        f32.Affine2D
+	// areaPlusOne is the index, plus 1, into pointerQueue.areas of the
+	// clip area the handler was declared in. It is used to resolve
+	// key.ScopeWindow bindings along the ancestor chain of the focused
+	// handler. Zero means the handler wasn't declared this frame.
+	areaPlusOne int
 }
 
 type keyFilter []key.Filter
@@ -93,11 +112,13 @@ func (k *keyHandler) Reset() {
 	k.visible = false
 	k.orderPlusOne = 0
 	k.hint = key.HintAny
+	k.areaPlusOne = 0
 }
 
 func (q *keyQueue) Reset() {
 	q.order = q.order[:0]
 	q.dirOrder = q.dirOrder[:0]
+	clear(q.bindings)
 }
 
 func (k *keyHandler) ResetEvent() (event.Event, bool) {
@@ -260,6 +281,21 @@ func (k *keyFilter) Matches(focus event.Tag, e key.Event, system bool) bool {
 	return false
 }
 
+// MatchesTagged reports whether k contains a filter for tag that matches
+// e, regardless of the current focus. It is used to claim a taggedEvent
+// targeted at tag by a scoped key.BindingOp dispatch.
+func (k *keyFilter) MatchesTagged(e key.Event, system bool, tag event.Tag) bool {
+	for _, f := range *k {
+		if f.Focus != tag {
+			continue
+		}
+		if keyFilterMatch(tag, f, e, system) {
+			return true
+		}
+	}
+	return false
+}
+
 func keyFilterMatch(focus event.Tag, f key.Filter, e key.Event, system bool) bool {
 	if f.Focus != nil && f.Focus != focus {
 		return false
@@ -326,6 +362,102 @@ func (q *keyQueue) inputOp(tag event.Tag, state *keyHandler, t f32.Affine2D, are
 	state.trans = t
 }
 
+// bindingOp registers a key.BindingOp in the per-frame binding table.
+func (q *keyQueue) bindingOp(tag event.Tag, keys key.Shortcut, scope key.Scope) {
+	if q.bindings == nil {
+		q.bindings = make(map[key.Shortcut][]keyBindingEntry)
+	}
+	q.bindings[keys] = append(q.bindings[keys], keyBindingEntry{tag: tag, scope: scope})
+}
+
+// Dispatch returns the events that should be offered for e, in priority
+// order: any matching ScopeGlobal binding, then the focused handler's own
+// ScopeFocused binding or a matching [key.Filter], then matching
+// ScopeWindow bindings along the ancestor chain of the focused handler,
+// outward from the focus. The returned events all share a single group,
+// so that a key.ConsumeOp issued by any one of their recipients drops the
+// rest that haven't been delivered yet.
+func (q *keyQueue) Dispatch(handlers map[event.Tag]*handler, areas []areaNode, filter keyFilter, focus event.Tag, e key.Event, system bool) []taggedEvent {
+	shortcut := key.Shortcut{Name: e.Name, Modifiers: e.Modifiers}
+	var evts []taggedEvent
+	for _, b := range q.bindings[shortcut] {
+		if b.scope == key.ScopeGlobal {
+			evts = append(evts, taggedEvent{tag: b.tag, event: e})
+		}
+	}
+	if focus != nil {
+		for _, b := range q.bindings[shortcut] {
+			if b.scope == key.ScopeFocused && b.tag == focus {
+				evts = append(evts, taggedEvent{tag: focus, event: e})
+			}
+		}
+	}
+	if filter.Matches(focus, e, system) {
+		evts = append(evts, taggedEvent{event: e})
+	}
+	evts = append(evts, q.windowBindings(handlers, areas, shortcut, focus, e)...)
+	if len(evts) == 0 {
+		return nil
+	}
+	q.group++
+	for i := range evts {
+		evts[i].group = q.group
+	}
+	return evts
+}
+
+// windowBindings returns the events for the ScopeWindow bindings matching
+// shortcut along the ancestor chain of focus, nearest ancestor first.
+func (q *keyQueue) windowBindings(handlers map[event.Tag]*handler, areas []areaNode, shortcut key.Shortcut, focus event.Tag, e key.Event) []taggedEvent {
+	focusArea, ok := handlerArea(handlers, focus)
+	if !ok {
+		return nil
+	}
+	type candidate struct {
+		tag   event.Tag
+		depth int
+	}
+	var candidates []candidate
+	for _, b := range q.bindings[shortcut] {
+		if b.scope != key.ScopeWindow {
+			continue
+		}
+		bindingArea, ok := handlerArea(handlers, b.tag)
+		if !ok {
+			continue
+		}
+		depth := 0
+		for a := focusArea; a != -1; a = areas[a].parent {
+			if a == bindingArea {
+				candidates = append(candidates, candidate{tag: b.tag, depth: depth})
+				break
+			}
+			depth++
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].depth < candidates[j].depth
+	})
+	evts := make([]taggedEvent, len(candidates))
+	for i, c := range candidates {
+		evts[i] = taggedEvent{tag: c.tag, event: e}
+	}
+	return evts
+}
+
+// handlerArea returns the clip area tag was declared in during the
+// current frame.
+func handlerArea(handlers map[event.Tag]*handler, tag event.Tag) (int, bool) {
+	if tag == nil {
+		return 0, false
+	}
+	h, ok := handlers[tag]
+	if !ok || h.key.areaPlusOne == 0 {
+		return 0, false
+	}
+	return h.key.areaPlusOne - 1, true
+}
+
 func (q *keyQueue) setSelection(state keyState, req key.SelectionCmd) keyState {
 	if req.Tag != state.focus {
 		return state
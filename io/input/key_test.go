@@ -317,6 +317,123 @@ func TestKeyRouting(t *testing.T) {
 	assertEventSequence(t, events(r, -1, key.Filter{Focus: h, Name: "A"}, key.Filter{Name: "B"}), A)
 }
 
+func TestKeyBindingScopeWindow(t *testing.T) {
+	r := new(Router)
+	ops := new(op.Ops)
+	parent, child := new(int), new(int)
+	pr := clip.Rect(image.Rect(0, 0, 100, 100)).Push(ops)
+	event.Op(ops, parent)
+	key.BindingOp{Tag: parent, Keys: key.Shortcut{Name: "A"}, Scope: key.ScopeWindow}.Add(ops)
+	cr := clip.Rect(image.Rect(0, 0, 50, 50)).Push(ops)
+	event.Op(ops, child)
+	cr.Pop()
+	pr.Pop()
+
+	filters := []event.Filter{
+		key.FocusFilter{Target: child},
+		key.Filter{Focus: parent, Name: "A"},
+	}
+	events(r, -1, filters...)
+	r.Frame(ops)
+	r.Source().Execute(key.FocusCmd{Tag: child})
+	assertEventSequence(t, events(r, 1, filters...), key.FocusEvent{Focus: true})
+
+	// child is focused but doesn't itself handle "A", so the ScopeWindow
+	// binding on its ancestor, parent, fires instead.
+	evt := key.Event{Name: "A"}
+	r.Queue(evt)
+	assertEventSequence(t, events(r, 1, filters...), evt)
+}
+
+func TestKeyBindingShadowing(t *testing.T) {
+	r := new(Router)
+	ops := new(op.Ops)
+	parent, child := new(int), new(int)
+	pr := clip.Rect(image.Rect(0, 0, 100, 100)).Push(ops)
+	event.Op(ops, parent)
+	key.BindingOp{Tag: parent, Keys: key.Shortcut{Name: "A"}, Scope: key.ScopeWindow}.Add(ops)
+	cr := clip.Rect(image.Rect(0, 0, 50, 50)).Push(ops)
+	event.Op(ops, child)
+	cr.Pop()
+	pr.Pop()
+
+	childFilter := key.Filter{Focus: child, Name: "A"}
+	parentFilter := key.Filter{Focus: parent, Name: "A"}
+	events(r, -1, key.FocusFilter{Target: child}, childFilter, parentFilter)
+	r.Frame(ops)
+	r.Source().Execute(key.FocusCmd{Tag: child})
+	events(r, -1, key.FocusFilter{Target: child}, childFilter, parentFilter)
+
+	// child filters for "A" itself, so it wins over parent's ScopeWindow
+	// binding for the same shortcut.
+	evt := key.Event{Name: "A"}
+	r.Queue(evt)
+	assertEventSequence(t, events(r, 1, childFilter, parentFilter), evt)
+	// child consumes the event, so parent's lower-priority binding never
+	// sees it.
+	r.Source().Execute(key.ConsumeOp{})
+	assertEventSequence(t, events(r, -1, parentFilter))
+}
+
+func TestKeyBindingGlobal(t *testing.T) {
+	r := new(Router)
+	ops := new(op.Ops)
+	h := new(int)
+	global := new(int)
+	cl := clip.Rect(image.Rect(0, 0, 10, 10)).Push(ops)
+	event.Op(ops, h)
+	cl.Pop()
+	key.BindingOp{Tag: global, Keys: key.Shortcut{Name: "A"}, Scope: key.ScopeGlobal}.Add(ops)
+
+	filters := []event.Filter{
+		key.FocusFilter{Target: h},
+		key.Filter{Focus: global, Name: "A"},
+	}
+	events(r, -1, filters...)
+	r.Frame(ops)
+
+	// A ScopeGlobal binding fires even though no handler is focused.
+	evt := key.Event{Name: "A"}
+	r.Queue(evt)
+	assertEventSequence(t, events(r, 1, filters...), evt)
+
+	r.Source().Execute(key.FocusCmd{Tag: h})
+	events(r, -1, filters...)
+
+	// It still fires ahead of the now-focused handler's own matches.
+	r.Queue(evt)
+	assertEventSequence(t, events(r, 1, filters...), evt)
+}
+
+func TestKeyBindingConsume(t *testing.T) {
+	r := new(Router)
+	ops := new(op.Ops)
+	outer, inner := new(int), new(int)
+	or := clip.Rect(image.Rect(0, 0, 100, 100)).Push(ops)
+	event.Op(ops, outer)
+	key.BindingOp{Tag: outer, Keys: key.Shortcut{Name: "A"}, Scope: key.ScopeWindow}.Add(ops)
+	ir := clip.Rect(image.Rect(0, 0, 50, 50)).Push(ops)
+	event.Op(ops, inner)
+	key.BindingOp{Tag: inner, Keys: key.Shortcut{Name: "A"}, Scope: key.ScopeWindow}.Add(ops)
+	ir.Pop()
+	or.Pop()
+
+	outerFilter := key.Filter{Focus: outer, Name: "A"}
+	innerFilter := key.Filter{Focus: inner, Name: "A"}
+	events(r, -1, key.FocusFilter{Target: inner}, outerFilter, innerFilter)
+	r.Frame(ops)
+	r.Source().Execute(key.FocusCmd{Tag: inner})
+	events(r, -1, key.FocusFilter{Target: inner}, outerFilter, innerFilter)
+
+	evt := key.Event{Name: "A"}
+	r.Queue(evt)
+	// inner is the nearest ScopeWindow ancestor and claims the event first.
+	assertEventSequence(t, events(r, 1, innerFilter, outerFilter), evt)
+	r.Source().Execute(key.ConsumeOp{})
+	// Having consumed it, outer must not also receive it.
+	assertEventSequence(t, events(r, -1, outerFilter))
+}
+
 func assertFocus(t *testing.T, router *Router, expected event.Tag) {
 	t.Helper()
 	if !router.Source().Focused(expected) {
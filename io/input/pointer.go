@@ -58,6 +58,10 @@ type pointerInfo struct {
 
 	dataSource event.Tag // dragging source tag
 	dataTarget event.Tag // dragging target tag
+
+	// captured is the tag holding an exclusive pointer.CaptureCmd
+	// capture on this pointer, or nil if it isn't captured.
+	captured event.Tag
 }
 
 type pointerHandler struct {
@@ -73,6 +77,9 @@ type pointerFilter struct {
 	kinds pointer.Kind
 	// min and max horizontal/vertical scroll
 	scrollX, scrollY pointer.ScrollRange
+	// wantStylus is set if any of the merged filters requested the
+	// extended stylus fields with Filter.WantStylus.
+	wantStylus bool
 
 	sourceMimes []string
 	targetMimes []string
@@ -272,6 +279,47 @@ func (q *pointerQueue) grab(state pointerState, req pointer.GrabCmd) (pointerSta
 	return state, evts
 }
 
+// capture starts an exclusive pointer.CaptureCmd capture on the pointer
+// identified by req.ID, cancelling every other handler that had matched
+// it so that Push can bypass hit-testing for it until the capture ends.
+func (q *pointerQueue) capture(state pointerState, req pointer.CaptureCmd) (pointerState, []taggedEvent) {
+	var evts []taggedEvent
+	for i, p := range state.pointers {
+		if p.id != req.ID {
+			continue
+		}
+		for _, tag := range p.handlers {
+			if tag != req.Tag {
+				evts = append(evts, taggedEvent{
+					tag:   tag,
+					event: pointer.Event{Kind: pointer.Cancel},
+				})
+			}
+		}
+		state.pointers = append([]pointerInfo{}, state.pointers...)
+		p.captured = req.Tag
+		p.handlers = []event.Tag{req.Tag}
+		state.pointers[i] = p
+		break
+	}
+	return state, evts
+}
+
+// releaseCapture ends the capture held on the pointer identified by
+// req.ID, if any, resuming ordinary hit-tested delivery.
+func (q *pointerQueue) releaseCapture(state pointerState, req pointer.ReleaseCaptureCmd) (pointerState, []taggedEvent) {
+	for i, p := range state.pointers {
+		if p.id != req.ID || p.captured == nil {
+			continue
+		}
+		state.pointers = append([]pointerInfo{}, state.pointers...)
+		p.captured = nil
+		state.pointers[i] = p
+		break
+	}
+	return state, nil
+}
+
 func (c *pointerCollector) inputOp(tag event.Tag, state *pointerHandler) {
 	areaID := c.currentArea()
 	area := &c.q.areas[areaID]
@@ -299,6 +347,7 @@ func (p *pointerFilter) Add(f event.Filter) {
 		p.kinds = p.kinds | f.Kinds
 		p.scrollX = p.scrollX.Union(f.ScrollX)
 		p.scrollY = p.scrollY.Union(f.ScrollY)
+		p.wantStylus = p.wantStylus || f.WantStylus
 	}
 }
 
@@ -328,6 +377,7 @@ func (p *pointerFilter) Merge(p2 pointerFilter) {
 	p.kinds = p.kinds | p2.kinds
 	p.scrollX = p.scrollX.Union(p2.scrollX)
 	p.scrollY = p.scrollY.Union(p2.scrollY)
+	p.wantStylus = p.wantStylus || p2.wantStylus
 	p.sourceMimes = append(p.sourceMimes, p2.sourceMimes...)
 	p.targetMimes = append(p.targetMimes, p2.targetMimes...)
 }
@@ -742,6 +792,10 @@ func (q *pointerQueue) Push(handlers map[event.Tag]*handler, state pointerState,
 	state, pidx := state.pointerOf(e)
 	p := state.pointers[pidx]
 
+	if p.captured != nil {
+		return q.pushCaptured(handlers, state, pidx, p, e)
+	}
+
 	switch e.Kind {
 	case pointer.Press:
 		p, evts, state.cursor, _ = q.deliverEnterLeaveEvents(handlers, state.cursor, p, evts, e)
@@ -780,6 +834,51 @@ func (q *pointerQueue) Push(handlers map[event.Tag]*handler, state pointerState,
 	return state, evts
 }
 
+// pushCaptured delivers e directly to the tag holding a pointer.CaptureCmd
+// capture on p, bypassing hit-testing and enter/leave tracking so that
+// every sample, however far outside the capturing handler's area, reaches
+// it marked Raw and Grabbed. The capture ends on a Release.
+func (q *pointerQueue) pushCaptured(handlers map[event.Tag]*handler, state pointerState, pidx int, p pointerInfo, e pointer.Event) (pointerState, []taggedEvent) {
+	if e.Kind == pointer.Move && p.pressed {
+		e.Kind = pointer.Drag
+	}
+	e.Priority = pointer.Grabbed
+	e.Raw = true
+	if h, ok := handlers[p.captured]; !ok || !h.filter.pointer.wantStylus {
+		e = normalizeStylus(e)
+	}
+	evts := []taggedEvent{{tag: p.captured, event: e}}
+
+	switch e.Kind {
+	case pointer.Press:
+		p.pressed = true
+	case pointer.Release:
+		p.pressed = false
+		p.captured = nil
+	}
+	p.last = e
+
+	state.pointers = append([]pointerInfo{}, state.pointers...)
+	state.pointers[pidx] = p
+	return state, evts
+}
+
+// normalizeStylus downgrades a Stylus-sourced e to Mouse and zeroes its
+// extended stylus fields, for delivery to a handler that didn't set
+// Filter.WantStylus.
+func normalizeStylus(e pointer.Event) pointer.Event {
+	if e.Source != pointer.Stylus {
+		return e
+	}
+	e.Source = pointer.Mouse
+	e.Pressure = 0
+	e.TangentialPressure = 0
+	e.TiltX, e.TiltY = 0, 0
+	e.Twist = 0
+	e.StylusButtons = 0
+	return e
+}
+
 func (q *pointerQueue) deliverEvent(handlers map[event.Tag]*handler, p pointerInfo, evts []taggedEvent, e pointer.Event) []taggedEvent {
 	foremost := true
 	if p.pressed && len(p.handlers) == 1 {
@@ -808,6 +907,9 @@ func (q *pointerQueue) deliverEvent(handlers map[event.Tag]*handler, p pointerIn
 			e.Priority = pointer.Foremost
 		}
 		e.Position = q.invTransform(h.pointer.areaPlusOne-1, e.Position)
+		if !f.wantStylus {
+			e = normalizeStylus(e)
+		}
 		evts = append(evts, taggedEvent{event: e, tag: k})
 	}
 	return evts
@@ -173,6 +173,136 @@ func TestPointerGrabSameHandlerTwice(t *testing.T) {
 	assertEventPointerTypeSequence(t, events(&r, -1, filter(handler2)), pointer.Cancel)
 }
 
+func TestPointerCapture(t *testing.T) {
+	handler1 := new(int)
+	handler2 := new(int)
+	var ops op.Ops
+
+	filter := func(t event.Tag) event.Filter {
+		return pointer.Filter{
+			Target: t,
+			Kinds:  pointer.Press | pointer.Release | pointer.Move | pointer.Drag | pointer.Cancel,
+		}
+	}
+
+	// handler1's area is nowhere near handler2's.
+	r1 := clip.Rect(image.Rect(0, 0, 50, 50)).Push(&ops)
+	event.Op(&ops, handler1)
+	r1.Pop()
+	r2 := clip.Rect(image.Rect(100, 100, 150, 150)).Push(&ops)
+	event.Op(&ops, handler2)
+	r2.Pop()
+
+	var r Router
+	assertEventPointerTypeSequence(t, events(&r, -1, filter(handler1)), pointer.Cancel)
+	assertEventPointerTypeSequence(t, events(&r, -1, filter(handler2)), pointer.Cancel)
+	r.Frame(&ops)
+
+	r.Queue(pointer.Event{Kind: pointer.Press, Position: f32.Pt(10, 10)})
+	assertEventPointerTypeSequence(t, events(&r, 1, filter(handler1)), pointer.Press)
+
+	r.Source().Execute(pointer.CaptureCmd{Tag: handler1})
+
+	// Once captured, a Move is delivered to handler1 as a Raw, Grabbed
+	// Drag sample even far outside its area -- normally handler2's
+	// territory -- bypassing hit-testing entirely.
+	r.Queue(pointer.Event{Kind: pointer.Move, Position: f32.Pt(120, 120)})
+	evts := events(&r, 1, filter(handler1))
+	assertEventPointerTypeSequence(t, evts, pointer.Drag)
+	if pe := evts[0].(pointer.Event); !pe.Raw || pe.Priority != pointer.Grabbed {
+		t.Errorf("captured event wasn't marked Raw and Grabbed: %+v", pe)
+	}
+	assertEventPointerTypeSequence(t, events(&r, -1, filter(handler2)))
+
+	r.Queue(pointer.Event{Kind: pointer.Release, Position: f32.Pt(120, 120)})
+	assertEventPointerTypeSequence(t, events(&r, 1, filter(handler1)), pointer.Release)
+
+	// The Release ended the capture: a further Move is hit-tested as
+	// usual and no longer reaches handler1.
+	r.Queue(pointer.Event{Kind: pointer.Move, Position: f32.Pt(120, 120)})
+	assertEventPointerTypeSequence(t, events(&r, -1, filter(handler1)))
+	assertEventPointerTypeSequence(t, events(&r, 1, filter(handler2)), pointer.Move)
+}
+
+func TestPointerCaptureRelease(t *testing.T) {
+	handler1 := new(int)
+	var ops op.Ops
+
+	filter := pointer.Filter{
+		Target: handler1,
+		Kinds:  pointer.Press | pointer.Release | pointer.Move | pointer.Drag | pointer.Cancel,
+	}
+
+	cl := clip.Rect(image.Rect(0, 0, 50, 50)).Push(&ops)
+	event.Op(&ops, handler1)
+	cl.Pop()
+
+	var r Router
+	assertEventPointerTypeSequence(t, events(&r, -1, filter), pointer.Cancel)
+	r.Frame(&ops)
+
+	r.Queue(pointer.Event{Kind: pointer.Press, Position: f32.Pt(10, 10)})
+	assertEventPointerTypeSequence(t, events(&r, 1, filter), pointer.Press)
+
+	r.Source().Execute(pointer.CaptureCmd{Tag: handler1})
+	r.Source().Execute(pointer.ReleaseCaptureCmd{})
+
+	// With the capture released, the still-pressed pointer is delivered
+	// as usual: handler1 keeps the Drag of the press it received, even
+	// outside its area, but the event is no longer marked Raw.
+	r.Queue(pointer.Event{Kind: pointer.Move, Position: f32.Pt(500, 500)})
+	evts := events(&r, 1, filter)
+	assertEventPointerTypeSequence(t, evts, pointer.Drag)
+	if pe := evts[0].(pointer.Event); pe.Raw {
+		t.Errorf("event after ReleaseCaptureCmd was still marked Raw: %+v", pe)
+	}
+}
+
+func TestPointerStylus(t *testing.T) {
+	plain := new(int)
+	ink := new(int)
+	var ops op.Ops
+
+	plainFilter := pointer.Filter{Target: plain, Kinds: pointer.Move | pointer.Cancel}
+	inkFilter := pointer.Filter{Target: ink, Kinds: pointer.Move | pointer.Cancel, WantStylus: true}
+
+	r1 := clip.Rect(image.Rect(0, 0, 50, 50)).Push(&ops)
+	event.Op(&ops, plain)
+	r1.Pop()
+	r2 := clip.Rect(image.Rect(0, 0, 50, 50)).Push(&ops)
+	event.Op(&ops, ink)
+	r2.Pop()
+
+	var r Router
+	assertEventPointerTypeSequence(t, events(&r, -1, plainFilter), pointer.Cancel)
+	assertEventPointerTypeSequence(t, events(&r, -1, inkFilter), pointer.Cancel)
+	r.Frame(&ops)
+
+	r.Queue(pointer.Event{
+		Kind:     pointer.Move,
+		Source:   pointer.Stylus,
+		Position: f32.Pt(10, 10),
+		Pressure: 0.5,
+		TiltX:    0.2,
+	})
+
+	// ink requested WantStylus, so it sees the Stylus source and the
+	// pressure sample.
+	evts := events(&r, 1, inkFilter)
+	assertEventPointerTypeSequence(t, evts, pointer.Move)
+	if pe := evts[0].(pointer.Event); pe.Source != pointer.Stylus || pe.Pressure != 0.5 || pe.TiltX != 0.2 {
+		t.Errorf("WantStylus handler didn't see the stylus fields: %+v", pe)
+	}
+
+	// plain didn't, so Gio downgrades it to an ordinary Mouse Move with
+	// the stylus fields zeroed.
+	evts = events(&r, 1, plainFilter)
+	assertEventPointerTypeSequence(t, evts, pointer.Move)
+	if pe := evts[0].(pointer.Event); pe.Source != pointer.Mouse || pe.Pressure != 0 || pe.TiltX != 0 {
+		t.Errorf("plain handler wasn't normalized: %+v", pe)
+	}
+}
+
 func TestPointerMove(t *testing.T) {
 	handler1 := new(int)
 	handler2 := new(int)
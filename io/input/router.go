@@ -3,6 +3,7 @@
 package input
 
 import (
+	"encoding/binary"
 	"image"
 	"io"
 	"strings"
@@ -38,6 +39,9 @@ type Router struct {
 		filter        keyFilter
 		nextFilter    keyFilter
 		scratchFilter keyFilter
+		// lastGroup is the group of the most recently delivered scoped
+		// key.Event, consumed by a key.ConsumeOp.
+		lastGroup int
 	}
 	cqueue clipboardQueue
 	// states is the list of pending state changes resulting from
@@ -162,6 +166,11 @@ type inputState struct {
 type taggedEvent struct {
 	event event.Event
 	tag   event.Tag
+	// group, if non-zero, is the key dispatch group this event belongs
+	// to. Events sharing a group are the priority-ordered candidates for
+	// a single key.Event; a key.ConsumeOp drops the ones not yet
+	// delivered.
+	group int
 }
 
 // Source returns a Source backed by this Router.
@@ -281,7 +290,11 @@ func (q *Router) Event(filters ...event.Filter) (event.Event, bool) {
 				match := false
 				switch e := evt.event.(type) {
 				case key.Event:
-					match = q.key.scratchFilter.Matches(change.state.keyState.focus, e, false)
+					if evt.tag != nil {
+						match = q.key.scratchFilter.MatchesTagged(e, false, evt.tag)
+					} else {
+						match = q.key.scratchFilter.Matches(change.state.keyState.focus, e, false)
+					}
 				default:
 					for _, tf := range q.scratchFilters {
 						if evt.tag == tf.tag && tf.filter.Matches(evt.event) {
@@ -294,6 +307,9 @@ func (q *Router) Event(filters ...event.Filter) (event.Event, bool) {
 					change.events = append(change.events[:j], change.events[j+1:]...)
 					// Fast forward state to last matched.
 					q.collapseState(i)
+					if evt.group != 0 {
+						q.key.lastGroup = evt.group
+					}
 					return evt.event, true
 				}
 			}
@@ -321,7 +337,10 @@ func (q *Router) collapseState(idx int) {
 
 // Frame replaces the declared handlers from the supplied
 // operation list. The text input state, wakeup time and whether
-// there are active profile handlers is also saved.
+// there are active profile handlers is also saved. Frame also
+// re-resolves hover, focus and cursor state against the new hit
+// areas, so callers should invoke it before the frame reaches the
+// GPU to avoid a frame of stale hover state.
 func (q *Router) Frame(frame *op.Ops) {
 	var remaining []event.Event
 	if n := len(q.changes); n > 0 {
@@ -432,10 +451,7 @@ func (q *Router) processEvent(e event.Event, system bool) {
 		state.pointerState = pstate
 		q.changeState(e, state, evts)
 	case key.Event:
-		var evts []taggedEvent
-		if q.key.filter.Matches(state.keyState.focus, e, system) {
-			evts = append(evts, taggedEvent{event: e})
-		}
+		evts := q.key.queue.Dispatch(q.handlers, q.pointer.queue.areas, q.key.filter, state.keyState.focus, e, system)
 		q.changeState(e, state, evts)
 	case key.SnippetEvent:
 		// Expand existing, overlapping snippet.
@@ -468,6 +484,13 @@ func (q *Router) processEvent(e event.Event, system bool) {
 }
 
 func (q *Router) execute(c Command) {
+	if _, ok := c.(key.ConsumeOp); ok {
+		// ConsumeOp only removes already queued, lower-priority key events:
+		// it carries no state change and must not trigger the event replay
+		// below.
+		q.consumeKeyGroup(q.key.lastGroup)
+		return
+	}
 	// The command can be executed immediately if event delivery is not frozen, and
 	// no event receiver has completed their event handling.
 	if !q.deferring {
@@ -541,6 +564,10 @@ func (q *Router) executeCommand(c Command) stateChange {
 		state.clipboardState = q.cqueue.ProcessReadClipboard(state.clipboardState, req.Tag)
 	case pointer.GrabCmd:
 		state.pointerState, evts = q.pointer.queue.grab(state.pointerState, req)
+	case pointer.CaptureCmd:
+		state.pointerState, evts = q.pointer.queue.capture(state.pointerState, req)
+	case pointer.ReleaseCaptureCmd:
+		state.pointerState, evts = q.pointer.queue.releaseCapture(state.pointerState, req)
 	case op.InvalidateCmd:
 		if !q.wakeup || req.At.Before(q.wakeupTime) {
 			q.wakeup = true
@@ -550,6 +577,27 @@ func (q *Router) executeCommand(c Command) stateChange {
 	return stateChange{state: state, events: evts}
 }
 
+// consumeKeyGroup drops the events of group that are still pending,
+// stopping a scoped key.Event from reaching the bindings that haven't
+// had a chance to claim it yet.
+func (q *Router) consumeKeyGroup(group int) {
+	if group == 0 {
+		return
+	}
+	for i := range q.changes {
+		change := &q.changes[i]
+		j := 0
+		for _, evt := range change.events {
+			if evt.group == group {
+				continue
+			}
+			change.events[j] = evt
+			j++
+		}
+		change.events = change.events[:j]
+	}
+}
+
 func (q *Router) changeState(e event.Event, state inputState, evts []taggedEvent) {
 	// Wrap pointer.DataEvent.Open functions to detect them not being called.
 	for i := range evts {
@@ -745,6 +793,28 @@ func (q *Router) EditorState() EditorState {
 	return q.key.queue.editorState(q.handlers, q.state().keyState)
 }
 
+// Binding describes a [key.BindingOp] registered in the most recent frame.
+type Binding struct {
+	Tag   event.Tag
+	Scope key.Scope
+}
+
+// Bindings returns the key bindings registered with [key.BindingOp] in
+// the most recent frame, keyed by their [key.Shortcut] as declared. It is
+// intended for a command palette or help overlay that needs to enumerate
+// the shortcuts active in the current frame.
+func (q *Router) Bindings() map[key.Shortcut][]Binding {
+	table := make(map[key.Shortcut][]Binding, len(q.key.queue.bindings))
+	for keys, entries := range q.key.queue.bindings {
+		bindings := make([]Binding, len(entries))
+		for i, e := range entries {
+			bindings[i] = Binding{Tag: e.tag, Scope: e.scope}
+		}
+		table[keys] = bindings
+	}
+	return table
+}
+
 func (q *Router) stateFor(tag event.Tag) *handler {
 	if tag == nil {
 		panic("internal error: nil tag")
@@ -808,6 +878,7 @@ func (q *Router) collect() {
 			pc.inputOp(tag, &s.pointer)
 			a := pc.currentArea()
 			b := pc.currentAreaBounds()
+			s.key.areaPlusOne = a + 1
 			if s.filter.focusable {
 				kq.inputOp(tag, &s.key, t, a, b)
 			}
@@ -830,6 +901,14 @@ func (q *Router) collect() {
 			}
 			s := q.stateFor(op.Tag)
 			s.key.inputHint(op.Hint)
+		case ops.TypeKeyBinding:
+			tag := encOp.Refs[0].(event.Tag)
+			name := *encOp.Refs[1].(*string)
+			bo := binary.LittleEndian
+			mods := key.Modifiers(bo.Uint32(encOp.Data[1:]))
+			scope := key.Scope(encOp.Data[5])
+			q.stateFor(tag)
+			kq.bindingOp(tag, key.Shortcut{Name: key.Name(name), Modifiers: mods}, scope)
 
 		// Semantic ops.
 		case ops.TypeSemanticLabel:
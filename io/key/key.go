@@ -4,6 +4,7 @@
 package key
 
 import (
+	"encoding/binary"
 	"strings"
 
 	"gioui.org/f32"
@@ -32,6 +33,54 @@ type InputHintOp struct {
 	Hint InputHint
 }
 
+// Scope determines which handlers a [BindingOp] can match, regardless of
+// which handler is focused. It has no effect on a plain [Filter], which
+// always behaves as ScopeFocused.
+type Scope uint8
+
+const (
+	// ScopeFocused matches a BindingOp only while its Tag is focused. This
+	// is the default, and the only scope available to a plain [Filter].
+	ScopeFocused Scope = iota
+	// ScopeWindow matches a BindingOp regardless of focus, but only while
+	// its Tag is the focused handler or an ancestor of it. ScopeWindow
+	// bindings are dispatched after the focus, outward along the
+	// ancestor chain, and a handler earlier in that chain can prevent
+	// the ones further out from matching by issuing a [ConsumeOp].
+	ScopeWindow
+	// ScopeGlobal matches a BindingOp regardless of focus, and is
+	// dispatched before every other scope.
+	ScopeGlobal
+)
+
+// Shortcut identifies a key combination for a [BindingOp]: a Name together
+// with the exact set of Modifiers that must be held.
+type Shortcut struct {
+	Name      Name
+	Modifiers Modifiers
+}
+
+// BindingOp declares that Tag should be offered e, key events matching
+// Keys, according to Scope. Unlike a [Filter], which only matches while
+// Tag is focused, a ScopeWindow or ScopeGlobal BindingOp lets a handler
+// react to a key combination regardless of the current focus.
+//
+// [Router.Queue] dispatches a key.Event in priority order: any matching
+// ScopeGlobal binding first, then the focused handler's own ScopeFocused
+// binding or [Filter], then matching ScopeWindow bindings along the
+// ancestor chain of the focused handler, outward from the focus. A
+// handler can issue a [ConsumeOp] while handling the event to stop it
+// from reaching the remaining, lower-priority bindings.
+type BindingOp struct {
+	Tag   event.Tag
+	Keys  Shortcut
+	Scope Scope
+}
+
+// ConsumeOp stops the key.Event most recently delivered to the caller
+// from being dispatched to any other, lower-priority [BindingOp].
+type ConsumeOp struct{}
+
 // SoftKeyboardCmd shows or hides the on-screen keyboard, if available.
 type SoftKeyboardCmd struct {
 	Show bool
@@ -105,8 +154,27 @@ type EditEvent struct {
 	Text  string
 }
 
-// FocusFilter matches any [FocusEvent], [EditEvent], [SnippetEvent],
-// or [SelectionEvent] with the specified target.
+// A PreEditEvent reports the in-progress, not yet committed text of an
+// input method editing session, such as a dead-key sequence or an IME
+// composition. A handler should display Text distinctly from committed
+// text, for example with an underline, and replace it once a following
+// EditEvent commits the final result or a PreEditEvent with an empty
+// Text cancels the sequence.
+type PreEditEvent struct {
+	Text string
+}
+
+// A LayoutEvent is generated when the active keyboard layout group
+// changes, for example when the user switches between Latin and
+// Cyrillic layouts. Group is the platform-specific index of the new
+// layout; apps that display shortcut labels should re-derive them
+// from the [Name] of their [Filter]s after receiving one.
+type LayoutEvent struct {
+	Group int
+}
+
+// FocusFilter matches any [FocusEvent], [EditEvent], [PreEditEvent],
+// [SnippetEvent], or [SelectionEvent] with the specified target.
 type FocusFilter struct {
 	// Target is a tag specified in a previous event.Op.
 	Target event.Tag
@@ -163,6 +231,13 @@ const (
 	// ModSuper is the "logo" modifier key, often
 	// represented by a Windows logo.
 	ModSuper
+	// ModAltGr is the AltGr/Mod5 modifier key used on many non-US
+	// keyboard layouts to access a third level of key symbols.
+	ModAltGr
+	// ModCapsLock is set while Caps Lock is toggled on.
+	ModCapsLock
+	// ModNumLock is set while Num Lock is toggled on.
+	ModNumLock
 )
 
 // Name is the identifier for a keyboard key.
@@ -194,6 +269,9 @@ const (
 	NameShift          Name = "Shift"
 	NameAlt            Name = "Alt"
 	NameSuper          Name = "Super"
+	NameAltGr          Name = "AltGr"
+	NameCapsLock       Name = "CapsLock"
+	NameNumLock        Name = "NumLock"
 	NameCommand        Name = "⌘"
 	NameF1             Name = "F1"
 	NameF2             Name = "F2"
@@ -243,7 +321,20 @@ func (h InputHintOp) Add(o *op.Ops) {
 	data[1] = byte(h.Hint)
 }
 
+func (b BindingOp) Add(o *op.Ops) {
+	if b.Tag == nil {
+		panic("Tag must be non-nil")
+	}
+	data := ops.Write2String(&o.Internal, ops.TypeKeyBindingLen, b.Tag, string(b.Keys.Name))
+	data[0] = byte(ops.TypeKeyBinding)
+	bo := binary.LittleEndian
+	bo.PutUint32(data[1:], uint32(b.Keys.Modifiers))
+	data[5] = byte(b.Scope)
+}
+
 func (EditEvent) ImplementsEvent()      {}
+func (PreEditEvent) ImplementsEvent()   {}
+func (LayoutEvent) ImplementsEvent()    {}
 func (Event) ImplementsEvent()          {}
 func (FocusEvent) ImplementsEvent()     {}
 func (SnippetEvent) ImplementsEvent()   {}
@@ -253,6 +344,7 @@ func (FocusCmd) ImplementsCommand()        {}
 func (SoftKeyboardCmd) ImplementsCommand() {}
 func (SelectionCmd) ImplementsCommand()    {}
 func (SnippetCmd) ImplementsCommand()      {}
+func (ConsumeOp) ImplementsCommand()       {}
 
 func (Filter) ImplementsFilter()      {}
 func (FocusFilter) ImplementsFilter() {}
@@ -274,6 +366,15 @@ func (m Modifiers) String() string {
 	if m.Contain(ModSuper) {
 		strs = append(strs, string(NameSuper))
 	}
+	if m.Contain(ModAltGr) {
+		strs = append(strs, string(NameAltGr))
+	}
+	if m.Contain(ModCapsLock) {
+		strs = append(strs, string(NameCapsLock))
+	}
+	if m.Contain(ModNumLock) {
+		strs = append(strs, string(NameNumLock))
+	}
 	return strings.Join(strs, "-")
 }
 
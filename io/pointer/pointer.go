@@ -3,7 +3,9 @@
 package pointer
 
 import (
+	"image"
 	"strings"
+	"sync"
 	"time"
 
 	"gioui.org/f32"
@@ -39,6 +41,26 @@ type Event struct {
 	// Modifiers is the set of active modifiers when
 	// the mouse button was pressed.
 	Modifiers key.Modifiers
+	// Raw is true for an uncoalesced, sub-frame sample delivered while the
+	// PointerID is captured by a [CaptureCmd]. Gio otherwise delivers at
+	// most one sample per frame for a given pointer.
+	Raw bool
+	// Pressure is the normalized tip pressure of a Stylus-sourced event,
+	// in the range [0;1]. It is always 0 for Mouse and Touch sources.
+	Pressure float32
+	// TangentialPressure is the normalized barrel/slider pressure of a
+	// Stylus-sourced event, in the range [-1;1], for styli that report
+	// it separately from tip Pressure.
+	TangentialPressure float32
+	// TiltX and TiltY are the stylus tilt off the X and Y axes, in
+	// radians, for a Stylus-sourced event.
+	TiltX, TiltY float32
+	// Twist is the clockwise rotation of a stylus around its own axis,
+	// in radians, for a Stylus-sourced event.
+	Twist float32
+	// StylusButtons are the pressed buttons of a Stylus-sourced event,
+	// such as a barrel button or the eraser end.
+	StylusButtons StylusButtons
 }
 
 // PassOp sets the pass-through mode. InputOps added while the pass-through
@@ -67,6 +89,14 @@ type Filter struct {
 	// ScrollY.Min <= e.Scroll.Y <= ScrollY.Max (vertical axis)
 	ScrollX ScrollRange
 	ScrollY ScrollRange
+	// WantStylus requests the extended stylus fields (Pressure, TiltX,
+	// TiltY, Twist, TangentialPressure, StylusButtons) and the Stylus
+	// Source on events delivered to Target. Without it, Gio downgrades a
+	// stylus-sourced event's Source to Mouse and zeroes those fields, so
+	// ordinary mouse-oriented handlers don't need to special-case pen
+	// input; an ink or drawing widget sets WantStylus to see the richer
+	// stream.
+	WantStylus bool
 }
 
 // ScrollRange describes the range of scrolling distances in an
@@ -81,6 +111,31 @@ type GrabCmd struct {
 	ID  ID
 }
 
+// CaptureCmd requests exclusive, uncoalesced pointer input for the
+// pointer identified by ID, analogous to the browser's
+// setPointerCapture. Unlike GrabCmd, a capture bypasses hit-testing for
+// the duration of the gesture: every event for ID, including the
+// sub-frame Move and Drag samples Gio otherwise coalesces to one per
+// frame, is delivered to Tag with Priority set to Grabbed and Raw set to
+// true, regardless of where the pointer moves. Tag still filters events
+// by Kind as usual, so a capture meant to see motion must filter for
+// Move or Drag.
+//
+// Other handlers that matched the pointer receive a Cancel, as with
+// GrabCmd. The capture ends, and ordinary hit-tested delivery resumes,
+// on the pointer's Release or Cancel, or on an explicit
+// ReleaseCaptureCmd.
+type CaptureCmd struct {
+	Tag event.Tag
+	ID  ID
+}
+
+// ReleaseCaptureCmd ends a capture started by CaptureCmd for the
+// pointer identified by ID. It is a no-op if ID isn't captured.
+type ReleaseCaptureCmd struct {
+	ID ID
+}
+
 type ID uint16
 
 // Kind of an Event.
@@ -95,6 +150,10 @@ type Source uint8
 // Buttons is a set of mouse buttons
 type Buttons uint8
 
+// StylusButtons is a set of stylus side/barrel buttons and the eraser
+// end, reported alongside a Stylus-sourced [Event].
+type StylusButtons uint8
+
 // Cursor denotes a pre-defined cursor shape. Its Add method adds an
 // operation that sets the cursor shape for the current clip area.
 type Cursor byte
@@ -201,6 +260,20 @@ const (
 	Mouse Source = iota
 	// Touch generated event.
 	Touch
+	// Stylus generated event, carrying Pressure, TiltX, TiltY, Twist,
+	// TangentialPressure and StylusButtons. Only delivered to a Filter
+	// with WantStylus set; otherwise reported as Mouse.
+	Stylus
+)
+
+const (
+	// StylusBarrel is the primary barrel/side button of a stylus.
+	StylusBarrel StylusButtons = 1 << iota
+	// StylusBarrelSecondary is the secondary barrel/side button of a
+	// stylus, for styli with two.
+	StylusBarrelSecondary
+	// StylusEraser is set while the stylus is used eraser-end first.
+	StylusEraser
 )
 
 const (
@@ -252,6 +325,64 @@ func (op Cursor) Add(o *op.Ops) {
 	data[1] = byte(op)
 }
 
+// CursorFrame is a single frame of an animated cursor registered with
+// NewAnimatedCursor. Duration is how long the frame is displayed
+// before the cursor advances to the next one; it is ignored for a
+// cursor registered with NewCursor.
+type CursorFrame struct {
+	Img      image.Image
+	Hotspot  image.Point
+	Duration time.Duration
+}
+
+// firstCustomCursor is the first Cursor value available for
+// application-defined cursors registered with NewCursor or
+// NewAnimatedCursor; values below it name the predefined cursors.
+const firstCustomCursor = CursorNorthWestSouthEastResize + 1
+
+var (
+	customCursorMu sync.Mutex
+	customCursors  [][]CursorFrame
+)
+
+// NewCursor registers img as a custom cursor shape with its hotspot,
+// the point within img that tracks the pointer position, and returns
+// a Cursor that can be used with Cursor.Add like any predefined
+// cursor. Backends without support for custom cursor images fall back
+// to CursorDefault.
+func NewCursor(img image.Image, hotspot image.Point) Cursor {
+	return registerCursor([]CursorFrame{{Img: img, Hotspot: hotspot}})
+}
+
+// NewAnimatedCursor registers a cursor that cycles through frames, for
+// use as a busy or wait indicator. len(frames) must be at least 1.
+func NewAnimatedCursor(frames []CursorFrame) Cursor {
+	return registerCursor(append([]CursorFrame{}, frames...))
+}
+
+func registerCursor(frames []CursorFrame) Cursor {
+	customCursorMu.Lock()
+	defer customCursorMu.Unlock()
+	idx := len(customCursors)
+	if int(firstCustomCursor)+idx > 0xff {
+		panic("pointer: too many custom cursors registered")
+	}
+	customCursors = append(customCursors, frames)
+	return Cursor(int(firstCustomCursor) + idx)
+}
+
+// Frames reports the frames registered for a custom Cursor returned by
+// NewCursor or NewAnimatedCursor, and whether c is such a cursor.
+func (c Cursor) Frames() ([]CursorFrame, bool) {
+	customCursorMu.Lock()
+	defer customCursorMu.Unlock()
+	idx := int(c) - int(firstCustomCursor)
+	if idx < 0 || idx >= len(customCursors) {
+		return nil, false
+	}
+	return customCursors[idx], true
+}
+
 func (t Kind) String() string {
 	if t == Cancel {
 		return "Cancel"
@@ -310,6 +441,8 @@ func (s Source) String() string {
 		return "Mouse"
 	case Touch:
 		return "Touch"
+	case Stylus:
+		return "Stylus"
 	default:
 		panic("unknown source")
 	}
@@ -335,6 +468,25 @@ func (b Buttons) String() string {
 	return strings.Join(strs, "|")
 }
 
+// Contain reports whether the set b contains all of buttons.
+func (b StylusButtons) Contain(buttons StylusButtons) bool {
+	return b&buttons == buttons
+}
+
+func (b StylusButtons) String() string {
+	var strs []string
+	if b.Contain(StylusBarrel) {
+		strs = append(strs, "StylusBarrel")
+	}
+	if b.Contain(StylusBarrelSecondary) {
+		strs = append(strs, "StylusBarrelSecondary")
+	}
+	if b.Contain(StylusEraser) {
+		strs = append(strs, "StylusEraser")
+	}
+	return strings.Join(strs, "|")
+}
+
 func (c Cursor) String() string {
 	switch c {
 	case CursorDefault:
@@ -390,6 +542,9 @@ func (c Cursor) String() string {
 	case CursorNorthWestSouthEastResize:
 		return "NorthWestSouthEastResize"
 	default:
+		if _, ok := c.Frames(); ok {
+			return "Custom"
+		}
 		panic("unknown Type")
 	}
 }
@@ -398,4 +553,8 @@ func (Event) ImplementsEvent() {}
 
 func (GrabCmd) ImplementsCommand() {}
 
+func (CaptureCmd) ImplementsCommand() {}
+
+func (ReleaseCaptureCmd) ImplementsCommand() {}
+
 func (Filter) ImplementsFilter() {}
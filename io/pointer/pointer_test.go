@@ -3,6 +3,7 @@
 package pointer
 
 import (
+	"image"
 	"testing"
 )
 
@@ -31,3 +32,41 @@ func TestTypeString(t *testing.T) {
 		})
 	}
 }
+
+func TestCustomCursor(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 4, 4))
+	hotspot := image.Pt(2, 2)
+	c1 := NewCursor(img, hotspot)
+	c2 := NewCursor(img, hotspot)
+	if c1 == c2 {
+		t.Fatal("distinct NewCursor calls returned the same Cursor")
+	}
+	for _, c := range []Cursor{c1, c2} {
+		frames, ok := c.Frames()
+		if !ok {
+			t.Fatalf("Frames(%v): expected a registered custom cursor", c)
+		}
+		if len(frames) != 1 || frames[0].Hotspot != hotspot {
+			t.Fatalf("Frames(%v) = %v, want a single frame with hotspot %v", c, frames, hotspot)
+		}
+	}
+	if _, ok := CursorDefault.Frames(); ok {
+		t.Fatal("CursorDefault.Frames(): expected a predefined cursor to not be custom")
+	}
+	if got := c1.String(); got != "Custom" {
+		t.Errorf("got %q, want %q", got, "Custom")
+	}
+}
+
+func TestAnimatedCursor(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 4, 4))
+	frames := []CursorFrame{
+		{Img: img, Hotspot: image.Pt(0, 0), Duration: 100},
+		{Img: img, Hotspot: image.Pt(0, 0), Duration: 100},
+	}
+	c := NewAnimatedCursor(frames)
+	got, ok := c.Frames()
+	if !ok || len(got) != 2 {
+		t.Fatalf("Frames(%v) = %v, %v, want the 2 registered frames", c, got, ok)
+	}
+}
@@ -0,0 +1,67 @@
+// SPDX-License-Identifier: Unlicense OR MIT
+
+package system
+
+// TextDirection is the reading direction of a piece of text.
+type TextDirection uint8
+
+const (
+	// LTR is left-to-right text, such as English or Hindi.
+	LTR TextDirection = iota
+	// RTL is right-to-left text, such as Hebrew or Arabic.
+	RTL
+	// TTB is top-to-bottom text, such as vertical Japanese.
+	TTB
+	// BTT is bottom-to-top text, such as vertical Mongolian.
+	BTT
+)
+
+// Axis indicates whether a TextDirection advances horizontally or
+// vertically.
+type Axis uint8
+
+const (
+	// Horizontal is the axis of LTR and RTL text.
+	Horizontal Axis = iota
+	// Vertical is the axis of TTB and BTT text.
+	Vertical
+)
+
+// Axis reports the layout axis that d advances along.
+func (d TextDirection) Axis() Axis {
+	switch d {
+	case TTB, BTT:
+		return Vertical
+	default:
+		return Horizontal
+	}
+}
+
+// Progression indicates whether text advances away from its origin or
+// towards it along its Axis.
+type Progression uint8
+
+const (
+	// FromOrigin text advances away from the origin, as LTR and TTB text do.
+	FromOrigin Progression = iota
+	// TowardOrigin text advances towards the origin, as RTL and BTT text do.
+	TowardOrigin
+)
+
+// Progression reports the progression of d.
+func (d TextDirection) Progression() Progression {
+	switch d {
+	case RTL, BTT:
+		return TowardOrigin
+	default:
+		return FromOrigin
+	}
+}
+
+// Locale describes the language and text direction preferred by the user.
+type Locale struct {
+	// Language is a BCP 47 language tag.
+	Language string
+	// Direction is the direction text should be laid out in.
+	Direction TextDirection
+}
@@ -5,6 +5,7 @@ package layout
 import (
 	"fmt"
 	"strconv"
+	"strings"
 
 	"gioui.org/unit"
 )
@@ -14,6 +15,11 @@ type formatter struct {
 	orig    string
 	expr    string
 	skip    int
+
+	// trace, if non-nil, is the node in a FormatTrace that the
+	// formatter is currently building children for. It is nil unless
+	// the format string is being executed through FormatDebug.
+	trace *FormatTrace
 }
 
 type formatError string
@@ -30,11 +36,11 @@ type formatError string
 //
 // For example,
 //
-//   layout.Format(gtx, "inset(8dp, _)", w)
+//	layout.Format(gtx, "inset(8dp, _)", w)
 //
 // is equivalent to
 //
-//   layout.UniformInset(unit.Dp(8)).Layout(gtx, w)
+//	layout.UniformInset(unit.Dp(8)).Layout(gtx, w)
 //
 // Available layouts:
 //
@@ -67,6 +73,16 @@ type formatError string
 // on the form e(widget).
 // If alignment is specified it must be one of the directions listed
 // above.
+//
+// grid(columns, rows, gap, cells...) lays out children with a Grid.
+// columns and rows are bracketed, comma-separated lists of tracks:
+// auto and min size a track to the largest, respectively smallest,
+// intrinsic size of its cells; a unit value such as 120dp fixes the
+// track size; and a bare number followed by fr, such as 1fr, gives
+// the track a share of the space left over once the other tracks are
+// resolved. gap is the uniform spacing applied between columns and
+// rows. Each cell is on the form cell(col, row, colspan, rowspan,
+// widget).
 func Format(gtx *Context, format string, widgets ...Widget) {
 	if format == "" {
 		return
@@ -89,12 +105,44 @@ func Format(gtx *Context, format string, widgets ...Widget) {
 }
 
 func formatExpr(gtx *Context, f *formatter, widgets []Widget) {
+	if f.trace == nil || f.skip > 0 {
+		switch peek(f) {
+		case '_':
+			formatWidget(gtx, f, widgets)
+		default:
+			formatLayout(gtx, f, widgets)
+		}
+		return
+	}
+	formatExprTraced(gtx, f, widgets)
+}
+
+// formatExprTraced is formatExpr with tracing: it records the node's
+// position, incoming and outgoing constraints and resolved size into
+// a new child of f.trace before descending into it.
+func formatExprTraced(gtx *Context, f *formatter, widgets []Widget) {
+	parent := f.trace
+	node := &FormatTrace{
+		Pos:           len(f.orig) - len(f.expr),
+		ConstraintsIn: gtx.Constraints,
+	}
+	parent.Children = append(parent.Children, node)
+	f.trace = node
 	switch peek(f) {
 	case '_':
+		node.Name = "_"
+		node.Widget = f.current
 		formatWidget(gtx, f, widgets)
 	default:
+		node.Widget = -1
 		formatLayout(gtx, f, widgets)
+		if i := strings.IndexByte(f.orig[node.Pos:], '('); i >= 0 {
+			node.Name = strings.TrimSpace(f.orig[node.Pos : node.Pos+i])
+		}
 	}
+	node.ConstraintsOut = gtx.Constraints
+	node.Size = gtx.Dimensions.Size
+	f.trace = parent
 }
 
 func formatLayout(gtx *Context, f *formatter, widgets []Widget) {
@@ -122,6 +170,8 @@ func formatLayout(gtx *Context, f *formatter, widgets []Widget) {
 		formatFlex(gtx, Vertical, f, widgets)
 	case "stack":
 		formatStack(gtx, f, widgets)
+	case "grid":
+		formatGrid(gtx, f, widgets)
 	case "hmax":
 		cs := gtx.Constraints
 		cs.Width.Min = cs.Width.Max
@@ -352,6 +402,107 @@ loop:
 	}
 }
 
+func formatGrid(gtx *Context, f *formatter, widgets []Widget) {
+	cols := parseTrackList(f)
+	expect(f, ",")
+	rows := parseTrackList(f)
+	expect(f, ",")
+	gap := parseValue(f)
+	g := Grid{Columns: cols, Rows: rows, ColumnGap: gap, RowGap: gap}
+	var cells []GridCell
+	for peek(f) == ',' {
+		expect(f, ",")
+		cells = append(cells, parseCell(gtx, f, widgets))
+	}
+	if f.skip == 0 {
+		g.Layout(gtx, cells...)
+	}
+}
+
+// parseCell parses a cell(col, row, colspan, rowspan, widget) token.
+// The widget sub-expression is parsed once to advance past it without
+// being laid out, and its source text is captured so it can be
+// replayed, against the same widget index, once Grid has resolved
+// where the cell belongs.
+func parseCell(gtx *Context, f *formatter, widgets []Widget) GridCell {
+	expect(f, "cell(")
+	col := parseInt(f)
+	expect(f, ",")
+	row := parseInt(f)
+	expect(f, ",")
+	colspan := parseInt(f)
+	expect(f, ",")
+	rowspan := parseInt(f)
+	expect(f, ",")
+	skipWhitespace(f)
+	start := f.expr
+	startIdx := f.current
+	f.skip++
+	formatExpr(gtx, f, widgets)
+	f.skip--
+	sub := start[:len(start)-len(f.expr)]
+	expect(f, ")")
+	return GridCell{
+		Col: col, Row: row,
+		ColSpan: colspan, RowSpan: rowspan,
+		Widget: func() {
+			sf := &formatter{orig: f.orig, expr: sub, current: startIdx, trace: f.trace}
+			formatExpr(gtx, sf, widgets)
+		},
+	}
+}
+
+func parseTrackList(f *formatter) []Track {
+	expect(f, "[")
+	var tracks []Track
+	for {
+		tracks = append(tracks, parseTrack(f))
+		if peek(f) == ',' {
+			expect(f, ",")
+			continue
+		}
+		break
+	}
+	expect(f, "]")
+	return tracks
+}
+
+func parseTrack(f *formatter) Track {
+	skipWhitespace(f)
+	if len(f.expr) == 0 {
+		errorf("expected track")
+	}
+	if c := f.expr[0]; c == '.' || ('0' <= c && c <= '9') {
+		v := parseFloat(f)
+		if len(f.expr) < 2 {
+			errorf("missing track unit")
+		}
+		u := f.expr[:2]
+		f.expr = f.expr[2:]
+		switch u {
+		case "fr":
+			return FlexTrack(v)
+		case "dp":
+			return FixedTrack(unit.Dp(v))
+		case "sp":
+			return FixedTrack(unit.Sp(v))
+		case "px":
+			return FixedTrack(unit.Px(v))
+		default:
+			errorf("unknown track unit %q", u)
+		}
+	}
+	switch name := parseName(f); name {
+	case "auto":
+		return AutoTrack()
+	case "min":
+		return MinTrack()
+	default:
+		errorf("invalid track %q", name)
+	}
+	panic("unreachable")
+}
+
 func parseInset(gtx *Context, f *formatter, widgets []Widget) Inset {
 	v1 := parseValue(f)
 	if peek(f) == ',' {
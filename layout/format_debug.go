@@ -0,0 +1,68 @@
+// SPDX-License-Identifier: Unlicense OR MIT
+
+package layout
+
+import (
+	"fmt"
+	"image"
+)
+
+// FormatTrace is a node in the tree of layouts and widgets executed
+// by FormatDebug. The root node, returned from FormatDebug, describes
+// the whole format string; its Children are the top-level layout or
+// widget.
+type FormatTrace struct {
+	// Name is the layout name ("inset", "hflex", ...), "_" for a
+	// widget reference, or "root" for the node returned from
+	// FormatDebug.
+	Name string
+	// Pos is the byte offset of this node within the format string
+	// passed to FormatDebug. It is meaningless for the root node.
+	Pos int
+	// Widget is the index of the widget this node invokes, or -1 if
+	// the node is a layout rather than a "_".
+	Widget int
+	// ConstraintsIn and ConstraintsOut are the constraints in effect
+	// when the node started, respectively finished, executing.
+	ConstraintsIn, ConstraintsOut Constraints
+	// Size is the resolved size of the node.
+	Size image.Point
+	// Children are the nodes executed while laying out this node, in
+	// the order they were executed. That differs from format string
+	// order where hflex, vflex and stack run their r() children before
+	// their f() or e() children, and where grid runs the cells outside
+	// fr tracks before those inside them.
+	Children []*FormatTrace
+}
+
+// FormatDebug is Format with tracing: it executes format exactly as
+// Format would, and additionally returns a FormatTrace describing
+// every node executed, so tools can render an overlay of box
+// boundaries and constraint values, or map a "_" back to the widget
+// it produced.
+func FormatDebug(gtx *Context, format string, widgets ...Widget) (Dimensions, *FormatTrace) {
+	root := &FormatTrace{Name: "root", Widget: -1}
+	if format == "" {
+		return Dimensions{}, root
+	}
+	f := formatter{
+		orig:  format,
+		expr:  format,
+		trace: root,
+	}
+	root.ConstraintsIn = gtx.Constraints
+	defer func() {
+		if err := recover(); err != nil {
+			if _, ok := err.(formatError); !ok {
+				panic(err)
+			}
+			pos := len(f.orig) - len(f.expr)
+			msg := f.orig[:pos] + "✗" + f.orig[pos:]
+			panic(fmt.Errorf("FormatDebug: %s:%d: %s", msg, pos, err))
+		}
+	}()
+	formatExpr(gtx, &f, widgets)
+	root.ConstraintsOut = gtx.Constraints
+	root.Size = gtx.Dimensions.Size
+	return gtx.Dimensions, root
+}
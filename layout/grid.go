@@ -0,0 +1,306 @@
+// SPDX-License-Identifier: Unlicense OR MIT
+
+package layout
+
+import (
+	"image"
+
+	"gioui.org/op"
+	"gioui.org/unit"
+)
+
+// Grid lays out children in a two-dimensional grid of independently
+// sized columns and rows. It is a lower-level primitive than Flex or
+// Stack, intended for forms and settings screens that would otherwise
+// require hand-rolled nested flexes.
+type Grid struct {
+	// Columns describes the sizing of each column, left to right.
+	Columns []Track
+	// Rows describes the sizing of each row, top to bottom.
+	Rows []Track
+	// ColumnGap and RowGap add space between adjacent columns and
+	// rows. No space is added before the first or after the last
+	// track.
+	ColumnGap, RowGap unit.Value
+}
+
+// Track describes the sizing of a single grid column or row.
+type Track struct {
+	kind   trackKind
+	size   unit.Value
+	weight float32
+}
+
+type trackKind uint8
+
+const (
+	// TrackAuto sizes the track to the largest intrinsic main-axis
+	// size among the cells that start and end within it.
+	TrackAuto trackKind = iota
+	// TrackMin sizes the track to the smallest intrinsic main-axis
+	// size among the cells that start and end within it.
+	TrackMin
+	// TrackFixed sizes the track to a fixed unit.Value.
+	TrackFixed
+	// TrackFlex distributes the space remaining after Auto, Min and
+	// Fixed tracks are resolved, proportional to weight.
+	TrackFlex
+)
+
+// AutoTrack returns a Track sized to the largest intrinsic size of
+// its cells.
+func AutoTrack() Track { return Track{kind: TrackAuto} }
+
+// MinTrack returns a Track sized to the smallest intrinsic size of
+// its cells.
+func MinTrack() Track { return Track{kind: TrackMin} }
+
+// FixedTrack returns a Track with a fixed size.
+func FixedTrack(size unit.Value) Track { return Track{kind: TrackFixed, size: size} }
+
+// FlexTrack returns a Track that takes a weighted share of the space
+// left over after Auto, Min and Fixed tracks are resolved.
+func FlexTrack(weight float32) Track { return Track{kind: TrackFlex, weight: weight} }
+
+func (t Track) flex() bool { return t.kind == TrackFlex }
+
+// GridCell positions a widget within a Grid. Col and Row are the
+// zero-based track indices of the cell's top-left corner.
+type GridCell struct {
+	Col, Row         int
+	ColSpan, RowSpan int
+	// Alignment positions the widget within its cell area when the
+	// widget is smaller than the area. The default, NW, anchors the
+	// widget to the top-left corner.
+	Alignment Direction
+	Widget    Widget
+
+	// Scratch space, filled in during Layout.
+	call op.CallOp
+	dims Dimensions
+}
+
+// Cell returns a GridCell at (col, row) spanning a single column and
+// row.
+func Cell(col, row int, w Widget) GridCell {
+	return GridCell{Col: col, Row: row, ColSpan: 1, RowSpan: 1, Widget: w}
+}
+
+// Span returns a copy of c spanning colspan columns and rowspan rows.
+func (c GridCell) Span(colspan, rowspan int) GridCell {
+	c.ColSpan, c.RowSpan = colspan, rowspan
+	return c
+}
+
+// Align returns a copy of c aligned within its cell area according to
+// dir.
+func (c GridCell) Align(dir Direction) GridCell {
+	c.Alignment = dir
+	return c
+}
+
+func (c *GridCell) colSpan() int {
+	if c.ColSpan < 1 {
+		return 1
+	}
+	return c.ColSpan
+}
+
+func (c *GridCell) rowSpan() int {
+	if c.RowSpan < 1 {
+		return 1
+	}
+	return c.RowSpan
+}
+
+// Layout the grid's cells. Layout runs a two-pass measure: first, the
+// cells that occupy only Auto, Min or Fixed tracks are laid out to
+// resolve the size of those tracks; second, any space left over is
+// distributed to Flex tracks and the remaining cells are laid out
+// against the now-final track sizes.
+func (g Grid) Layout(gtx *Context, cells ...GridCell) Dimensions {
+	for _, c := range cells {
+		if c.Col < 0 || c.Col+c.colSpan() > len(g.Columns) || c.Row < 0 || c.Row+c.rowSpan() > len(g.Rows) {
+			errorf("grid cell (%d,%d) out of bounds", c.Col, c.Row)
+		}
+	}
+	colWidths := make([]int, len(g.Columns))
+	rowHeights := make([]int, len(g.Rows))
+	colGap := gtx.Px(g.ColumnGap)
+	rowGap := gtx.Px(g.RowGap)
+	for i, t := range g.Columns {
+		if t.kind == TrackFixed {
+			colWidths[i] = gtx.Px(t.size)
+		}
+	}
+	for i, t := range g.Rows {
+		if t.kind == TrackFixed {
+			rowHeights[i] = gtx.Px(t.size)
+		}
+	}
+
+	// Pass 1: lay out cells confined to non-Flex tracks, which
+	// resolves the size of the Auto and Min tracks they occupy.
+	colMinSeen := make([]bool, len(g.Columns))
+	rowMinSeen := make([]bool, len(g.Rows))
+	for i := range cells {
+		c := &cells[i]
+		if spansFlex(g.Columns, c.Col, c.colSpan()) || spansFlex(g.Rows, c.Row, c.rowSpan()) {
+			continue
+		}
+		cs := Constraints{Max: image.Pt(gtx.Constraints.Max.X, gtx.Constraints.Max.Y)}
+		var macro op.MacroOp
+		macro.Record(gtx.Ops)
+		dims := ctxLayout(gtx, cs, c.Widget)
+		c.call = macro.Stop()
+		c.dims = dims
+		if c.colSpan() == 1 {
+			growOrShrinkTrack(g.Columns[c.Col].kind, dims.Size.X, &colWidths[c.Col], &colMinSeen[c.Col])
+		}
+		if c.rowSpan() == 1 {
+			growOrShrinkTrack(g.Rows[c.Row].kind, dims.Size.Y, &rowHeights[c.Row], &rowMinSeen[c.Row])
+		}
+	}
+
+	// Pass 2: distribute remaining space to Flex tracks and lay out
+	// the cells that depend on them.
+	distribute(gtx.Constraints.Max.X, colGap, g.Columns, colWidths)
+	distribute(gtx.Constraints.Max.Y, rowGap, g.Rows, rowHeights)
+	colOffsets := offsets(colWidths, colGap)
+	rowOffsets := offsets(rowHeights, rowGap)
+
+	for i := range cells {
+		c := &cells[i]
+		if !spansFlex(g.Columns, c.Col, c.colSpan()) && !spansFlex(g.Rows, c.Row, c.rowSpan()) {
+			continue
+		}
+		x0, x1 := cellSpan(colOffsets, colWidths, c.Col, c.colSpan(), colGap)
+		y0, y1 := cellSpan(rowOffsets, rowHeights, c.Row, c.rowSpan(), rowGap)
+		cs := Constraints{Min: image.Pt(x1-x0, y1-y0), Max: image.Pt(x1-x0, y1-y0)}
+		var macro op.MacroOp
+		macro.Record(gtx.Ops)
+		dims := ctxLayout(gtx, cs, c.Widget)
+		c.call = macro.Stop()
+		c.dims = dims
+	}
+
+	var size image.Point
+	for i := range cells {
+		c := &cells[i]
+		x0, x1 := cellSpan(colOffsets, colWidths, c.Col, c.colSpan(), colGap)
+		y0, y1 := cellSpan(rowOffsets, rowHeights, c.Row, c.rowSpan(), rowGap)
+		area := image.Rect(x0, y0, x1, y1)
+		p := alignIn(area, c.dims.Size, c.Alignment)
+		var stack op.StackOp
+		stack.Push(gtx.Ops)
+		op.TransformOp{}.Offset(FPt(p)).Add(gtx.Ops)
+		c.call.Add(gtx.Ops)
+		stack.Pop()
+		if b := area.Max.X; b > size.X {
+			size.X = b
+		}
+		if b := y0 + c.dims.Size.Y; b > size.Y {
+			size.Y = b
+		}
+	}
+	return Dimensions{Size: gtx.Constraints.Constrain(size)}
+}
+
+func alignIn(area image.Rectangle, sz image.Point, align Direction) image.Point {
+	p := area.Min
+	switch align {
+	case N, S, Center:
+		p.X += (area.Dx() - sz.X) / 2
+	case NE, SE, E:
+		p.X += area.Dx() - sz.X
+	}
+	switch align {
+	case W, Center, E:
+		p.Y += (area.Dy() - sz.Y) / 2
+	case SW, S, SE:
+		p.Y += area.Dy() - sz.Y
+	}
+	return p
+}
+
+// growOrShrinkTrack updates size, the resolved main-axis size of a
+// single Auto or Min track, given the intrinsic size of one more cell
+// confined to it. Auto tracks grow to their largest cell; Min tracks
+// shrink to their smallest, so the first cell sets size outright and
+// later cells only ever pull it down. Fixed tracks are already
+// resolved and are not passed here.
+func growOrShrinkTrack(kind trackKind, cellSize int, size *int, minSeen *bool) {
+	switch kind {
+	case TrackFixed:
+		// Already resolved; cells never affect a Fixed track's size.
+	case TrackMin:
+		if !*minSeen || cellSize < *size {
+			*size = cellSize
+			*minSeen = true
+		}
+	default:
+		if cellSize > *size {
+			*size = cellSize
+		}
+	}
+}
+
+func spansFlex(tracks []Track, start, span int) bool {
+	for i := start; i < start+span && i < len(tracks); i++ {
+		if tracks[i].flex() {
+			return true
+		}
+	}
+	return false
+}
+
+// distribute grows the Flex tracks in tracks to consume the space
+// left in total after gaps and the already resolved sizes in sizes.
+func distribute(total, gap int, tracks []Track, sizes []int) {
+	used := 0
+	var totalWeight float32
+	for i, t := range tracks {
+		if t.flex() {
+			totalWeight += t.weight
+		} else {
+			used += sizes[i]
+		}
+	}
+	if len(tracks) > 1 {
+		used += gap * (len(tracks) - 1)
+	}
+	remaining := total - used
+	if remaining < 0 || totalWeight == 0 {
+		return
+	}
+	var fraction float32
+	for i, t := range tracks {
+		if !t.flex() {
+			continue
+		}
+		share := float32(remaining)*t.weight/totalWeight + fraction
+		sizes[i] = int(share + .5)
+		fraction = share - float32(sizes[i])
+	}
+}
+
+// offsets returns the leading offset of every track in sizes given
+// the gap between tracks.
+func offsets(sizes []int, gap int) []int {
+	off := make([]int, len(sizes))
+	pos := 0
+	for i, s := range sizes {
+		off[i] = pos
+		pos += s + gap
+	}
+	return off
+}
+
+func cellSpan(offsets, sizes []int, track, span, gap int) (int, int) {
+	last := track + span - 1
+	if last >= len(sizes) {
+		last = len(sizes) - 1
+	}
+	end := offsets[last] + sizes[last]
+	return offsets[track], end
+}
@@ -0,0 +1,64 @@
+// SPDX-License-Identifier: Unlicense OR MIT
+
+package layout
+
+import (
+	"image"
+	"testing"
+
+	"gioui.org/op"
+)
+
+func TestGrowOrShrinkTrack(t *testing.T) {
+	for _, tc := range []struct {
+		label string
+		kind  trackKind
+		sizes []int
+		want  int
+	}{
+		{label: "auto grows to the largest cell", kind: TrackAuto, sizes: []int{10, 30, 20}, want: 30},
+		{label: "min shrinks to the smallest cell", kind: TrackMin, sizes: []int{30, 10, 20}, want: 10},
+		{label: "fixed ignores its cells", kind: TrackFixed, sizes: []int{30, 10, 20}, want: 42},
+	} {
+		t.Run(tc.label, func(t *testing.T) {
+			size := 0
+			if tc.kind == TrackFixed {
+				size = 42
+			}
+			var seen bool
+			for _, s := range tc.sizes {
+				growOrShrinkTrack(tc.kind, s, &size, &seen)
+			}
+			if size != tc.want {
+				t.Errorf("got size %d, want %d", size, tc.want)
+			}
+		})
+	}
+}
+
+func TestGridTrackMin(t *testing.T) {
+	g := Grid{
+		Columns: []Track{MinTrack()},
+		Rows:    []Track{AutoTrack(), AutoTrack()},
+	}
+	gtx := &Context{
+		Ops:         new(op.Ops),
+		Constraints: Constraints{Max: image.Pt(200, 200)},
+	}
+	sized := func(sz image.Point) Widget {
+		return func() {
+			gtx.Dimensions = Dimensions{Size: sz}
+		}
+	}
+	// Two cells share the Min column, one wider than the other. The
+	// column must shrink to the narrower cell, not grow to the wider
+	// one as TrackAuto would.
+	cells := []GridCell{
+		Cell(0, 0, sized(image.Pt(30, 10))),
+		Cell(0, 1, sized(image.Pt(10, 10))),
+	}
+	dims := g.Layout(gtx, cells...)
+	if got, want := dims.Size.X, 10; got != want {
+		t.Errorf("got grid width %d, want %d", got, want)
+	}
+}
@@ -0,0 +1,90 @@
+// SPDX-License-Identifier: Unlicense OR MIT
+
+// Package inspector renders the trace produced by layout.FormatDebug
+// as an overlay of box boundaries and constraint labels, similar to
+// the element inspector of a browser or a GUI toolkit with a retained
+// tree. It lives apart from layout so that layout itself does not
+// depend on a painting backend or a font shaper.
+package inspector
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+
+	"gioui.org/layout"
+	"gioui.org/op"
+	"gioui.org/op/clip"
+	"gioui.org/op/paint"
+	"gioui.org/text"
+	"gioui.org/unit"
+	"gioui.org/widget"
+)
+
+// Overlay paints a box and a label for every node in a
+// layout.FormatTrace, so the boundaries and resolved constraints of a
+// layout.Format tree can be inspected visually.
+//
+// BUG: layout.Format does not record the screen offset of a node,
+// only its size, so nested boxes are drawn inset from their parent by
+// a fixed margin per depth rather than at their true position.
+type Overlay struct {
+	// Shaper draws the per-node label. A nil Shaper disables labels
+	// and draws only the boxes.
+	Shaper *text.Shaper
+	// Color tints the outline and label of every box; its opacity is
+	// reduced with nesting depth so overlapping boxes stay legible.
+	Color color.NRGBA
+}
+
+// Layout paints the overlay for trace and reports the constraints'
+// maximum as its size: the overlay always fills the inspected
+// widget's area.
+func (o Overlay) Layout(gtx layout.Context, trace *layout.FormatTrace) layout.Dimensions {
+	o.paint(gtx, trace, 0)
+	return layout.Dimensions{Size: gtx.Constraints.Max}
+}
+
+// depthInset is the visual offset applied to a nested box per level
+// of depth, to distinguish ancestor and descendant boxes that would
+// otherwise be drawn on top of each other.
+const depthInset = unit.Dp(4)
+
+func (o Overlay) paint(gtx layout.Context, node *layout.FormatTrace, depth int) {
+	inset := gtx.Dp(depthInset) * depth
+	bounds := image.Rect(inset, inset, node.Size.X+inset, node.Size.Y+inset).
+		Intersect(image.Rectangle{Max: gtx.Constraints.Max})
+	if !bounds.Empty() {
+		c := o.Color
+		c.A = fade(c.A, depth)
+		paint.FillShape(gtx.Ops, c, clip.Stroke{
+			Path:  clip.Rect(bounds).Path(),
+			Width: 1,
+		}.Op())
+		if o.Shaper != nil {
+			o.label(gtx, bounds.Min, c, fmt.Sprintf("%s %dx%d", node.Name, node.Size.X, node.Size.Y))
+		}
+	}
+	for _, child := range node.Children {
+		o.paint(gtx, child, depth+1)
+	}
+}
+
+func (o Overlay) label(gtx layout.Context, at image.Point, c color.NRGBA, txt string) {
+	defer op.Offset(at).Push(gtx.Ops).Pop()
+	paint.ColorOp{Color: c}.Add(gtx.Ops)
+	widget.Label{MaxLines: 1}.Layout(gtx, o.Shaper, text.Font{}, unit.Sp(10), txt, op.CallOp{})
+}
+
+// fade reduces a with nesting depth so ancestor and descendant boxes
+// remain distinguishable when they overlap.
+func fade(a uint8, depth int) uint8 {
+	v := int(a)
+	for i := 0; i < depth; i++ {
+		v = v * 2 / 3
+	}
+	if v < 32 {
+		v = 32
+	}
+	return uint8(v)
+}
@@ -0,0 +1,27 @@
+// SPDX-License-Identifier: Unlicense OR MIT
+
+package layout
+
+import (
+	"image"
+
+	"gioui.org/op"
+)
+
+// Overlay lays out w at pos, in the current context's coordinate
+// space, but defers its operations so that they paint after, and are
+// not clipped by, the operations recorded by the surrounding layout.
+// Use it for floating content such as tooltips and menus that must
+// escape their parent's clip area. Overlay reports zero Dimensions:
+// its content does not participate in the surrounding layout's flow.
+func Overlay(gtx Context, pos image.Point, w Widget) Dimensions {
+	m := op.Record(gtx.Ops)
+	w(gtx)
+	call := m.Stop()
+
+	stack := op.Offset(pos).Push(gtx.Ops)
+	op.Defer(gtx.Ops, call)
+	stack.Pop()
+
+	return Dimensions{}
+}
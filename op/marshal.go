@@ -0,0 +1,158 @@
+// SPDX-License-Identifier: Unlicense OR MIT
+
+package op
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+
+	"gioui.org/internal/ops"
+	"gioui.org/internal/stream"
+)
+
+// marshalMagic identifies the Marshal format. It is 8 bytes so every field
+// following it starts on an 8-byte boundary.
+var marshalMagic = [8]byte{'G', 'i', 'o', 'N', 'A', 'R', '1', '\n'}
+
+// Reference tags used to disambiguate the entries of an Ops' ref list.
+const (
+	refNil = iota
+	refString
+)
+
+// Marshal writes o in a deterministic, content-addressable form modeled on
+// Nix's NAR format: a fixed magic header followed by the recorded op
+// stream and its references, in the order they were written. Every
+// variable-length field is prefixed by its length as a little-endian
+// uint64 and padded with zeroes to the next 8-byte boundary, so two
+// recordings of the same sequence of operations marshal to byte-identical
+// output, regardless of pointer identity or allocation history.
+//
+// Marshal only supports references that are themselves deterministic:
+// strings and nil. Any other reference, such as an event tag or an image
+// handle, is neither content-addressable nor reconstructable by Unmarshal,
+// and makes Marshal fail. That restricts Marshal to op lists built from
+// drawing and transform operations, which is enough for golden-file
+// testing of layouts and content-addressed caching of headless-rendered
+// frames.
+//
+// Internally, Marshal writes through stream.Writer.Must and recovers its
+// panic with a deferred stream.Catch, so the loop over refs stays
+// branch-free on the hot path instead of checking an error after every
+// write, the technique used by the standard library's image/gif and
+// image/jpeg encoders.
+func Marshal(w io.Writer, o *Ops) (err error) {
+	defer stream.Catch(&err)
+	sw := &stream.Writer{W: w}
+	mustWriteField(sw, marshalMagic[:])
+	mustWriteUint64(sw, uint64(ops.Version(&o.Internal)))
+	mustWriteField(sw, ops.Data(&o.Internal))
+	refs := ops.Refs(&o.Internal)
+	mustWriteUint64(sw, uint64(len(refs)))
+	for _, ref := range refs {
+		switch r := ref.(type) {
+		case nil:
+			mustWriteUint64(sw, refNil)
+		case *string:
+			mustWriteUint64(sw, refString)
+			mustWriteField(sw, []byte(*r))
+		default:
+			return fmt.Errorf("op: Marshal: unsupported reference type %T", ref)
+		}
+	}
+	return nil
+}
+
+// Unmarshal parses an Ops from the form written by Marshal.
+func Unmarshal(r io.Reader) (*Ops, error) {
+	sr := &stream.Reader{R: r}
+	// The magic field is read at its fixed size rather than with
+	// readField, so that foreign input is rejected instead of being
+	// taken as a length prefix.
+	var magic [8 + len(marshalMagic)]byte
+	if _, err := io.ReadFull(sr, magic[:]); err != nil {
+		return nil, err
+	}
+	if binary.LittleEndian.Uint64(magic[:8]) != uint64(len(marshalMagic)) || string(magic[8:]) != string(marshalMagic[:]) {
+		return nil, fmt.Errorf("op: Unmarshal: invalid magic header")
+	}
+	version, err := readUint64(sr)
+	if err != nil {
+		return nil, err
+	}
+	data, err := readField(sr)
+	if err != nil {
+		return nil, err
+	}
+	nrefs, err := readUint64(sr)
+	if err != nil {
+		return nil, err
+	}
+	refs := make([]any, 0, nrefs)
+	// stringRefs is preallocated to its final size so that appending to it
+	// never reallocates, keeping the *string values stashed in refs valid.
+	stringRefs := make([]string, 0, nrefs)
+	for i := uint64(0); i < nrefs; i++ {
+		tag, err := readUint64(sr)
+		if err != nil {
+			return nil, err
+		}
+		switch tag {
+		case refNil:
+			refs = append(refs, nil)
+		case refString:
+			s, err := readField(sr)
+			if err != nil {
+				return nil, err
+			}
+			stringRefs = append(stringRefs, string(s))
+			refs = append(refs, &stringRefs[len(stringRefs)-1])
+		default:
+			return nil, fmt.Errorf("op: Unmarshal: invalid reference tag %d", tag)
+		}
+	}
+	o := new(Ops)
+	ops.Load(&o.Internal, data, refs, uint32(version))
+	return o, nil
+}
+
+func mustWriteUint64(w *stream.Writer, v uint64) {
+	var b [8]byte
+	binary.LittleEndian.PutUint64(b[:], v)
+	w.Must(b[:])
+}
+
+// mustWriteField writes b length-prefixed by a little-endian uint64 and
+// padded with zeroes to the next 8-byte boundary.
+func mustWriteField(w *stream.Writer, b []byte) {
+	mustWriteUint64(w, uint64(len(b)))
+	w.Must(b)
+	var pad [7]byte
+	w.Must(pad[:-len(b)&7])
+}
+
+func readUint64(r io.Reader) (uint64, error) {
+	var b [8]byte
+	if _, err := io.ReadFull(r, b[:]); err != nil {
+		return 0, err
+	}
+	return binary.LittleEndian.Uint64(b[:]), nil
+}
+
+// readField reads a field written by mustWriteField.
+func readField(r io.Reader) ([]byte, error) {
+	n, err := readUint64(r)
+	if err != nil {
+		return nil, err
+	}
+	b := make([]byte, n)
+	if _, err := io.ReadFull(r, b); err != nil {
+		return nil, err
+	}
+	var pad [7]byte
+	if _, err := io.ReadFull(r, pad[:-int(n)&7]); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
@@ -0,0 +1,75 @@
+// SPDX-License-Identifier: Unlicense OR MIT
+
+package op
+
+import (
+	"bytes"
+	"image"
+	"strings"
+	"testing"
+)
+
+func TestMarshalUnmarshalRoundTrip(t *testing.T) {
+	var o Ops
+	t1 := Offset(image.Pt(1, 2)).Push(&o)
+	t2 := Offset(image.Pt(3, 4)).Push(&o)
+	t2.Pop()
+	t1.Pop()
+
+	var buf bytes.Buffer
+	if err := Marshal(&buf, &o); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := Unmarshal(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var want, gotBuf bytes.Buffer
+	if _, err := o.WriteTo(&want); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := got.WriteTo(&gotBuf); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(want.Bytes(), gotBuf.Bytes()) {
+		t.Errorf("round-tripped Ops encodes to %x, want %x", gotBuf.Bytes(), want.Bytes())
+	}
+}
+
+func TestMarshalDeterministic(t *testing.T) {
+	var o Ops
+	Offset(image.Pt(5, 6)).Push(&o).Pop()
+
+	var buf1, buf2 bytes.Buffer
+	if err := Marshal(&buf1, &o); err != nil {
+		t.Fatal(err)
+	}
+	if err := Marshal(&buf2, &o); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(buf1.Bytes(), buf2.Bytes()) {
+		t.Errorf("two Marshal calls on the same Ops produced different output")
+	}
+}
+
+func TestUnmarshalInvalidMagic(t *testing.T) {
+	_, err := Unmarshal(strings.NewReader("not a valid header, definitely"))
+	if err == nil {
+		t.Fatal("Unmarshal accepted an invalid magic header")
+	}
+}
+
+func TestUnmarshalTruncated(t *testing.T) {
+	var o Ops
+	Offset(image.Pt(1, 1)).Push(&o).Pop()
+	var buf bytes.Buffer
+	if err := Marshal(&buf, &o); err != nil {
+		t.Fatal(err)
+	}
+	truncated := buf.Bytes()[:buf.Len()-1]
+	if _, err := Unmarshal(bytes.NewReader(truncated)); err == nil {
+		t.Fatal("Unmarshal accepted a truncated stream")
+	}
+}
@@ -66,11 +66,13 @@ import (
 	"encoding/binary"
 	"fmt"
 	"image"
+	"io"
 	"math"
 	"time"
 
 	"gioui.org/f32"
 	"gioui.org/internal/ops"
+	"gioui.org/internal/stream"
 )
 
 // Ops holds a list of operations. Operations are stored in
@@ -163,6 +165,19 @@ func (o *Ops) Reset() {
 	ops.Reset(&o.Internal)
 }
 
+// WriteTo writes the raw, encoded op stream to w and returns the number of
+// bytes written. It does not serialize the refs accompanying ops that carry
+// one, such as macro and call references, clip paths or decoration
+// parameters, so it is only useful for ops lists that don't depend on them,
+// or as a basis for a content hash keyed cache of the kind produced by a
+// stream.Writer with its Hash field set: the op list can be written and
+// hashed in a single pass without ever buffering it in memory.
+func (o *Ops) WriteTo(w io.Writer) (int64, error) {
+	sw := &stream.Writer{W: w}
+	sw.Write(ops.Data(&o.Internal))
+	return sw.Count(), sw.Err()
+}
+
 // Record a macro of operations.
 func Record(o *Ops) MacroOp {
 	m := MacroOp{
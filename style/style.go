@@ -0,0 +1,539 @@
+// SPDX-License-Identifier: Unlicense OR MIT
+
+/*
+Package style implements a small subset of CSS for declaratively
+restyling Gio widgets.
+
+An application compiles a style sheet with [Parse] and looks up the
+resolved [StyleSet] for a widget's class, id and interaction state
+with [Sheet.Resolve]. This lets a designer adjust an app's colors,
+spacing and cursors without touching Go code.
+
+The supported selectors are `.class`, `#id` and the pseudo-classes
+`:hover`, `:focus`, `:pressed` and `:disabled`, optionally combined
+(for example `.primary:hover`). The supported properties are color,
+background, padding, margin, border-radius, cursor, font-size and
+font-weight.
+*/
+package style
+
+import (
+	"fmt"
+	"image/color"
+	"strconv"
+	"strings"
+
+	"gioui.org/font"
+	"gioui.org/io/pointer"
+	"gioui.org/unit"
+)
+
+// State is a bit set of the interaction states a widget can be in,
+// mirroring the CSS pseudo-classes :hover, :focus, :pressed and
+// :disabled.
+type State uint8
+
+const (
+	// Hover corresponds to the :hover pseudo-class.
+	Hover State = 1 << iota
+	// Focus corresponds to the :focus pseudo-class.
+	Focus
+	// Pressed corresponds to the :pressed pseudo-class.
+	Pressed
+	// Disabled corresponds to the :disabled pseudo-class.
+	Disabled
+)
+
+// fields records which properties of a StyleSet were set by a rule, so
+// that resolving a cascade only overrides properties a later rule
+// actually specifies.
+type fields uint16
+
+const (
+	fieldColor fields = 1 << iota
+	fieldBackground
+	fieldPadding
+	fieldMargin
+	fieldBorderRadius
+	fieldCursor
+	fieldFontSize
+	fieldFontWeight
+)
+
+// StyleSet is the resolved set of properties for a widget in a given
+// State. A property that no rule set keeps its zero value.
+type StyleSet struct {
+	Color        color.NRGBA
+	Background   color.NRGBA
+	Padding      unit.Dp
+	Margin       unit.Dp
+	BorderRadius unit.Dp
+	Cursor       pointer.Cursor
+	FontSize     unit.Sp
+	FontWeight   font.Weight
+
+	set fields
+}
+
+// merge overlays the properties o has set onto s, leaving the rest of
+// s untouched.
+func (s StyleSet) merge(o StyleSet) StyleSet {
+	if o.set&fieldColor != 0 {
+		s.Color = o.Color
+	}
+	if o.set&fieldBackground != 0 {
+		s.Background = o.Background
+	}
+	if o.set&fieldPadding != 0 {
+		s.Padding = o.Padding
+	}
+	if o.set&fieldMargin != 0 {
+		s.Margin = o.Margin
+	}
+	if o.set&fieldBorderRadius != 0 {
+		s.BorderRadius = o.BorderRadius
+	}
+	if o.set&fieldCursor != 0 {
+		s.Cursor = o.Cursor
+	}
+	if o.set&fieldFontSize != 0 {
+		s.FontSize = o.FontSize
+	}
+	if o.set&fieldFontWeight != 0 {
+		s.FontWeight = o.FontWeight
+	}
+	s.set |= o.set
+	return s
+}
+
+// selector is a single compound selector such as ".primary:hover".
+type selector struct {
+	class string
+	id    string
+	state State
+}
+
+// specificity follows the usual CSS ordering: id beats class beats
+// pseudo-class, so that more specific rules later in the cascade can
+// still be overridden by an even more specific earlier rule.
+func (s selector) specificity() int {
+	n := 0
+	if s.id != "" {
+		n += 100
+	}
+	if s.class != "" {
+		n += 10
+	}
+	if s.state != 0 {
+		n++
+	}
+	return n
+}
+
+// matches reports whether the selector applies to a widget with the
+// given class, id and current interaction state. A selector matches
+// only if every pseudo-class it names is present in state.
+func (s selector) matches(class, id string, state State) bool {
+	if s.class != "" && s.class != class {
+		return false
+	}
+	if s.id != "" && s.id != id {
+		return false
+	}
+	return state&s.state == s.state
+}
+
+// rule is a style sheet rule: a set of selectors sharing a single
+// declaration block.
+type rule struct {
+	selectors []selector
+	props     StyleSet
+}
+
+// Sheet is a compiled style sheet, ready to be queried with Resolve.
+type Sheet struct {
+	rules []rule
+}
+
+// Resolve returns the StyleSet for a widget identified by class and
+// id, in the given interaction state. Matching rules are applied from
+// least to most specific, and rules of equal specificity in source
+// order, so a more specific rule overrides the properties it sets
+// wherever it appears, and among equals the later rule wins.
+func (s *Sheet) Resolve(class, id string, state State) StyleSet {
+	type match struct {
+		spec  int
+		props StyleSet
+	}
+	var matches []match
+	for _, r := range s.rules {
+		best := -1
+		for _, sel := range r.selectors {
+			if sel.matches(class, id, state) {
+				if sp := sel.specificity(); sp > best {
+					best = sp
+				}
+			}
+		}
+		if best >= 0 {
+			matches = append(matches, match{spec: best, props: r.props})
+		}
+	}
+	// Stable sort by ascending specificity so ties keep source order,
+	// and the final merge lets the most specific match win.
+	for i := 1; i < len(matches); i++ {
+		for j := i; j > 0 && matches[j].spec < matches[j-1].spec; j-- {
+			matches[j], matches[j-1] = matches[j-1], matches[j]
+		}
+	}
+	var out StyleSet
+	for _, m := range matches {
+		out = out.merge(m.props)
+	}
+	return out
+}
+
+// Parse compiles a style sheet from a subset of CSS. See the package
+// documentation for the supported selectors and properties.
+func Parse(css string) (*Sheet, error) {
+	css = stripComments(css)
+	sheet := new(Sheet)
+	for {
+		css = strings.TrimSpace(css)
+		if css == "" {
+			break
+		}
+		open := strings.IndexByte(css, '{')
+		if open < 0 {
+			return nil, fmt.Errorf("style: unterminated rule %q", css)
+		}
+		selText, rest := css[:open], css[open+1:]
+		close := strings.IndexByte(rest, '}')
+		if close < 0 {
+			return nil, fmt.Errorf("style: unterminated declaration block for %q", strings.TrimSpace(selText))
+		}
+		body := rest[:close]
+		css = rest[close+1:]
+
+		sels, err := parseSelectors(selText)
+		if err != nil {
+			return nil, err
+		}
+		props, err := parseDeclarations(body)
+		if err != nil {
+			return nil, err
+		}
+		sheet.rules = append(sheet.rules, rule{selectors: sels, props: props})
+	}
+	return sheet, nil
+}
+
+func stripComments(css string) string {
+	for {
+		start := strings.Index(css, "/*")
+		if start < 0 {
+			return css
+		}
+		end := strings.Index(css[start:], "*/")
+		if end < 0 {
+			return css[:start]
+		}
+		css = css[:start] + css[start+end+2:]
+	}
+}
+
+func parseSelectors(text string) ([]selector, error) {
+	var sels []selector
+	for _, tok := range strings.Split(text, ",") {
+		sel, err := parseSelector(strings.TrimSpace(tok))
+		if err != nil {
+			return nil, err
+		}
+		sels = append(sels, sel)
+	}
+	return sels, nil
+}
+
+func parseSelector(tok string) (selector, error) {
+	var sel selector
+	for len(tok) > 0 {
+		switch tok[0] {
+		case '.':
+			name, rest := splitIdent(tok[1:])
+			sel.class = name
+			tok = rest
+		case '#':
+			name, rest := splitIdent(tok[1:])
+			sel.id = name
+			tok = rest
+		case ':':
+			name, rest := splitIdent(tok[1:])
+			st, err := parsePseudo(name)
+			if err != nil {
+				return selector{}, err
+			}
+			sel.state |= st
+			tok = rest
+		default:
+			// A bare widget name, such as "widget" in "widget:hover",
+			// is accepted but otherwise ignored: this package has no
+			// notion of widget tag names, only class, id and state.
+			_, rest := splitIdent(tok)
+			if len(rest) == len(tok) {
+				return selector{}, fmt.Errorf("style: invalid selector %q", tok)
+			}
+			tok = rest
+		}
+	}
+	return sel, nil
+}
+
+func splitIdent(s string) (ident, rest string) {
+	i := 0
+	for i < len(s) && isIdentByte(s[i]) {
+		i++
+	}
+	return s[:i], s[i:]
+}
+
+func isIdentByte(b byte) bool {
+	return b == '-' || b == '_' ||
+		'a' <= b && b <= 'z' ||
+		'A' <= b && b <= 'Z' ||
+		'0' <= b && b <= '9'
+}
+
+func parsePseudo(name string) (State, error) {
+	switch name {
+	case "hover":
+		return Hover, nil
+	case "focus":
+		return Focus, nil
+	case "pressed":
+		return Pressed, nil
+	case "disabled":
+		return Disabled, nil
+	default:
+		return 0, fmt.Errorf("style: unknown pseudo-class %q", name)
+	}
+}
+
+func parseDeclarations(body string) (StyleSet, error) {
+	var out StyleSet
+	for _, decl := range strings.Split(body, ";") {
+		decl = strings.TrimSpace(decl)
+		if decl == "" {
+			continue
+		}
+		name, value, ok := strings.Cut(decl, ":")
+		if !ok {
+			return StyleSet{}, fmt.Errorf("style: malformed declaration %q", decl)
+		}
+		if err := out.setProperty(strings.TrimSpace(name), strings.TrimSpace(value)); err != nil {
+			return StyleSet{}, err
+		}
+	}
+	return out, nil
+}
+
+func (s *StyleSet) setProperty(name, value string) error {
+	switch name {
+	case "color":
+		c, err := parseColor(value)
+		if err != nil {
+			return err
+		}
+		s.Color, s.set = c, s.set|fieldColor
+	case "background":
+		c, err := parseColor(value)
+		if err != nil {
+			return err
+		}
+		s.Background, s.set = c, s.set|fieldBackground
+	case "padding":
+		d, err := parseDp(value)
+		if err != nil {
+			return err
+		}
+		s.Padding, s.set = d, s.set|fieldPadding
+	case "margin":
+		d, err := parseDp(value)
+		if err != nil {
+			return err
+		}
+		s.Margin, s.set = d, s.set|fieldMargin
+	case "border-radius":
+		d, err := parseDp(value)
+		if err != nil {
+			return err
+		}
+		s.BorderRadius, s.set = d, s.set|fieldBorderRadius
+	case "cursor":
+		c, err := parseCursor(value)
+		if err != nil {
+			return err
+		}
+		s.Cursor, s.set = c, s.set|fieldCursor
+	case "font-size":
+		sp, err := parseSp(value)
+		if err != nil {
+			return err
+		}
+		s.FontSize, s.set = sp, s.set|fieldFontSize
+	case "font-weight":
+		w, err := parseWeight(value)
+		if err != nil {
+			return err
+		}
+		s.FontWeight, s.set = w, s.set|fieldFontWeight
+	default:
+		return fmt.Errorf("style: unknown property %q", name)
+	}
+	return nil
+}
+
+func parseColor(value string) (color.NRGBA, error) {
+	if !strings.HasPrefix(value, "#") {
+		return color.NRGBA{}, fmt.Errorf("style: unsupported color %q, want a #rrggbb[aa] hex value", value)
+	}
+	hex := value[1:]
+	expand := func(s string) string {
+		b := make([]byte, 0, 2*len(s))
+		for _, c := range s {
+			b = append(b, byte(c), byte(c))
+		}
+		return string(b)
+	}
+	switch len(hex) {
+	case 3:
+		hex = expand(hex) + "ff"
+	case 4:
+		hex = expand(hex)
+	case 6:
+		hex += "ff"
+	case 8:
+	default:
+		return color.NRGBA{}, fmt.Errorf("style: invalid color %q", value)
+	}
+	n, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return color.NRGBA{}, fmt.Errorf("style: invalid color %q: %w", value, err)
+	}
+	return color.NRGBA{
+		R: byte(n >> 24),
+		G: byte(n >> 16),
+		B: byte(n >> 8),
+		A: byte(n),
+	}, nil
+}
+
+func parseNumber(value string) (float64, error) {
+	value = strings.TrimSuffix(value, "px")
+	value = strings.TrimSuffix(value, "dp")
+	value = strings.TrimSuffix(value, "sp")
+	f, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+	if err != nil {
+		return 0, fmt.Errorf("style: invalid numeric value %q", value)
+	}
+	return f, nil
+}
+
+func parseDp(value string) (unit.Dp, error) {
+	f, err := parseNumber(value)
+	if err != nil {
+		return 0, err
+	}
+	return unit.Dp(f), nil
+}
+
+func parseSp(value string) (unit.Sp, error) {
+	f, err := parseNumber(value)
+	if err != nil {
+		return 0, err
+	}
+	return unit.Sp(f), nil
+}
+
+func parseWeight(value string) (font.Weight, error) {
+	switch value {
+	case "thin":
+		return font.Thin, nil
+	case "extra-light":
+		return font.ExtraLight, nil
+	case "light":
+		return font.Light, nil
+	case "normal":
+		return font.Normal, nil
+	case "medium":
+		return font.Medium, nil
+	case "semi-bold":
+		return font.SemiBold, nil
+	case "bold":
+		return font.Bold, nil
+	case "extra-bold":
+		return font.ExtraBold, nil
+	case "black":
+		return font.Black, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("style: invalid font-weight %q", value)
+	}
+	// CSS font-weight is 100-900 with 400 as normal; font.Weight is
+	// the same scale shifted so 0 is normal.
+	return font.Weight(n - 400), nil
+}
+
+func parseCursor(value string) (pointer.Cursor, error) {
+	switch value {
+	case "default":
+		return pointer.CursorDefault, nil
+	case "none":
+		return pointer.CursorNone, nil
+	case "text":
+		return pointer.CursorText, nil
+	case "vertical-text":
+		return pointer.CursorVerticalText, nil
+	case "pointer":
+		return pointer.CursorPointer, nil
+	case "crosshair":
+		return pointer.CursorCrosshair, nil
+	case "all-scroll":
+		return pointer.CursorAllScroll, nil
+	case "col-resize":
+		return pointer.CursorColResize, nil
+	case "row-resize":
+		return pointer.CursorRowResize, nil
+	case "grab":
+		return pointer.CursorGrab, nil
+	case "grabbing":
+		return pointer.CursorGrabbing, nil
+	case "not-allowed":
+		return pointer.CursorNotAllowed, nil
+	case "wait":
+		return pointer.CursorWait, nil
+	case "progress":
+		return pointer.CursorProgress, nil
+	default:
+		return 0, fmt.Errorf("style: unknown cursor %q", value)
+	}
+}
+
+// StateFor derives the State of a clickable widget from its hover,
+// press and focus status, for use with Sheet.Resolve.
+func StateFor(hovered, pressed, focused, disabled bool) State {
+	var s State
+	if hovered {
+		s |= Hover
+	}
+	if pressed {
+		s |= Pressed
+	}
+	if focused {
+		s |= Focus
+	}
+	if disabled {
+		s |= Disabled
+	}
+	return s
+}
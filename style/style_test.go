@@ -0,0 +1,93 @@
+// SPDX-License-Identifier: Unlicense OR MIT
+
+package style
+
+import (
+	"image/color"
+	"testing"
+
+	"gioui.org/io/pointer"
+	"gioui.org/unit"
+)
+
+func TestResolveCascade(t *testing.T) {
+	sheet, err := Parse(`
+		.button {
+			color: #112233;
+			padding: 8px;
+			cursor: pointer;
+		}
+		.button:hover {
+			background: #ff0000;
+		}
+		#submit {
+			color: #000000ff;
+		}
+	`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	base := sheet.Resolve("button", "", 0)
+	if got, want := base.Color, (color.NRGBA{R: 0x11, G: 0x22, B: 0x33, A: 0xff}); got != want {
+		t.Errorf("got color %v, want %v", got, want)
+	}
+	if got, want := base.Padding, unit.Dp(8); got != want {
+		t.Errorf("got padding %v, want %v", got, want)
+	}
+	if got, want := base.Cursor, pointer.CursorPointer; got != want {
+		t.Errorf("got cursor %v, want %v", got, want)
+	}
+	if got, want := base.Background, (color.NRGBA{}); got != want {
+		t.Errorf("got background %v, want %v", got, want)
+	}
+
+	hovered := sheet.Resolve("button", "", Hover)
+	if got, want := hovered.Background, (color.NRGBA{R: 0xff, A: 0xff}); got != want {
+		t.Errorf("got background %v, want %v", got, want)
+	}
+	// The hover rule only sets background; color should still cascade
+	// down from the base rule.
+	if got, want := hovered.Color, base.Color; got != want {
+		t.Errorf("got color %v, want %v", got, want)
+	}
+
+	// A more specific #id selector overrides the .class rule's color.
+	submit := sheet.Resolve("button", "submit", 0)
+	if got, want := submit.Color, (color.NRGBA{A: 0xff}); got != want {
+		t.Errorf("got color %v, want %v", got, want)
+	}
+}
+
+func TestResolveUnmatchedPseudo(t *testing.T) {
+	sheet, err := Parse(`.danger:disabled { color: #ff0000; }`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := sheet.Resolve("danger", "", 0).Color, (color.NRGBA{}); got != want {
+		t.Errorf("got color %v, want zero value when not disabled", got)
+	}
+	if got, want := sheet.Resolve("danger", "", Disabled).Color, (color.NRGBA{R: 0xff, A: 0xff}); got != want {
+		t.Errorf("got color %v, want %v", got, want)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, css := range []string{
+		`.button { color: red; }`,
+		`.button { unknown: 1px; }`,
+		`.button:not-a-pseudo { color: #fff; }`,
+		`.button { color: #fff`,
+		`.button`,
+	} {
+		if _, err := Parse(css); err == nil {
+			t.Errorf("Parse(%q): expected error", css)
+		}
+	}
+}
+
+func TestStateFor(t *testing.T) {
+	if got, want := StateFor(true, false, true, false), Hover|Focus; got != want {
+		t.Errorf("got state %v, want %v", got, want)
+	}
+}
@@ -6,10 +6,12 @@ import (
 	"bytes"
 	"image"
 	"io"
+	"math"
 	"sort"
 
 	"github.com/go-text/typesetting/di"
 	"github.com/go-text/typesetting/font"
+	ot "github.com/go-text/typesetting/font/opentype"
 	"github.com/go-text/typesetting/language"
 	"github.com/go-text/typesetting/opentype/api"
 	"github.com/go-text/typesetting/shaping"
@@ -157,9 +159,15 @@ type faceOrderer struct {
 	faces               map[Font]font.Face
 	faceToIndex         map[font.Face]int
 	fonts               []Font
+	// faceInfo caches the FaceInfo computed for each font at insertion time,
+	// so repeated calls to Shaper.Faces don't re-read the name table.
+	faceInfo map[Font]FaceInfo
+	// shapers holds the non-default shaping engines registered for each
+	// font via FontFace.Shapers.
+	shapers map[Font][]shaping.Shaper
 }
 
-func (f *faceOrderer) insert(fnt Font, face font.Face) {
+func (f *faceOrderer) insert(fnt Font, face font.Face, shapers []shaping.Shaper) {
 	if len(f.fonts) == 0 {
 		f.def = fnt
 	}
@@ -169,6 +177,7 @@ func (f *faceOrderer) insert(fnt Font, face font.Face) {
 	if f.faces == nil {
 		f.faces = make(map[Font]font.Face)
 		f.faceToIndex = make(map[font.Face]int)
+		f.faceInfo = make(map[Font]FaceInfo)
 	}
 	f.fontDefaultOrder[fnt] = len(f.faceScratch)
 	f.defaultOrderedFonts = append(f.defaultOrderedFonts, fnt)
@@ -176,6 +185,37 @@ func (f *faceOrderer) insert(fnt Font, face font.Face) {
 	f.fonts = append(f.fonts, fnt)
 	f.faces[fnt] = face
 	f.faceToIndex[face] = f.fontDefaultOrder[fnt]
+	f.faceInfo[fnt] = FaceInfo{
+		Font:   fnt,
+		Family: face.Describe().Family,
+	}
+	if len(shapers) > 0 {
+		if f.shapers == nil {
+			f.shapers = make(map[Font][]shaping.Shaper)
+		}
+		f.shapers[fnt] = shapers
+	}
+}
+
+// shapersFor returns the non-default shaping engines registered for face,
+// in the order they should be tried, or nil if face was registered without
+// any.
+func (f *faceOrderer) shapersFor(face font.Face) []shaping.Shaper {
+	idx, ok := f.faceToIndex[face]
+	if !ok {
+		return nil
+	}
+	return f.shapers[f.defaultOrderedFonts[idx]]
+}
+
+// faceInfos returns the cached FaceInfo for every registered font, in
+// registration order.
+func (f *faceOrderer) faceInfos() []FaceInfo {
+	infos := make([]FaceInfo, len(f.defaultOrderedFonts))
+	for i, fnt := range f.defaultOrderedFonts {
+		infos[i] = f.faceInfo[fnt]
+	}
+	return infos
 }
 
 // resetFontOrder restores the fonts to a predictable order. It should be invoked
@@ -198,7 +238,11 @@ func (c *faceOrderer) faceFor(idx int) font.Face {
 // TODO(whereswaldon): this function could sort all faces by appropriateness for the
 // given font characteristics. This would ensure that (if possible) text using a
 // fallback font would select similar weights and emphases to the primary font.
-func (c *faceOrderer) sortedFacesForStyle(font Font) []font.Face {
+//
+// The returned syntheticStyle flags the style features of font that the
+// returned primary face (faces[0]) cannot provide natively, so that the
+// caller can fake them when shaping and painting.
+func (c *faceOrderer) sortedFacesForStyle(font Font) ([]font.Face, syntheticStyle) {
 	c.resetFontOrder()
 	primary, ok := c.fontForStyle(font)
 	if !ok {
@@ -208,7 +252,22 @@ func (c *faceOrderer) sortedFacesForStyle(font Font) []font.Face {
 			primary = c.def
 		}
 	}
-	return c.sorted(primary)
+	return c.sorted(primary), synthesisFor(font, primary)
+}
+
+// synthesisFor reports which style features of requested the matched face
+// cannot provide, and so must be faked by the shaper. Only the weight and
+// style axes are considered: a fallback to a different typeface is treated
+// as a deliberate substitution, not something to synthesize on top of.
+func synthesisFor(requested, matched Font) syntheticStyle {
+	var synth syntheticStyle
+	if requested.Style == Italic && matched.Style != Italic {
+		synth |= syntheticOblique
+	}
+	if requested.Weight >= Bold && matched.Weight < requested.Weight {
+		synth |= syntheticBold
+	}
+	return synth
 }
 
 // fontForStyle returns the closest existing font to the requested font within the
@@ -256,6 +315,8 @@ type shaperImpl struct {
 	orderer faceOrderer
 
 	// Shaping and wrapping state.
+	// shaper is the default OpenType shaping engine, used for any face
+	// registered without its own FontFace.Shapers. See shaperFor.
 	shaper        shaping.HarfbuzzShaper
 	wrapper       shaping.LineWrapper
 	bidiParagraph bidi.Paragraph
@@ -274,7 +335,23 @@ type shaperImpl struct {
 // It returns whether the face is now available for use. FontFaces are prioritized
 // in the order in which they are loaded, with the first face being the default.
 func (s *shaperImpl) Load(f FontFace) {
-	s.orderer.insert(f.Font, f.Face.Face())
+	s.orderer.insert(f.Font, f.Face.Face(), f.Shapers)
+}
+
+// shaperFor returns the shaping engine that should shape a run assigned to
+// face: the first of its registered FontFace.Shapers, or the default
+// OpenType engine if it was registered without any or isn't recognized.
+//
+// Engines are only ever selected by explicit registration, never by probing
+// face for Graphite tables such as Silf: the vendored go-text/typesetting
+// font package only exposes the OpenType tables it parses itself (GSUB,
+// GPOS, and the like) and has no accessor for arbitrary table presence, so
+// there is nothing in the public API to probe with.
+func (s *shaperImpl) shaperFor(face font.Face) shaping.Shaper {
+	if shapers := s.orderer.shapersFor(face); len(shapers) > 0 {
+		return shapers[0]
+	}
+	return &s.shaper
 }
 
 // splitByScript divides the inputs into new, smaller inputs on script boundaries
@@ -373,29 +450,108 @@ func (s *shaperImpl) splitByFaces(inputs []shaping.Input, faces []font.Face, buf
 	return split
 }
 
+// splitByFeatures further divides the inputs at the boundaries of any
+// range-restricted feature, so that each resulting input's FontFeatures
+// accurately reflects the features in effect across its whole extent. It
+// is a no-op unless features contains at least one range-restricted entry.
+func splitByFeatures(inputs []shaping.Input, features []FontFeature) []shaping.Input {
+	if !featuresHaveRanges(features) {
+		return inputs
+	}
+	split := make([]shaping.Input, 0, len(inputs))
+	for _, input := range inputs {
+		split = append(split, splitInputByFeatures(input, features)...)
+	}
+	return split
+}
+
+func featuresHaveRanges(features []FontFeature) bool {
+	for _, f := range features {
+		if f.Start != 0 || f.End != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func splitInputByFeatures(input shaping.Input, features []FontFeature) []shaping.Input {
+	breaks := featureBreakpoints(input.RunStart, input.RunEnd, features)
+	if len(breaks) == 0 {
+		input.FontFeatures = toShapingFeatures(featuresActiveIn(features, input.RunStart, input.RunEnd))
+		return []shaping.Input{input}
+	}
+	out := make([]shaping.Input, 0, len(breaks)+1)
+	start := input.RunStart
+	for _, b := range append(breaks, input.RunEnd) {
+		piece := input
+		piece.RunStart = start
+		piece.RunEnd = b
+		piece.FontFeatures = toShapingFeatures(featuresActiveIn(features, start, b))
+		out = append(out, piece)
+		start = b
+	}
+	return out
+}
+
+// featureBreakpoints returns the sorted, de-duplicated rune offsets strictly
+// inside (start, end) at which a range-restricted feature begins or ends.
+func featureBreakpoints(start, end int, features []FontFeature) []int {
+	seen := map[int]bool{}
+	var breaks []int
+	add := func(at int) {
+		if at > start && at < end && !seen[at] {
+			seen[at] = true
+			breaks = append(breaks, at)
+		}
+	}
+	for _, f := range features {
+		if f.Start == f.End {
+			continue
+		}
+		add(f.Start)
+		add(f.End)
+	}
+	sort.Ints(breaks)
+	return breaks
+}
+
+// featuresActiveIn returns the features that apply across the whole
+// [start, end) rune range: global features (Start == End == 0) and
+// range-restricted features that cover the range entirely.
+func featuresActiveIn(features []FontFeature, start, end int) []FontFeature {
+	var active []FontFeature
+	for _, f := range features {
+		if (f.Start == 0 && f.End == 0) || (f.Start < f.End && f.Start <= start && f.End >= end) {
+			active = append(active, f)
+		}
+	}
+	return active
+}
+
 // shapeText invokes the text shaper and returns the raw text data in the shaper's native
 // format. It does not wrap lines.
-func (s *shaperImpl) shapeText(faces []font.Face, ppem fixed.Int26_6, lc system.Locale, txt []rune) []shaping.Output {
+func (s *shaperImpl) shapeText(faces []font.Face, ppem fixed.Int26_6, lc system.Locale, mode WritingMode, txt []rune, features []FontFeature) []shaping.Output {
 	if len(faces) < 1 {
 		return nil
 	}
 	lcfg := langConfig{
 		Language:  language.NewLanguage(lc.Language),
-		Direction: mapDirection(lc.Direction),
+		Direction: mapDirection(paragraphDirection(lc, mode)),
 	}
 	// Create an initial input.
-	input := toInput(faces[0], ppem, lcfg, txt)
+	input := toInput(faces[0], ppem, lcfg, txt, features)
 	// Break input on font glyph coverage.
 	inputs := s.splitBidi(input)
 	inputs = s.splitByFaces(inputs, faces, s.splitScratch1[:0])
 	inputs = splitByScript(inputs, lcfg.Direction, s.splitScratch2[:0])
+	inputs = splitByFeatures(inputs, features)
 	// Shape all inputs.
 	if needed := len(inputs) - len(s.outScratchBuf); needed > 0 {
 		s.outScratchBuf = slices.Grow(s.outScratchBuf, needed)
 	}
 	s.outScratchBuf = s.outScratchBuf[:len(inputs)]
 	for i := range inputs {
-		s.outScratchBuf[i] = s.shaper.Shape(inputs[i])
+		s.outScratchBuf[i] = s.shaperFor(inputs[i].Face).Shape(inputs[i])
 	}
 	return s.outScratchBuf
 }
@@ -412,10 +568,11 @@ func (s *shaperImpl) shapeAndWrapText(faces []font.Face, params Parameters, txt
 		}
 		// We only permit a single run as the truncator, regardless of whether more were generated.
 		// Just use the first one.
-		wc.Truncator = s.shapeText(faces, params.PxPerEm, params.Locale, []rune(params.Truncator))[0]
+		wc.Truncator = s.shapeText(faces, params.PxPerEm, params.Locale, params.WritingMode, []rune(params.Truncator), params.Features)[0]
 	}
-	// Wrap outputs into lines.
-	return s.wrapper.WrapParagraph(wc, params.MaxWidth, txt, s.shapeText(faces, params.PxPerEm, params.Locale, txt)...)
+	// Wrap outputs into lines. params.MaxWidth bounds the column height rather than the
+	// line width when params.WritingMode is Vertical.
+	return s.wrapper.WrapParagraph(wc, params.MaxWidth, txt, s.shapeText(faces, params.PxPerEm, params.Locale, params.WritingMode, txt, params.Features)...)
 }
 
 // replaceControlCharacters replaces problematic unicode
@@ -474,7 +631,11 @@ func (s *shaperImpl) LayoutRunes(params Parameters, txt []rune) document {
 	if hasNewline {
 		txt = txt[:len(txt)-1]
 	}
-	ls, truncated := s.shapeAndWrapText(s.orderer.sortedFacesForStyle(params.Font), params, replaceControlCharacters(txt))
+	faces, synth := s.orderer.sortedFacesForStyle(params.Font)
+	if params.DisableSynthesis {
+		synth = 0
+	}
+	ls, truncated := s.shapeAndWrapText(faces, params, replaceControlCharacters(txt))
 
 	didTruncate := truncated > 0 || (params.forceTruncate && params.MaxLines == len(ls))
 
@@ -485,9 +646,10 @@ func (s *shaperImpl) LayoutRunes(params Parameters, txt []rune) document {
 		hasNewline = false
 	}
 	// Convert to Lines.
+	dir := paragraphDirection(params.Locale, params.WritingMode)
 	textLines := make([]line, len(ls))
 	for i := range ls {
-		otLine := toLine(&s.orderer, ls[i], params.Locale.Direction)
+		otLine := toLine(&s.orderer, ls[i], dir, faces[0], synth)
 		isFinalLine := i == len(ls)-1
 		if isFinalLine && hasNewline {
 			// If there was a trailing newline update the rune counts to include
@@ -550,6 +712,17 @@ func alignWidth(minWidth int, lines []line) int {
 	return minWidth
 }
 
+const (
+	// syntheticObliqueShear is the slope applied to synthesized italics,
+	// roughly a 12 degree shear of the upright outline.
+	syntheticObliqueShear = 0.21
+	// syntheticBoldStrength is the fraction of the em size by which a second,
+	// nudged copy of each glyph outline is offset to fake a heavier weight
+	// than the face provides. The non-zero winding rule merges the two
+	// copies into a single, slightly thickened shape.
+	syntheticBoldStrength = 0.02
+)
+
 // Shape converts the provided glyphs into a path. The path will enclose the forms
 // of all vector glyphs.
 func (s *shaperImpl) Shape(pathOps *op.Ops, gs []Glyph) clip.PathSpec {
@@ -561,65 +734,109 @@ func (s *shaperImpl) Shape(pathOps *op.Ops, gs []Glyph) clip.PathSpec {
 		if i == 0 {
 			x = g.X
 		}
-		ppem, faceIdx, gid := splitGlyphID(g.ID)
+		ppem, faceIdx, gid, synth := splitGlyphID(g.ID)
 		face := s.orderer.faceFor(faceIdx)
 		scaleFactor := fixedToFloat(ppem) / float32(face.Upem())
 		glyphData := face.GlyphData(gid)
 		switch glyphData := glyphData.(type) {
 		case api.GlyphOutline:
-			outline := glyphData
-			// Move to glyph position.
-			pos := f32.Point{
-				X: fixedToFloat((g.X - x) - g.Offset.X),
-				Y: -fixedToFloat(g.Offset.Y),
+			var shear float32
+			if synth&syntheticOblique != 0 {
+				shear = syntheticObliqueShear
 			}
-			builder.Move(pos.Sub(lastPos))
-			lastPos = pos
-			var lastArg f32.Point
-
-			// Convert fonts.Segments to relative segments.
-			for _, fseg := range outline.Segments {
-				nargs := 1
-				switch fseg.Op {
-				case api.SegmentOpQuadTo:
-					nargs = 2
-				case api.SegmentOpCubeTo:
-					nargs = 3
-				}
-				var args [3]f32.Point
-				for i := 0; i < nargs; i++ {
-					a := f32.Point{
-						X: fseg.Args[i].X * scaleFactor,
-						Y: -fseg.Args[i].Y * scaleFactor,
-					}
-					args[i] = a.Sub(lastArg)
-					if i == nargs-1 {
-						lastArg = a
-					}
-				}
-				switch fseg.Op {
-				case api.SegmentOpMoveTo:
-					builder.Move(args[0])
-				case api.SegmentOpLineTo:
-					builder.Line(args[0])
-				case api.SegmentOpQuadTo:
-					builder.Quad(args[0], args[1])
-				case api.SegmentOpCubeTo:
-					builder.Cube(args[0], args[1], args[2])
-				default:
-					panic("unsupported segment op")
-				}
+			lastPos = drawGlyphOutline(&builder, glyphData, g, x, lastPos, scaleFactor, shear, f32.Point{})
+			if synth&syntheticBold != 0 {
+				nudge := f32.Point{X: fixedToFloat(ppem) * syntheticBoldStrength}
+				lastPos = drawGlyphOutline(&builder, glyphData, g, x, lastPos, scaleFactor, shear, nudge)
 			}
-			lastPos = lastPos.Add(lastArg)
 		}
 	}
 	return builder.End()
 }
 
+// drawGlyphOutline appends the path segments of outline to builder, placed
+// for glyph g whose containing run starts at lineStart and scaled by
+// scaleFactor. shear slants every point horizontally in proportion to its
+// height above the baseline, faking an italic style. extra displaces the
+// whole outline by a constant amount, which Shape uses to draw a second,
+// nudged copy of a glyph to fake a bolder weight. It returns the document
+// position of the outline's final point, for use as the next glyph's
+// lastPos.
+func drawGlyphOutline(builder *clip.Path, outline api.GlyphOutline, g Glyph, lineStart fixed.Int26_6, lastPos f32.Point, scaleFactor, shear float32, extra f32.Point) f32.Point {
+	// Move to glyph position.
+	pos := f32.Point{
+		X: fixedToFloat((g.X-lineStart)-g.Offset.X) + extra.X,
+		Y: -fixedToFloat(g.Offset.Y) + extra.Y,
+	}
+	builder.Move(pos.Sub(lastPos))
+	lastPos = pos
+	var lastArg f32.Point
+
+	// Convert fonts.Segments to relative segments.
+	for _, fseg := range outline.Segments {
+		nargs := 1
+		switch fseg.Op {
+		case api.SegmentOpQuadTo:
+			nargs = 2
+		case api.SegmentOpCubeTo:
+			nargs = 3
+		}
+		var args [3]f32.Point
+		for i := 0; i < nargs; i++ {
+			a := f32.Point{
+				X: fseg.Args[i].X * scaleFactor,
+				Y: -fseg.Args[i].Y * scaleFactor,
+			}
+			if shear != 0 {
+				a.X -= shear * a.Y
+			}
+			args[i] = a.Sub(lastArg)
+			if i == nargs-1 {
+				lastArg = a
+			}
+		}
+		switch fseg.Op {
+		case api.SegmentOpMoveTo:
+			builder.Move(args[0])
+		case api.SegmentOpLineTo:
+			builder.Line(args[0])
+		case api.SegmentOpQuadTo:
+			builder.Quad(args[0], args[1])
+		case api.SegmentOpCubeTo:
+			builder.Cube(args[0], args[1], args[2])
+		default:
+			panic("unsupported segment op")
+		}
+	}
+	return lastPos.Add(lastArg)
+}
+
 func fixedToFloat(i fixed.Int26_6) float32 {
 	return float32(i) / 64.0
 }
 
+func floatToFixed(v float32) fixed.Int26_6 {
+	return fixed.Int26_6(math.Round(float64(v) * 64))
+}
+
+// Metrics returns the cap-height and x-height of the face matching f, scaled
+// to ppem. Both are zero if the face has no OS/2 table entry for the metric.
+func (s *shaperImpl) Metrics(f Font, ppem fixed.Int26_6) LineMetrics {
+	faces, _ := s.orderer.sortedFacesForStyle(f)
+	face := faces[0]
+	scaleFactor := fixedToFloat(ppem) / float32(face.Upem())
+	return LineMetrics{
+		CapHeight: floatToFixed(face.LineMetric(font.CapHeight) * scaleFactor),
+		XHeight:   floatToFixed(face.LineMetric(font.XHeight) * scaleFactor),
+	}
+}
+
+// Faces returns metadata describing every face registered with the shaper,
+// in registration order.
+func (s *shaperImpl) Faces() []FaceInfo {
+	return s.orderer.faceInfos()
+}
+
 // Bitmaps returns an op.CallOp that will display all bitmap glyphs within gs.
 // The positioning of the bitmaps uses the same logic as Shape(), so the returned
 // CallOp can be added at the same offset as the path data returned by Shape()
@@ -631,7 +848,7 @@ func (s *shaperImpl) Bitmaps(ops *op.Ops, gs []Glyph) op.CallOp {
 		if i == 0 {
 			x = g.X
 		}
-		_, faceIdx, gid := splitGlyphID(g.ID)
+		_, faceIdx, gid, _ := splitGlyphID(g.ID)
 		face := s.orderer.faceFor(faceIdx)
 		glyphData := face.GlyphData(gid)
 		switch glyphData := glyphData.(type) {
@@ -689,6 +906,54 @@ func (s *shaperImpl) Bitmaps(ops *op.Ops, gs []Glyph) op.CallOp {
 	return bitmapMacro.Stop()
 }
 
+// Layers returns an op.CallOp that will display the color glyph layers within
+// gs. The positioning of the layers uses the same logic as Shape(), so the
+// returned CallOp can be added at the same offset as the path data returned
+// by Shape() and will align correctly.
+//
+// Color glyph support only covers the flat, COLRv0-style layer list: each
+// layer is a single solid-colored outline, composited in the order the font
+// provides. Gradients, transforms and the rest of the COLRv1 paint graph are
+// not represented, so a glyph that relies on them contributes no layers here.
+//
+// Layers are resolved from each Glyph's existing GlyphID rather than being
+// expanded into synthetic per-layer glyphs: clusterIndex, glyphCount and the
+// rest of the rune-accounting fields that selection and editing rely on are
+// only meaningful one-per-shaped-glyph, and fanning a color glyph out into
+// several Glyphs would desynchronize them from the source text.
+func (s *shaperImpl) Layers(ops *op.Ops, gs []Glyph) op.CallOp {
+	var x fixed.Int26_6
+	layersMacro := op.Record(ops)
+	var builder clip.Path
+	for i, g := range gs {
+		if i == 0 {
+			x = g.X
+		}
+		ppem, faceIdx, gid, _ := splitGlyphID(g.ID)
+		face := s.orderer.faceFor(faceIdx)
+		scaleFactor := fixedToFloat(ppem) / float32(face.Upem())
+		glyphData := face.GlyphData(gid)
+		colorGlyph, ok := glyphData.(api.GlyphColor)
+		if !ok {
+			continue
+		}
+		for _, cl := range colorGlyph.Layers {
+			outline, ok := face.GlyphData(cl.GlyphID).(api.GlyphOutline)
+			if !ok {
+				continue
+			}
+			c, ok := face.PaletteColor(cl.PaletteIndex)
+			if !ok {
+				continue
+			}
+			builder.Begin(ops)
+			drawGlyphOutline(&builder, outline, g, x, f32.Point{}, scaleFactor, 0, f32.Point{})
+			paint.FillShape(ops, c, clip.Outline{Path: builder.End()}.Op())
+		}
+	}
+	return layersMacro.Stop()
+}
+
 // langConfig describes the language and writing system of a body of text.
 type langConfig struct {
 	// Language the text is written in.
@@ -700,7 +965,7 @@ type langConfig struct {
 }
 
 // toInput converts its parameters into a shaping.Input.
-func toInput(face font.Face, ppem fixed.Int26_6, lc langConfig, runes []rune) shaping.Input {
+func toInput(face font.Face, ppem fixed.Int26_6, lc langConfig, runes []rune, features []FontFeature) shaping.Input {
 	var input shaping.Input
 	input.Direction = lc.Direction
 	input.Text = runes
@@ -710,15 +975,58 @@ func toInput(face font.Face, ppem fixed.Int26_6, lc langConfig, runes []rune) sh
 	input.Script = lc.Script
 	input.RunStart = 0
 	input.RunEnd = len(runes)
+	input.FontFeatures = toShapingFeatures(features)
 	return input
 }
 
+// toShapingFeatures converts FontFeature values into the form expected by
+// the underlying shaper. Tags shorter than 4 bytes are padded with spaces
+// and longer ones truncated, following the usual OpenType tag convention.
+func toShapingFeatures(features []FontFeature) []shaping.FontFeature {
+	if len(features) == 0 {
+		return nil
+	}
+	out := make([]shaping.FontFeature, len(features))
+	for i, f := range features {
+		out[i] = shaping.FontFeature{Tag: featureTag(f.Tag), Value: f.Value}
+	}
+	return out
+}
+
+func featureTag(tag string) ot.Tag {
+	var b [4]byte
+	copy(b[:], tag)
+	for i := len(tag); i < len(b); i++ {
+		b[i] = ' '
+	}
+	return ot.NewTag(b[0], b[1], b[2], b[3])
+}
+
+// FeatureTags enumerates the GSUB and GPOS feature tags available in the
+// face selected by font, for example to populate a font-feature picker UI.
+func (s *shaperImpl) FeatureTags(font Font) []string {
+	faces, _ := s.orderer.sortedFacesForStyle(font)
+	face := faces[0]
+	tags := make([]string, 0, len(face.GSUB.Features)+len(face.GPOS.Features))
+	for _, f := range face.GSUB.Features {
+		tags = append(tags, f.Tag.String())
+	}
+	for _, f := range face.GPOS.Features {
+		tags = append(tags, f.Tag.String())
+	}
+	return tags
+}
+
 func mapDirection(d system.TextDirection) di.Direction {
 	switch d {
 	case system.LTR:
 		return di.DirectionLTR
 	case system.RTL:
 		return di.DirectionRTL
+	case system.TTB:
+		return di.DirectionTTB
+	case system.BTT:
+		return di.DirectionBTT
 	}
 	return di.DirectionLTR
 }
@@ -729,13 +1037,35 @@ func unmapDirection(d di.Direction) system.TextDirection {
 		return system.LTR
 	case di.DirectionRTL:
 		return system.RTL
+	case di.DirectionTTB:
+		return system.TTB
+	case di.DirectionBTT:
+		return system.BTT
 	}
 	return system.LTR
 }
 
+// paragraphDirection returns the direction that should be handed to the
+// shaper for a paragraph: the vertical axis selected by mode if the writing
+// mode calls for it, otherwise the locale's own (horizontal) direction.
+// Vertical Japanese and Chinese are conventionally top-to-bottom; BTT is
+// used when the locale's own direction progresses toward the origin, as a
+// right-to-left locale's does.
+func paragraphDirection(lc system.Locale, mode WritingMode) system.TextDirection {
+	if mode != Vertical {
+		return lc.Direction
+	}
+	if lc.Direction.Progression() == system.TowardOrigin {
+		return system.BTT
+	}
+	return system.TTB
+}
+
 // toGioGlyphs converts text shaper glyphs into the minimal representation
-// that Gio needs.
-func toGioGlyphs(in []shaping.Glyph, ppem fixed.Int26_6, faceIdx int) []glyph {
+// that Gio needs. synth is stamped onto every glyph's ID so that Shaper.Shape
+// knows to fake the style features it describes, and each glyph's bounds are
+// widened to cover the faked outline.
+func toGioGlyphs(in []shaping.Glyph, ppem fixed.Int26_6, faceIdx int, synth syntheticStyle) []glyph {
 	out := make([]glyph, 0, len(in))
 	for _, g := range in {
 		// To better understand how to calculate the bounding box, see here:
@@ -744,8 +1074,21 @@ func toGioGlyphs(in []shaping.Glyph, ppem fixed.Int26_6, faceIdx int) []glyph {
 		bounds.Min.X = g.XBearing
 		bounds.Min.Y = -g.YBearing
 		bounds.Max = bounds.Min.Add(fixed.Point26_6{X: g.Width, Y: -g.Height})
+		if synth&syntheticOblique != 0 {
+			// The shear moves points above the baseline (negative Y) right
+			// and points below it left, in proportion to their distance.
+			if bounds.Min.Y < 0 {
+				bounds.Max.X -= floatToFixed(fixedToFloat(bounds.Min.Y) * syntheticObliqueShear)
+			}
+			if bounds.Max.Y > 0 {
+				bounds.Min.X -= floatToFixed(fixedToFloat(bounds.Max.Y) * syntheticObliqueShear)
+			}
+		}
+		if synth&syntheticBold != 0 {
+			bounds.Max.X += floatToFixed(fixedToFloat(ppem) * syntheticBoldStrength)
+		}
 		out = append(out, glyph{
-			id:           newGlyphID(ppem, faceIdx, g.GlyphID),
+			id:           newGlyphID(ppem, faceIdx, g.GlyphID, synth),
 			clusterIndex: g.ClusterIndex,
 			runeCount:    g.RuneCount,
 			glyphCount:   g.GlyphCount,
@@ -759,8 +1102,12 @@ func toGioGlyphs(in []shaping.Glyph, ppem fixed.Int26_6, faceIdx int) []glyph {
 	return out
 }
 
-// toLine converts the output into a Line with the provided dominant text direction.
-func toLine(orderer *faceOrderer, o shaping.Line, dir system.TextDirection) line {
+// toLine converts the output into a Line with the provided dominant text
+// direction. synth describes the style features that primary cannot provide
+// natively; it only applies to runs shaped with primary, since the other
+// faces in a fallback chain are substitutions for script coverage rather
+// than style alternates of the requested font.
+func toLine(orderer *faceOrderer, o shaping.Line, dir system.TextDirection, primary font.Face, synth syntheticStyle) line {
 	if len(o) < 1 {
 		return line{}
 	}
@@ -770,8 +1117,12 @@ func toLine(orderer *faceOrderer, o shaping.Line, dir system.TextDirection) line
 	}
 	for i := range o {
 		run := o[i]
+		var runSynth syntheticStyle
+		if run.Face == primary {
+			runSynth = synth
+		}
 		line.runs[i] = runLayout{
-			Glyphs: toGioGlyphs(run.Glyphs, run.Size, orderer.indexFor(run.Face)),
+			Glyphs: toGioGlyphs(run.Glyphs, run.Size, orderer.indexFor(run.Face), runSynth),
 			Runes: Range{
 				Count:  run.Runes.Count,
 				Offset: line.runeCount,
@@ -8,10 +8,10 @@ import (
 	"testing"
 
 	nsareg "eliasnaur.com/font/noto/sans/arabic/regular"
+	"github.com/go-text/typesetting/di"
 	"github.com/go-text/typesetting/font"
-	"github.com/go-text/typesetting/shaping"
 	"github.com/go-text/typesetting/language"
-	"github.com/go-text/typesetting/di"
+	"github.com/go-text/typesetting/shaping"
 	"golang.org/x/image/font/gofont/goregular"
 	"golang.org/x/image/math/fixed"
 
@@ -61,6 +61,117 @@ func TestEmptyString(t *testing.T) {
 	}
 }
 
+// TestMetrics checks that cap-height and x-height are reported for a face that
+// provides them, and that both scale linearly with PxPerEm.
+func TestMetrics(t *testing.T) {
+	ltrFace, _ := opentype.Parse(goregular.TTF)
+	shaper := testShaper(ltrFace)
+	fnt := Font{}
+
+	small := shaper.Metrics(fnt, fixed.I(10))
+	if small.CapHeight <= 0 {
+		t.Errorf("CapHeight = %v, expected a positive value", small.CapHeight)
+	}
+	if small.XHeight <= 0 {
+		t.Errorf("XHeight = %v, expected a positive value", small.XHeight)
+	}
+
+	large := shaper.Metrics(fnt, fixed.I(20))
+	if got, want := large.CapHeight, small.CapHeight*2; math.Abs(float64(got-want)) > 1 {
+		t.Errorf("CapHeight did not scale linearly with PxPerEm: got %v, want ~%v", got, want)
+	}
+	if got, want := large.XHeight, small.XHeight*2; math.Abs(float64(got-want)) > 1 {
+		t.Errorf("XHeight did not scale linearly with PxPerEm: got %v, want ~%v", got, want)
+	}
+}
+
+// TestFaces checks that the Go font's family name round-trips through
+// Shaper.Faces.
+func TestFaces(t *testing.T) {
+	ltrFace, _ := opentype.Parse(goregular.TTF)
+	shaper := testShaper(ltrFace)
+
+	faces := shaper.Faces()
+	if len(faces) != 1 {
+		t.Fatalf("Faces() returned %d entries, expected 1", len(faces))
+	}
+	if got, want := faces[0].Family, "Go"; got != want {
+		t.Errorf("Family = %q, expected %q", got, want)
+	}
+	if faces[0].Font != (Font{}) {
+		t.Errorf("Font = %v, expected the zero value registered by testShaper", faces[0].Font)
+	}
+}
+
+// TestFontFeatures checks that toggling the "liga" feature changes the
+// shaped glyph count for text containing ligating letter pairs.
+func TestFontFeatures(t *testing.T) {
+	ltrFace, _ := opentype.Parse(goregular.TTF)
+	shaper := testShaper(ltrFace)
+	if !slices.Contains(shaper.FeatureTags(Font{}), "liga") {
+		t.Skip("face does not expose a liga feature to toggle")
+	}
+
+	glyphCount := func(features []FontFeature) int {
+		doc := shaper.LayoutString(Parameters{
+			PxPerEm:  fixed.I(16),
+			MaxWidth: 10000,
+			Features: features,
+		}, "ffi ffl")
+		n := 0
+		for _, l := range doc.lines {
+			for _, r := range l.runs {
+				n += len(r.Glyphs)
+			}
+		}
+		return n
+	}
+
+	without := glyphCount([]FontFeature{{Tag: "liga", Value: 0}})
+	with := glyphCount([]FontFeature{{Tag: "liga", Value: 1}})
+	if without == with {
+		t.Errorf("toggling liga did not change the shaped glyph count: got %d for both", without)
+	}
+}
+
+// TestFontFeatureRange checks that a FontFeature with a Start and End only
+// affects the runes in that range, rather than the whole shaped text.
+func TestFontFeatureRange(t *testing.T) {
+	ltrFace, _ := opentype.Parse(goregular.TTF)
+	shaper := testShaper(ltrFace)
+	if !slices.Contains(shaper.FeatureTags(Font{}), "liga") {
+		t.Skip("face does not expose a liga feature to toggle")
+	}
+
+	const txt = "ffi ffi"
+	glyphCount := func(features []FontFeature) int {
+		doc := shaper.LayoutString(Parameters{
+			PxPerEm:  fixed.I(16),
+			MaxWidth: 10000,
+			Features: features,
+		}, txt)
+		n := 0
+		for _, l := range doc.lines {
+			for _, r := range l.runs {
+				n += len(r.Glyphs)
+			}
+		}
+		return n
+	}
+
+	allOff := glyphCount([]FontFeature{{Tag: "liga", Value: 0}})
+	allOn := glyphCount([]FontFeature{{Tag: "liga", Value: 1}})
+	// liga is on by default, so disabling it for the second "ffi" alone
+	// leaves only the first one ligated.
+	firstOnly := glyphCount([]FontFeature{{Tag: "liga", Value: 0, Start: 4, End: 7}})
+	if firstOnly == allOn {
+		t.Errorf("range-restricted liga produced the same glyph count as fully enabled: got %d", firstOnly)
+	}
+	if firstOnly == allOff {
+		t.Errorf("range-restricted liga produced the same glyph count as fully disabled: got %d", firstOnly)
+	}
+}
+
 func TestNoFaces(t *testing.T) {
 	ppem := fixed.I(200)
 	shaper := testShaper()
@@ -211,6 +322,61 @@ func TestNewlineSynthesis(t *testing.T) {
 	}
 }
 
+func TestParagraphDirection(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		locale   system.Locale
+		mode     WritingMode
+		expected system.TextDirection
+	}{
+		{"horizontal ltr locale", english, Horizontal, system.LTR},
+		{"horizontal rtl locale", arabic, Horizontal, system.RTL},
+		{"vertical ltr locale", english, Vertical, system.TTB},
+		{"vertical rtl locale", arabic, Vertical, system.BTT},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := paragraphDirection(tc.locale, tc.mode); got != tc.expected {
+				t.Errorf("expected %v, got %v", tc.expected, got)
+			}
+			if got := unmapDirection(mapDirection(tc.expected)); got != tc.expected {
+				t.Errorf("direction did not round-trip through di.Direction: got %v", got)
+			}
+		})
+	}
+}
+
+// TestWritingModeVertical checks that a paragraph shaped with WritingMode:
+// Vertical produces lines and runs whose direction advances along the
+// vertical axis. It does not check on-screen glyph positions: Gio's glyph
+// positioning does not yet lay out or paint vertical text, as documented on
+// WritingMode.
+func TestWritingModeVertical(t *testing.T) {
+	ppem := fixed.I(10)
+	ltrFace, _ := opentype.Parse(goregular.TTF)
+	shaper := testShaper(ltrFace)
+
+	doc := shaper.LayoutRunes(Parameters{
+		PxPerEm:     ppem,
+		MaxWidth:    200,
+		Locale:      english,
+		WritingMode: Vertical,
+	}, []rune("The quick brown fox jumps over the lazy dog\n"))
+
+	if len(doc.lines) == 0 {
+		t.Fatal("expected at least one line")
+	}
+	for lineIdx, line := range doc.lines {
+		if line.direction.Axis() != system.Vertical {
+			t.Errorf("line %d: expected vertical axis, got %v", lineIdx, line.direction)
+		}
+		for runIdx, run := range line.runs {
+			if run.Direction.Axis() != system.Vertical {
+				t.Errorf("line %d run %d: expected vertical run direction, got %v", lineIdx, runIdx, run.Direction)
+			}
+		}
+	}
+}
+
 // simpleGlyph returns a simple square glyph with the provided cluster
 // value.
 func simpleGlyph(cluster int) shaping.Glyph {
@@ -480,6 +646,46 @@ func FuzzLayout(f *testing.F) {
 	})
 }
 
+// recordingShaper wraps shaping.HarfbuzzShaper and counts its invocations,
+// so a test can confirm that a FontFace.Shapers registration actually
+// intercepts shaping for its face rather than silently falling through to
+// the default engine.
+type recordingShaper struct {
+	shaping.HarfbuzzShaper
+	calls int
+}
+
+func (r *recordingShaper) Shape(input shaping.Input) shaping.Output {
+	r.calls++
+	return r.HarfbuzzShaper.Shape(input)
+}
+
+// FuzzLayoutWithShapers is FuzzLayout, but one of the two faces registers a
+// recordingShaper through FontFace.Shapers. Mixed LTR/RTL input splits into
+// runs on each face, so a single paragraph alternates between the
+// registered shaper and the default engine from run to run.
+func FuzzLayoutWithShapers(f *testing.F) {
+	ltrFace, _ := opentype.Parse(goregular.TTF)
+	rtlFace, _ := opentype.Parse(nsareg.TTF)
+	f.Add("د عرمثال dstي met لم aqل جدmوpمg lرe dرd  لو عل ميrةsdiduntut lab renنيتذدagلaaiua.ئPocttأior رادرsاي mيrbلmnonaيdتد ماةعcلخ.", uint8(10), uint16(200))
+
+	rec := &recordingShaper{}
+	shaper := &shaperImpl{}
+	shaper.Load(FontFace{Face: ltrFace, Shapers: []shaping.Shaper{rec}})
+	shaper.Load(FontFace{Face: rtlFace})
+	f.Fuzz(func(t *testing.T, txt string, fontSize uint8, width uint16) {
+		if fontSize < 1 {
+			fontSize = 1
+		}
+		lines := shaper.LayoutRunes(Parameters{
+			PxPerEm:  fixed.I(int(fontSize)),
+			MaxWidth: int(width),
+			Locale:   english,
+		}, []rune(txt))
+		validateLines(t, lines.lines, len([]rune(txt)))
+	})
+}
+
 func validateLines(t *testing.T, lines []line, expectedRuneCount int) {
 	t.Helper()
 	runesSeen := 0
@@ -551,11 +757,13 @@ func TestTextAppend(t *testing.T) {
 
 func TestGlyphIDPacking(t *testing.T) {
 	const maxPPem = fixed.Int26_6((1 << sizebits) - 1)
+	const maxFaceIndex = (1 << facebits) - 1
 	type testcase struct {
 		name      string
 		ppem      fixed.Int26_6
 		faceIndex int
 		gid       font.GID
+		synth     syntheticStyle
 		expected  GlyphID
 	}
 	for _, tc := range []testcase{
@@ -570,19 +778,28 @@ func TestGlyphIDPacking(t *testing.T) {
 			expected:  284223755780101,
 		},
 		{
-			name:      maxPPem.String() + " ppem faceIdx " + strconv.Itoa(math.MaxUint16) + " GID " + fmt.Sprintf("%d", int64(math.MaxUint32)),
+			name:      "10 ppem faceIdx 1 GID 5 synthetic oblique and bold",
+			ppem:      fixed.I(10),
+			faceIndex: 1,
+			gid:       5,
+			synth:     syntheticOblique | syntheticBold,
+			expected:  13835342279037943813,
+		},
+		{
+			name:      maxPPem.String() + " ppem faceIdx " + strconv.Itoa(maxFaceIndex) + " GID " + fmt.Sprintf("%d", int64(math.MaxUint32)),
 			ppem:      maxPPem,
-			faceIndex: math.MaxUint16,
+			faceIndex: maxFaceIndex,
 			gid:       math.MaxUint32,
+			synth:     syntheticOblique | syntheticBold,
 			expected:  18446744073709551615,
 		},
 	} {
 		t.Run(tc.name, func(t *testing.T) {
-			actual := newGlyphID(tc.ppem, tc.faceIndex, tc.gid)
+			actual := newGlyphID(tc.ppem, tc.faceIndex, tc.gid, tc.synth)
 			if actual != tc.expected {
 				t.Errorf("expected %d, got %d", tc.expected, actual)
 			}
-			actualPPEM, actualFaceIdx, actualGID := splitGlyphID(actual)
+			actualPPEM, actualFaceIdx, actualGID, actualSynth := splitGlyphID(actual)
 			if actualPPEM != tc.ppem {
 				t.Errorf("expected ppem %d, got %d", tc.ppem, actualPPEM)
 			}
@@ -592,6 +809,9 @@ func TestGlyphIDPacking(t *testing.T) {
 			if actualGID != tc.gid {
 				t.Errorf("expected gid %d, got %d", tc.gid, actualGID)
 			}
+			if actualSynth != tc.synth {
+				t.Errorf("expected synth %b, got %b", tc.synth, actualSynth)
+			}
 		})
 	}
 }
@@ -599,6 +819,43 @@ func TestGlyphIDPacking(t *testing.T) {
 // TestArabicDiacriticClustering verifies that Arabic diacritics (which usually have
 // script 'Inherited') are correctly clustered with their base Arabic letters,
 // rather than being split into a separate shaping run.
+// TestDisableSynthesis checks that requesting a bold-italic style of a
+// face that only has a regular cut yields glyphs whose bounding boxes
+// differ from a plain request, and that setting DisableSynthesis
+// suppresses that difference.
+func TestDisableSynthesis(t *testing.T) {
+	ppem := fixed.I(50)
+	ltrFace, _ := opentype.Parse(goregular.TTF)
+	shaper := testShaper(ltrFace)
+
+	glyphBounds := func(params Parameters) fixed.Rectangle26_6 {
+		doc := shaper.LayoutRunes(params, []rune("A"))
+		return doc.lines[0].runs[0].Glyphs[0].bounds
+	}
+
+	plain := glyphBounds(Parameters{PxPerEm: ppem, MaxWidth: 2000, Locale: english})
+	boldItalic := glyphBounds(Parameters{
+		PxPerEm:  ppem,
+		MaxWidth: 2000,
+		Locale:   english,
+		Font:     Font{Style: Italic, Weight: Bold},
+	})
+	if boldItalic == plain {
+		t.Fatalf("synthesized bold-italic glyph has the same bounds as plain: %v", plain)
+	}
+
+	noSynth := glyphBounds(Parameters{
+		PxPerEm:          ppem,
+		MaxWidth:         2000,
+		Locale:           english,
+		Font:             Font{Style: Italic, Weight: Bold},
+		DisableSynthesis: true,
+	})
+	if noSynth != plain {
+		t.Errorf("DisableSynthesis glyph bounds %v, expected plain bounds %v", noSynth, plain)
+	}
+}
+
 func TestArabicDiacriticClustering(t *testing.T) {
 	tests := []struct {
 		name          string
@@ -668,7 +925,7 @@ func TestArabicDiacriticClustering(t *testing.T) {
 				RunEnd:    len(tt.input),
 				Direction: tt.wantDirection,
 				Script:    language.Arabic,
-				Face:      nil,             // face doesn't really matter for splitting anyway
+				Face:      nil, // face doesn't really matter for splitting anyway
 				Size:      fixed.I(10),
 			}}
 
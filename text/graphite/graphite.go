@@ -0,0 +1,143 @@
+// SPDX-License-Identifier: Unlicense OR MIT
+
+//go:build graphite
+
+// Package graphite shapes text with libgraphite2. Some fonts, mostly SIL
+// releases targeting minority languages (Padauk for Burmese, Annapurna for
+// Devanagari, and the like) encode their rendering rules in Graphite's
+// Silf/Glat/Gloc tables instead of, or in addition to, OpenType GSUB/GPOS;
+// go-text/typesetting's HarfbuzzShaper only understands the OpenType
+// tables. Registering a *Shaper from this package through
+// text.FontFace.Shapers lets such a face shape correctly.
+//
+// The package is gated behind the "graphite" build tag because it cgo-links
+// against libgraphite2, which is not vendored: install a distribution
+// package such as libgraphite2-dev (Debian/Ubuntu) before building with
+// -tags graphite.
+package graphite
+
+/*
+#cgo pkg-config: graphite2
+#include <stdlib.h>
+#include <graphite2/Font.h>
+#include <graphite2/Segment.h>
+*/
+import "C"
+
+import (
+	"errors"
+	"unsafe"
+
+	"github.com/go-text/typesetting/di"
+	"github.com/go-text/typesetting/font"
+	"github.com/go-text/typesetting/shaping"
+	"golang.org/x/image/math/fixed"
+)
+
+// Shaper shapes text with a single Graphite-enabled face. Unlike
+// shaping.HarfbuzzShaper, which shapes with whatever font.Face each Input
+// carries, a Shaper is bound to one face's raw bytes at construction time:
+// libgraphite2 builds its own face and font objects from font bytes rather
+// than from a go-text/typesetting font.Face, so there is no cheap way to
+// share a single Shaper across faces the way the OpenType path shares one
+// HarfbuzzShaper.
+type Shaper struct {
+	face *C.gr_face
+	font *C.gr_font
+	// data is retained for the lifetime of face, which borrows rather than
+	// copies the bytes passed to gr_make_face.
+	data []byte
+}
+
+var (
+	errFaceCreation = errors.New("graphite: gr_make_face failed")
+	errFontCreation = errors.New("graphite: gr_make_font failed")
+)
+
+// New constructs a Shaper from the raw bytes of a font containing Graphite
+// tables, rasterized at ppem pixels per em. The returned Shaper borrows
+// fontData until Release is called, so the caller must keep it alive and
+// unmodified for that long.
+func New(fontData []byte, ppem float32) (*Shaper, error) {
+	var base unsafe.Pointer
+	if len(fontData) > 0 {
+		base = unsafe.Pointer(&fontData[0])
+	}
+	face := C.gr_make_face(base, C.size_t(len(fontData)), C.gr_face_default)
+	if face == nil {
+		return nil, errFaceCreation
+	}
+	fnt := C.gr_make_font(C.float(ppem), face)
+	if fnt == nil {
+		C.gr_face_destroy(face)
+		return nil, errFontCreation
+	}
+	return &Shaper{face: face, font: fnt, data: fontData}, nil
+}
+
+// Release frees the libgraphite2 resources held by s. Shape must not be
+// called after Release.
+func (s *Shaper) Release() {
+	if s.font != nil {
+		C.gr_font_destroy(s.font)
+		s.font = nil
+	}
+	if s.face != nil {
+		C.gr_face_destroy(s.face)
+		s.face = nil
+	}
+	s.data = nil
+}
+
+// Shape implements shaping.Shaper using libgraphite2's line-breaking-free
+// segment API. input.Face is ignored: a Shaper is already bound to the one
+// Graphite face it was constructed from. Graphite has no notion of a
+// vertical run, so a vertical input is shaped horizontally; Gio's vertical
+// writing mode synthesizes column layout above the shaper rather than
+// relying on per-glyph vertical advances from it.
+func (s *Shaper) Shape(input shaping.Input) shaping.Output {
+	runes := input.Text[input.RunStart:input.RunEnd]
+	utf32 := make([]C.uint32_t, len(runes))
+	for i, r := range runes {
+		utf32[i] = C.uint32_t(r)
+	}
+	var dir C.int
+	if input.Direction.Axis() == di.Horizontal && input.Direction.Progression() == di.TowardTopLeft {
+		dir = 1 // gr_rtl
+	}
+	var textPtr unsafe.Pointer
+	if len(utf32) > 0 {
+		textPtr = unsafe.Pointer(&utf32[0])
+	}
+	out := shaping.Output{
+		Size:      input.Size,
+		Direction: input.Direction,
+		Runes:     shaping.Range{Offset: input.RunStart, Count: len(runes)},
+	}
+	seg := C.gr_make_seg(s.font, s.face, 0, nil, C.gr_utf32, textPtr, C.size_t(len(utf32)), dir)
+	if seg == nil {
+		return out
+	}
+	defer C.gr_seg_destroy(seg)
+
+	n := int(C.gr_seg_n_slots(seg))
+	glyphs := make([]shaping.Glyph, 0, n)
+	slot := C.gr_seg_first_slot(seg)
+	for i := 0; i < n && slot != nil; i++ {
+		glyphs = append(glyphs, shaping.Glyph{
+			GlyphID:      font.GID(C.gr_slot_gid(slot)),
+			XAdvance:     fixed.Int26_6(C.gr_slot_advance_X(slot, s.font, seg) * 64),
+			XOffset:      fixed.Int26_6(C.gr_slot_origin_X(slot) * 64),
+			YOffset:      fixed.Int26_6(C.gr_slot_origin_Y(slot) * 64),
+			ClusterIndex: input.RunStart + int(C.gr_slot_before(slot)),
+			RuneCount:    1,
+			GlyphCount:   1,
+		})
+		slot = C.gr_slot_next_in_segment(slot)
+	}
+	out.Glyphs = glyphs
+	out.Advance = fixed.Int26_6(C.gr_seg_advance_X(seg) * 64)
+	return out
+}
+
+var _ shaping.Shaper = (*Shaper)(nil)
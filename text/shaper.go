@@ -13,6 +13,7 @@ import (
 	"gioui.org/op"
 	"gioui.org/op/clip"
 	"github.com/go-text/typesetting/font"
+	"github.com/go-text/typesetting/shaping"
 	"golang.org/x/image/math/fixed"
 )
 
@@ -32,11 +33,31 @@ type Parameters struct {
 	// truncated.
 	Truncator string
 
-	// MinWidth and MaxWidth provide the minimum and maximum horizontal space constraints
-	// for the shaped text.
+	// MinWidth and MaxWidth provide the minimum and maximum space constraints for the
+	// shaped text along its primary advance axis. For WritingMode Vertical, that axis
+	// is the column height rather than the line width.
 	MinWidth, MaxWidth int
 	// Locale provides primary direction and language information for the shaped text.
 	Locale system.Locale
+	// WritingMode selects whether the text advances horizontally or vertically. It
+	// defaults to Horizontal.
+	WritingMode WritingMode
+
+	// Features lists the OpenType features to enable or disable while shaping,
+	// such as standard ligatures, small caps, or a stylistic set. A feature
+	// whose Start and End are both zero applies to the entire text; otherwise
+	// it is restricted to the rune range [Start, End), letting callers vary
+	// features within a single shaped paragraph.
+	Features []FontFeature
+
+	// DisableSynthesis stops the shaper from faking the weight and style
+	// axes a fallback face can't natively provide, such as slanting an
+	// upright face's outline to approximate Italic or thickening it to
+	// approximate Bold. With DisableSynthesis, Font.Style and Font.Weight
+	// only ever affect which face is matched, the same as before
+	// synthesis was added; glyphs are always shaped and painted exactly
+	// as the matched face provides them.
+	DisableSynthesis bool
 
 	// forceTruncate controls whether the truncator string is inserted on the final line of
 	// text with a MaxLines. It is unexported because this behavior only makes sense for the
@@ -44,10 +65,57 @@ type Parameters struct {
 	forceTruncate bool
 }
 
+// FontFeature enables or disables an OpenType font feature during shaping,
+// such as "liga" (standard ligatures), "smcp" (small caps), "ss01" (a
+// stylistic set), or "tnum" (tabular figures).
+type FontFeature struct {
+	// Tag is the 4-byte OpenType feature tag, e.g. "liga". Tags shorter than
+	// 4 bytes are padded with spaces; longer ones are truncated.
+	Tag string
+	// Value enables the feature when non-zero, and disables it otherwise.
+	// Some features, such as stylistic alternates, use values greater than
+	// 1 to select among several variants.
+	Value uint32
+	// Start and End restrict the feature to the rune range [Start, End) of
+	// the shaped text, using the same offsets as the text passed to
+	// LayoutRunes. The zero value for both fields means the feature applies
+	// to the entire text.
+	Start, End int
+}
+
 // A FontFace is a Font and a matching Face.
 type FontFace struct {
 	Font Font
 	Face Face
+	// Shapers are tried, in order, before the default OpenType shaping
+	// engine, for any run of text assigned to Face. This lets a face whose
+	// layout rules live outside OpenType GSUB/GPOS, such as a Graphite
+	// font shaped by the text/graphite subpackage, be shaped correctly.
+	// A face with no Shapers is shaped with the default engine only.
+	Shapers []shaping.Shaper
+}
+
+// FaceInfo describes a face available to a Shaper, for use by font-picker
+// UIs that need to present the faces of a collection to users. Font is the
+// descriptor that selects the face through Parameters.Font.
+type FaceInfo struct {
+	Font Font
+	// Family is the typographic family name recorded in the face's name
+	// table, e.g. "Go".
+	Family string
+}
+
+// LineMetrics describes typographic metrics of a face at a given size, beyond
+// the per-line Ascent/Descent already carried on Glyph. These let callers
+// align content to the font's ink rather than its loose ascent/descent
+// envelope, e.g. aligning an icon to CapHeight or sizing a caret to XHeight.
+type LineMetrics struct {
+	// CapHeight is the distance from the baseline to the top of flat capital
+	// letters such as "H".
+	CapHeight fixed.Int26_6
+	// XHeight is the distance from the baseline to the top of flat lowercase
+	// letters such as "x".
+	XHeight fixed.Int26_6
 }
 
 // Glyph describes a shaped font glyph. Many fields are distances relative
@@ -470,13 +538,31 @@ func (l *Shaper) NextGlyph() (_ Glyph, ok bool) {
 }
 
 const (
-	facebits = 16
-	sizebits = 16
-	gidbits  = 64 - facebits - sizebits
+	facebits  = 14
+	sizebits  = 16
+	synthbits = 2
+	gidbits   = 64 - facebits - sizebits - synthbits
+)
+
+// syntheticStyle flags a Font style feature that the face chosen for a
+// glyph cannot provide natively, so Shaper.Shape must fake it when turning
+// the glyph into a path. The flags ride along in the GlyphID itself because
+// that is the only per-glyph state that survives from shaping through to
+// Shape.
+type syntheticStyle uint8
+
+const (
+	// syntheticOblique shears the glyph outline to approximate an italic
+	// style from an upright face.
+	syntheticOblique syntheticStyle = 1 << iota
+	// syntheticBold thickens the glyph outline to approximate a heavier
+	// weight than the face natively provides.
+	syntheticBold
 )
 
-// newGlyphID encodes a face and a glyph id into a GlyphID.
-func newGlyphID(ppem fixed.Int26_6, faceIdx int, gid font.GID) GlyphID {
+// newGlyphID encodes a face, a glyph id and any synthetic style flags into
+// a GlyphID.
+func newGlyphID(ppem fixed.Int26_6, faceIdx int, gid font.GID, synth syntheticStyle) GlyphID {
 	if gid&^((1<<gidbits)-1) != 0 {
 		fmt.Println(gid)
 		panic("glyph id out of bounds")
@@ -490,15 +576,16 @@ func newGlyphID(ppem fixed.Int26_6, faceIdx int, gid font.GID) GlyphID {
 	// Mask off the upper 16 bits of ppem. This still allows values up to
 	// 1023.
 	ppem &= ((1 << sizebits) - 1)
-	return GlyphID(faceIdx)<<(gidbits+sizebits) | GlyphID(ppem)<<(gidbits) | GlyphID(gid)
+	return GlyphID(synth)<<(gidbits+sizebits+facebits) | GlyphID(faceIdx)<<(gidbits+sizebits) | GlyphID(ppem)<<(gidbits) | GlyphID(gid)
 }
 
 // splitGlyphID is the opposite of newGlyphID.
-func splitGlyphID(g GlyphID) (fixed.Int26_6, int, font.GID) {
-	faceIdx := int(g) >> (gidbits + sizebits)
+func splitGlyphID(g GlyphID) (fixed.Int26_6, int, font.GID, syntheticStyle) {
+	synth := syntheticStyle(g >> (gidbits + sizebits + facebits))
+	faceIdx := int(g>>(gidbits+sizebits)) & (1<<facebits - 1)
 	ppem := fixed.Int26_6((g & ((1<<sizebits - 1) << gidbits)) >> gidbits)
 	gid := font.GID(g) & (1<<gidbits - 1)
-	return ppem, faceIdx, gid
+	return ppem, faceIdx, gid, synth
 }
 
 // Shape converts the provided glyphs into a path. The path will enclose the forms
@@ -516,6 +603,36 @@ func (l *Shaper) Shape(gs []Glyph) clip.PathSpec {
 	return shape
 }
 
+// Metrics returns the cap-height and x-height of font, scaled to ppem. It
+// reports the zero value for metrics the face's OS/2 table does not provide.
+//
+// The OpenType head table's global xMin/yMin/xMax/yMax bounding box is not
+// exposed here: the vendored go-text/typesetting shaping library has no
+// accessor for it, so a Bounds field would have no real data to report.
+func (l *Shaper) Metrics(font Font, ppem fixed.Int26_6) LineMetrics {
+	return l.shaper.Metrics(font, ppem)
+}
+
+// Faces returns metadata for every face registered with the shaper via
+// NewShaper, in registration order.
+//
+// The vendored go-text/typesetting font package only surfaces the family
+// name from a face's name table (through font.Font.Describe); it keeps the
+// remaining name table records private, so FaceInfo cannot report a
+// subfamily, full name, PostScript name, version, or preferred language
+// variants.
+func (l *Shaper) Faces() []FaceInfo {
+	return l.shaper.Faces()
+}
+
+// FeatureTags enumerates the GSUB and GPOS feature tags available in the
+// face selected by font, for building UI that lets users toggle the
+// features a font supports (ligatures, small caps, stylistic sets, and
+// the like).
+func (l *Shaper) FeatureTags(font Font) []string {
+	return l.shaper.FeatureTags(font)
+}
+
 // Bitmaps extracts bitmap glyphs from the provided slice and creates an op.CallOp to present
 // them. The returned op.CallOp will align correctly with the return value of Shape() for the
 // same gs slice.
@@ -531,3 +648,18 @@ func (l *Shaper) Bitmaps(gs []Glyph) op.CallOp {
 	l.bitmapShapeCache.Put(key, gs, call)
 	return call
 }
+
+// Layers returns an op.CallOp that displays the color glyph layers within gs.
+// The returned CallOp uses the same positioning logic as Shape, so it can be
+// added at the same offset as the path returned by Shape for the same gs
+// slice and will align correctly with it.
+// All glyphs are expected to be from a single line of text (their Y offsets are ignored).
+//
+// Color glyph support only covers the flat, COLRv0-style layer list: each
+// layer is a single solid-colored outline, composited in the order the font
+// provides. Gradients, transforms and the rest of the COLRv1 paint graph are
+// not represented, so glyphs that rely on them contribute no layers.
+func (l *Shaper) Layers(gs []Glyph) op.CallOp {
+	callOps := new(op.Ops)
+	return l.shaper.Layers(callOps, gs)
+}
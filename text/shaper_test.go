@@ -2,6 +2,7 @@ package text
 
 import (
 	"fmt"
+	"image/color"
 	"strings"
 	"testing"
 
@@ -9,7 +10,11 @@ import (
 	"gioui.org/font"
 	"gioui.org/font/gofont"
 	"gioui.org/font/opentype"
+	"gioui.org/gpu/headless"
 	"gioui.org/io/system"
+	"gioui.org/op"
+	"gioui.org/op/clip"
+	"gioui.org/op/paint"
 	"golang.org/x/exp/slices"
 	"golang.org/x/image/font/gofont/goregular"
 	"golang.org/x/image/math/fixed"
@@ -462,6 +467,70 @@ func printLinePositioning(t *testing.T, lines []line, glyphs []Glyph) {
 	}
 }
 
+// TestShapeOutlines verifies that Shaper.Shape can convert the glyphs of a shaped
+// line into a renderable clip.PathSpec for LTR, RTL, and bidi runs, and that
+// repeated calls for the same glyphs hit the path cache instead of reshaping.
+func TestShapeOutlines(t *testing.T) {
+	ltrFace, _ := opentype.Parse(goregular.TTF)
+	rtlFace, _ := opentype.Parse(nsareg.TTF)
+	collection := []FontFace{{Face: ltrFace}, {Face: rtlFace}}
+	type testcase struct {
+		name   string
+		text   string
+		locale system.Locale
+	}
+	for _, tc := range []testcase{
+		{name: "ltr", text: "The quick brown fox jumps over the lazy dog.", locale: english},
+		{name: "rtl", text: "الحب سماء والأحلام الجميلة", locale: arabic},
+		{name: "bidi", text: "The quick سماء שלום لا fox تمط", locale: english},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			shaper := NewShaper(NoSystemFonts(), WithCollection(collection))
+			shaper.LayoutString(Parameters{
+				PxPerEm:  fixed.I(16),
+				MaxWidth: 10000,
+				Locale:   tc.locale,
+			}, tc.text)
+			var line []Glyph
+			for g, ok := shaper.NextGlyph(); ok; g, ok = shaper.NextGlyph() {
+				line = append(line, g)
+				if g.Flags&FlagLineBreak != 0 {
+					break
+				}
+			}
+			if len(line) == 0 {
+				t.Fatal("shaping produced no glyphs")
+			}
+			path := shaper.Shape(line)
+			cached := shaper.Shape(line)
+			if path != cached {
+				t.Error("Shape did not return the cached path for identical glyphs")
+			}
+			w := newHeadlessWindow(t, 200, 100)
+			if w == nil {
+				return
+			}
+			ops := new(op.Ops)
+			stack := clip.Outline{Path: path}.Op().Push(ops)
+			paint.Fill(ops, color.NRGBA{A: 255})
+			stack.Pop()
+			if err := w.Frame(ops); err != nil {
+				t.Errorf("Shape produced an unrenderable path: %v", err)
+			}
+		})
+	}
+}
+
+func newHeadlessWindow(t *testing.T, width, height int) *headless.Window {
+	t.Helper()
+	w, err := headless.NewWindow(width, height)
+	if err != nil {
+		t.Skipf("failed to create headless window, skipping: %v", err)
+		return nil
+	}
+	return w
+}
+
 // TestShapeStringRuneAccounting tries shaping the same string/parameter combinations with both
 // shaping methods and ensures that the resulting glyph stream always has the right number of
 // runes accounted for.
@@ -47,6 +47,28 @@ const (
 	Middle
 )
 
+// WritingMode selects the axis a paragraph advances along.
+type WritingMode uint8
+
+const (
+	// Horizontal lays out each line left-to-right or right-to-left, stacking
+	// lines top-to-bottom. This is the default.
+	Horizontal WritingMode = iota
+	// Vertical lays out each column top-to-bottom or bottom-to-top, stacking
+	// columns according to the paragraph's Locale.
+	//
+	// Vertical support is limited to shaping: the vendored go-text/typesetting
+	// shaper is given a vertical direction so that YAdvance and YBearing are
+	// populated correctly for a vertical script, and column wrapping reuses
+	// Parameters.MaxWidth as the column height. Gio's own glyph positioning
+	// (Glyph.X/Y, Shaper.Shape, Shaper.Bitmaps, Shaper.Layers) still assumes a
+	// horizontal pen, so it does not yet lay the result out or paint it as a
+	// vertical column, and the Unicode Vertical_Orientation property that
+	// should keep upright CJK glyphs upright while rotating embedded Latin
+	// runs sideways (via the vert/vrt2 OpenType features) is not applied.
+	Vertical
+)
+
 const (
 	Regular Style = iota
 	Italic
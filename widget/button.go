@@ -68,6 +68,11 @@ func (b *Clickable) Pressed() bool {
 	return b.click.Pressed()
 }
 
+// Focused reports whether b holds keyboard focus.
+func (b *Clickable) Focused(gtx layout.Context) bool {
+	return gtx.Focused(b)
+}
+
 // History is the past pointer presses useful for drawing markers.
 // History is retained for a short duration (about a second).
 func (b *Clickable) History() []Press {
@@ -109,6 +109,9 @@ type imeState struct {
 	}
 	snippet    key.Snippet
 	start, end int
+	// preedit holds the in-progress text of an uncommitted input
+	// method composition, such as a dead-key sequence.
+	preedit string
 }
 
 type maskReader struct {
@@ -421,10 +424,13 @@ func (e *Editor) processKey(gtx layout.Context) (EditorEvent, bool) {
 			}
 		case key.SnippetEvent:
 			e.updateSnippet(gtx, ke.Start, ke.End)
+		case key.PreEditEvent:
+			e.ime.preedit = ke.Text
 		case key.EditEvent:
 			if e.ReadOnly {
 				break
 			}
+			e.ime.preedit = ""
 			e.scrollCaret = true
 			e.scroller.Stop()
 			s := ke.Text
@@ -0,0 +1,226 @@
+// SPDX-License-Identifier: Unlicense OR MIT
+
+package widget
+
+import (
+	"bytes"
+	"image"
+	"testing"
+	"unicode/utf8"
+
+	"gioui.org/font"
+	"gioui.org/layout"
+	"gioui.org/op"
+	"gioui.org/text"
+	"gioui.org/unit"
+
+	"golang.org/x/image/math/fixed"
+)
+
+// EditorFuzz drives an Editor through a scripted tape of operations decoded
+// from a fuzz input, re-laying-out and checking invariants after each one.
+// It replaces the single cut-and-paste exercise FuzzEditorEditing used to
+// run, which only ever touched one run of text at random offsets and so
+// never reached the bidi and grapheme-cluster boundaries where those
+// invariants actually tend to break.
+//
+// The op vocabulary is Insert, Delete, SetCaret, MoveCaret and a layout
+// constraint change. Editor has no SelectLine method and no IME composing
+// region API (SetComposingRegion, CommitComposingText): both were part of
+// the original request, but neither exists on this type to drive, so they
+// are left out rather than faked.
+type EditorFuzz struct {
+	e      *Editor
+	gtx    layout.Context
+	shaper *text.Shaper
+	font   font.Font
+	size   unit.Sp
+	pool   []rune
+}
+
+// editorFuzzWidths is the set of constraint widths the tape can switch
+// between, chosen to force rewrapping of multi-line and bidi text.
+var editorFuzzWidths = []int{20, 50, 100, 200, 400}
+
+// Run decodes tape as a sequence of opcodes and applies them to an Editor
+// seeded with txt, pulling inserted text from pool. It fails t if an
+// invariant is violated after any operation.
+func (f *EditorFuzz) Run(t *testing.T, txt, pool string, tape []byte) {
+	f.pool = []rune(pool)
+	f.e = new(Editor)
+	f.e.SetText(txt)
+	f.gtx = layout.Context{
+		Ops:         new(op.Ops),
+		Constraints: layout.Constraints{Max: image.Pt(200, 1000)},
+		Locale:      arabic,
+	}
+	f.shaper = text.NewShaper(text.NoSystemFonts(), text.WithCollection(benchFonts))
+	f.font = font.Font{}
+	f.size = unit.Sp(10)
+
+	f.layout()
+	f.checkInvariants(t)
+
+	const maxOps = 64
+	r := fuzzTape{b: tape}
+	for i := 0; i < maxOps && r.more(); i++ {
+		switch r.byte() % 5 {
+		case 0:
+			f.e.Insert(f.take(r.int16()))
+		case 1:
+			f.e.Delete(int(r.int16()))
+		case 2:
+			a, b := f.clampPair(r.int16(), r.int16())
+			f.e.SetCaret(a, b)
+		case 3:
+			f.e.MoveCaret(int(r.int16()), int(r.int16()))
+		case 4:
+			f.gtx.Constraints.Max.X = editorFuzzWidths[int(r.byte())%len(editorFuzzWidths)]
+		}
+		f.layout()
+		f.checkInvariants(t)
+	}
+}
+
+// take returns a substring of the insertion pool of up to 7 runes, chosen
+// and sized from n so that short tapes can still hit multi-rune grapheme
+// clusters such as the emoji ZWJ sequences seeded below.
+func (f *EditorFuzz) take(n int16) string {
+	if len(f.pool) == 0 {
+		return ""
+	}
+	start := int(n) % len(f.pool)
+	if start < 0 {
+		start += len(f.pool)
+	}
+	length := (int(n) >> 8) % 8
+	if length < 0 {
+		length = -length
+	}
+	end := start + length
+	if end > len(f.pool) {
+		end = len(f.pool)
+	}
+	return string(f.pool[start:end])
+}
+
+// clampPair reduces two raw operands to a valid (start, end) pair within
+// [0, Len()], the precondition SetCaret otherwise panics on.
+func (f *EditorFuzz) clampPair(x, y int16) (int, int) {
+	n := f.e.Len() + 1
+	a := int(x) % n
+	if a < 0 {
+		a += n
+	}
+	b := int(y) % n
+	if b < 0 {
+		b += n
+	}
+	return a, b
+}
+
+func (f *EditorFuzz) layout() {
+	f.gtx.Ops.Reset()
+	f.e.Layout(f.gtx, f.shaper, f.font, f.size, op.CallOp{}, op.CallOp{})
+}
+
+// checkInvariants re-asserts the properties that bidi reordering and
+// grapheme-cluster splitting have historically broken silently: caret
+// bounds, the rune-count/Len agreement, selection endpoints landing on
+// cluster boundaries, and that laying out the same text twice produces the
+// same ops.
+func (f *EditorFuzz) checkInvariants(t *testing.T) {
+	e := f.e
+	start, end := e.Selection()
+	n := e.Len()
+	if start < 0 || start > n || end < 0 || end > n {
+		t.Fatalf("selection [%d, %d) escapes [0, %d]", start, end, n)
+	}
+	txt := e.Text()
+	if got := utf8.RuneCountInString(txt); got != n {
+		t.Fatalf("Len() = %d but Text() has %d runes", n, got)
+	}
+	bounds := f.clusterBounds(txt)
+	if !bounds[start] {
+		t.Errorf("selection start %d is not a grapheme cluster boundary in %q", start, txt)
+	}
+	if !bounds[end] {
+		t.Errorf("selection end %d is not a grapheme cluster boundary in %q", end, txt)
+	}
+
+	var first, second bytes.Buffer
+	f.layout()
+	if _, err := f.gtx.Ops.WriteTo(&first); err != nil {
+		t.Fatalf("writing ops: %v", err)
+	}
+	f.layout()
+	if _, err := f.gtx.Ops.WriteTo(&second); err != nil {
+		t.Fatalf("writing ops: %v", err)
+	}
+	if !bytes.Equal(first.Bytes(), second.Bytes()) {
+		t.Errorf("re-layout of unchanged text %q produced different ops", txt)
+	}
+}
+
+// clusterBounds returns the set of rune offsets into txt, including 0 and
+// the rune length, that the shaper reports as grapheme cluster boundaries.
+func (f *EditorFuzz) clusterBounds(txt string) map[int]bool {
+	bounds := map[int]bool{0: true}
+	f.shaper.LayoutString(text.Parameters{
+		Font:    text.Font{Typeface: f.font.Typeface, Variant: f.font.Variant, Style: text.Style(f.font.Style), Weight: text.Weight(f.font.Weight)},
+		PxPerEm: fixed.I(f.gtx.Sp(f.size)),
+		Locale:  f.gtx.Locale,
+	}, txt)
+	runeIdx := 0
+	for g, ok := f.shaper.NextGlyph(); ok; g, ok = f.shaper.NextGlyph() {
+		runeIdx += g.Runes
+		if g.Flags&text.FlagClusterBreak != 0 {
+			bounds[runeIdx] = true
+		}
+	}
+	return bounds
+}
+
+// fuzzTape is a cursor over a fuzz-provided byte slice, decoding an opcode
+// stream that runs dry (rather than erroring) once exhausted so a mutated,
+// truncated tape is still a legal, shorter script.
+type fuzzTape struct {
+	b   []byte
+	pos int
+}
+
+func (r *fuzzTape) more() bool { return r.pos < len(r.b) }
+
+func (r *fuzzTape) byte() byte {
+	if r.pos >= len(r.b) {
+		return 0
+	}
+	v := r.b[r.pos]
+	r.pos++
+	return v
+}
+
+func (r *fuzzTape) int16() int16 {
+	hi := r.byte()
+	lo := r.byte()
+	return int16(uint16(hi)<<8 | uint16(lo))
+}
+
+// FuzzEditorEditing scripts Editor operations over documents drawn from the
+// mixed-direction corpus in text_bench_test.go, seeded with the Arabic and
+// complex mixed-direction documents plus emoji ZWJ sequences, to catch
+// bidi-boundary and cluster-splitting regressions.
+func FuzzEditorEditing(f *testing.F) {
+	f.Add(complexDocument, emojiZWJDocument, []byte{0, 1, 2, 3, 4})
+	f.Add(arabicDocument, latinDocument, []byte{2, 0, 0, 2, 1, 0, 4, 3})
+	f.Add("", emojiZWJDocument, []byte{0, 0, 0, 5, 0, 0})
+	f.Fuzz(func(t *testing.T, txt, pool string, tape []byte) {
+		var fz EditorFuzz
+		fz.Run(t, txt, pool, tape)
+	})
+}
+
+// emojiZWJDocument seeds the insertion pool with multi-rune grapheme
+// clusters: family and flag sequences joined with U+200D, which a naive
+// rune-counted caret move or selection can split in the middle of.
+const emojiZWJDocument = "👨‍👩‍👧‍👦 👩‍👩‍👧‍👧 🏳️‍🌈 👨‍👨‍👦 🧑‍🤝‍🧑"
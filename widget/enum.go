@@ -8,6 +8,7 @@ import (
 	"gioui.org/io/key"
 	"gioui.org/io/pointer"
 	"gioui.org/io/semantic"
+	"gioui.org/io/system"
 	"gioui.org/layout"
 	"gioui.org/op"
 	"gioui.org/op/clip"
@@ -46,7 +47,7 @@ func (e *Enum) Update(gtx layout.Context) bool {
 	}
 	e.hovering = false
 	changed := false
-	for _, state := range e.keys {
+	for i, state := range e.keys {
 		for {
 			ev, ok := state.click.Update(gtx.Source)
 			if !ok {
@@ -69,6 +70,12 @@ func (e *Enum) Update(gtx layout.Context) bool {
 				key.FocusFilter{Target: &state.tag},
 				key.Filter{Focus: &state.tag, Name: key.NameReturn},
 				key.Filter{Focus: &state.tag, Name: key.NameSpace},
+				key.Filter{Focus: &state.tag, Name: key.NameLeftArrow},
+				key.Filter{Focus: &state.tag, Name: key.NameRightArrow},
+				key.Filter{Focus: &state.tag, Name: key.NameUpArrow},
+				key.Filter{Focus: &state.tag, Name: key.NameDownArrow},
+				key.Filter{Focus: &state.tag, Name: key.NameHome},
+				key.Filter{Focus: &state.tag, Name: key.NameEnd},
 			)
 			if !ok {
 				break
@@ -85,12 +92,18 @@ func (e *Enum) Update(gtx layout.Context) bool {
 				if ev.State != key.Release {
 					break
 				}
-				if ev.Name != key.NameReturn && ev.Name != key.NameSpace {
-					break
-				}
-				if state.key != e.Value {
-					e.Value = state.key
-					changed = true
+				switch ev.Name {
+				case key.NameReturn, key.NameSpace:
+					if state.key != e.Value {
+						e.Value = state.key
+						changed = true
+					}
+				case key.NameLeftArrow, key.NameRightArrow, key.NameUpArrow, key.NameDownArrow, key.NameHome, key.NameEnd:
+					if next := e.move(i, ev.Name, gtx.Locale.Direction); next != nil && next != state {
+						e.Value = next.key
+						changed = true
+						gtx.Execute(key.FocusCmd{Tag: &next.tag})
+					}
 				}
 			}
 		}
@@ -103,6 +116,32 @@ func (e *Enum) Update(gtx layout.Context) bool {
 	return changed
 }
 
+// move returns the sibling that an arrow, Home or End key should move the
+// roving tab stop and selection to, starting from the key at index from in
+// registration order. dir swaps the left/right arrows under a right-to-left
+// locale, so that they still move towards the visual start and end of the
+// group.
+func (e *Enum) move(from int, name key.Name, dir system.TextDirection) *enumKey {
+	if len(e.keys) == 0 {
+		return nil
+	}
+	switch name {
+	case key.NameHome:
+		return e.keys[0]
+	case key.NameEnd:
+		return e.keys[len(e.keys)-1]
+	}
+	delta := 1
+	if name == key.NameLeftArrow || name == key.NameUpArrow {
+		delta = -1
+	}
+	if dir == system.RTL && (name == key.NameLeftArrow || name == key.NameRightArrow) {
+		delta = -delta
+	}
+	idx := (from + delta + len(e.keys)) % len(e.keys)
+	return e.keys[idx]
+}
+
 // Hovered returns the key that is highlighted, or false if none are.
 func (e *Enum) Hovered() (string, bool) {
 	return e.hovered, e.hovering
@@ -130,7 +169,13 @@ func (e *Enum) Layout(gtx layout.Context, k string, content layout.Widget) layou
 	}
 	clk := &state.click
 	clk.Add(gtx.Ops)
-	event.Op(gtx.Ops, &state.tag)
+	// The group is a single tab stop: only the selected key (or, if nothing
+	// is selected yet, the first registered key) participates in tab order.
+	// Arrow keys move both the selection and this roving tab stop among the
+	// other keys in the group.
+	if k == e.Value || (e.Value == "" && state == e.keys[0]) {
+		event.Op(gtx.Ops, &state.tag)
+	}
 	semantic.SelectedOp(k == e.Value).Add(gtx.Ops)
 	semantic.EnabledOp(gtx.Enabled()).Add(gtx.Ops)
 	c.Add(gtx.Ops)
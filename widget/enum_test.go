@@ -0,0 +1,102 @@
+// SPDX-License-Identifier: Unlicense OR MIT
+
+package widget_test
+
+import (
+	"image"
+	"testing"
+
+	"gioui.org/f32"
+	"gioui.org/io/input"
+	"gioui.org/io/key"
+	"gioui.org/io/pointer"
+	"gioui.org/io/system"
+	"gioui.org/layout"
+	"gioui.org/op"
+	"gioui.org/widget"
+)
+
+func TestEnumArrowNavigation(t *testing.T) {
+	var (
+		r    input.Router
+		enum widget.Enum
+	)
+	gtx := layout.Context{
+		Ops:    new(op.Ops),
+		Source: r.Source(),
+	}
+	options := []string{"a", "b", "c"}
+	layoutOptions := func() {
+		for i, opt := range options {
+			off := op.Offset(image.Pt(0, i*100)).Push(gtx.Ops)
+			enum.Layout(gtx, opt, func(gtx layout.Context) layout.Dimensions {
+				return layout.Dimensions{Size: image.Pt(100, 100)}
+			})
+			off.Pop()
+		}
+	}
+	frame := func() {
+		gtx.Reset()
+		layoutOptions()
+		r.Frame(gtx.Ops)
+	}
+	frame()
+
+	// Click the first option to select and focus it.
+	r.Queue(
+		pointer.Event{Source: pointer.Mouse, Kind: pointer.Press, Position: f32.Pt(50, 50)},
+		pointer.Event{Source: pointer.Mouse, Kind: pointer.Release, Position: f32.Pt(50, 50)},
+	)
+	frame()
+	if enum.Value != "a" {
+		t.Fatalf("click did not select the first option: %q", enum.Value)
+	}
+
+	press := func(name key.Name) {
+		r.Queue(
+			key.Event{Name: name, State: key.Press},
+			key.Event{Name: name, State: key.Release},
+		)
+		frame()
+	}
+
+	press(key.NameDownArrow)
+	if enum.Value != "b" {
+		t.Fatalf("down arrow did not move to the second option: %q", enum.Value)
+	}
+	press(key.NameDownArrow)
+	if enum.Value != "c" {
+		t.Fatalf("down arrow did not move to the third option: %q", enum.Value)
+	}
+	press(key.NameDownArrow)
+	if enum.Value != "a" {
+		t.Fatalf("down arrow did not wrap around to the first option: %q", enum.Value)
+	}
+	press(key.NameEnd)
+	if enum.Value != "c" {
+		t.Fatalf("end did not move to the last option: %q", enum.Value)
+	}
+	press(key.NameHome)
+	if enum.Value != "a" {
+		t.Fatalf("home did not move to the first option: %q", enum.Value)
+	}
+	press(key.NameUpArrow)
+	if enum.Value != "c" {
+		t.Fatalf("up arrow did not wrap around to the last option: %q", enum.Value)
+	}
+	press(key.NameHome)
+	if enum.Value != "a" {
+		t.Fatalf("home did not move to the first option: %q", enum.Value)
+	}
+
+	// Under a right-to-left locale, left and right swap relative to LTR.
+	gtx.Locale.Direction = system.RTL
+	press(key.NameLeftArrow)
+	if enum.Value != "b" {
+		t.Fatalf("left arrow under RTL did not move to the second option: %q", enum.Value)
+	}
+	press(key.NameRightArrow)
+	if enum.Value != "a" {
+		t.Fatalf("right arrow under RTL did not move back to the first option: %q", enum.Value)
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"image/draw"
 
 	"golang.org/x/exp/shiny/iconvg"
+	ximgdraw "golang.org/x/image/draw"
 
 	"gioui.org/internal/f32color"
 	"gioui.org/layout"
@@ -15,9 +16,78 @@ import (
 	"gioui.org/unit"
 )
 
+// IconSource rasterizes an icon into a tinted bitmap of a given size.
+// Icon uses it to defer rasterization until the size and color it's drawn
+// at are known, and to share that work across Icon values through
+// iconCache. Implementations must be comparable, since iconCache keys on
+// them directly; wrap any non-comparable state (such as a []byte or
+// image.Image) behind a pointer, as iconVGSource and imageSource do.
+type IconSource interface {
+	// Rasterize draws the icon size.X pixels wide, with tint as the icon's
+	// foreground color. The SVG and raster image sources fill size.X by
+	// size.Y; the IconVG source keeps the icon's own aspect ratio and takes
+	// the image height from it rather than from size.Y.
+	Rasterize(size image.Point, tint color.NRGBA) *image.RGBA
+}
+
+// iconVGSource rasterizes IconVG data, the encoding Icon has always
+// supported. It's a pointer so that two Icon values decoding the same
+// bytes still compare unequal as iconCache keys unless they share one
+// *iconVGSource, the same way two distinct *image.RGBA icons do.
+type iconVGSource struct {
+	data []byte
+}
+
+func (src *iconVGSource) Rasterize(size image.Point, tint color.NRGBA) *image.RGBA {
+	m, _ := iconvg.DecodeMetadata(src.data)
+	dx, dy := m.ViewBox.AspectRatio()
+	img := image.NewRGBA(image.Rectangle{Max: image.Point{X: size.X, Y: int(float32(size.X) * dy / dx)}})
+	var ico iconvg.Rasterizer
+	ico.SetDstImage(img, img.Bounds(), draw.Src)
+	m.Palette[0] = f32color.NRGBAToLinearRGBA(tint)
+	iconvg.Decode(&ico, src.data, &iconvg.DecodeOptions{
+		Palette: &m.Palette,
+	})
+	return img
+}
+
+// imageSource rasterizes a raster icon asset (a PNG or JPEG silhouette,
+// typically) by scaling it to size and recoloring it: the source's alpha
+// channel becomes the icon's coverage mask, and tint is composited into
+// that mask in linear space, the same as IconVG and SVG sources.
+type imageSource struct {
+	img image.Image
+}
+
+func (src *imageSource) Rasterize(size image.Point, tint color.NRGBA) *image.RGBA {
+	scaled := image.NewRGBA(image.Rectangle{Max: size})
+	ximgdraw.ApproxBiLinear.Scale(scaled, scaled.Bounds(), src.img, src.img.Bounds(), ximgdraw.Src, nil)
+	t := f32color.NRGBAToLinearRGBA(tint)
+	out := image.NewRGBA(image.Rectangle{Max: size})
+	for y := 0; y < size.Y; y++ {
+		for x := 0; x < size.X; x++ {
+			_, _, _, a := scaled.RGBAAt(x, y).RGBA()
+			out.SetRGBA(x, y, color.RGBA{
+				R: uint8(uint32(t.R) * a / 0xffff),
+				G: uint8(uint32(t.G) * a / 0xffff),
+				B: uint8(uint32(t.B) * a / 0xffff),
+				A: uint8(uint32(t.A) * a / 0xffff),
+			})
+		}
+	}
+	return out
+}
+
+// NewImageIcon returns an Icon that rasterizes img, a pre-rendered
+// silhouette such as a decoded PNG or JPEG, recoloring it to Color on
+// every draw instead of IconVG's vector redraw.
+func NewImageIcon(img image.Image) *Icon {
+	return &Icon{src: &imageSource{img: img}, Color: color.NRGBA{A: 0xff}}
+}
+
 type Icon struct {
 	Color color.NRGBA
-	src   []byte
+	src   IconSource
 	// Cached values.
 	op       paint.ImageOp
 	imgSize  int
@@ -30,7 +100,7 @@ func NewIcon(data []byte) (*Icon, error) {
 	if err != nil {
 		return nil, err
 	}
-	return &Icon{src: data, Color: color.NRGBA{A: 0xff}}, nil
+	return &Icon{src: &iconVGSource{data: data}, Color: color.NRGBA{A: 0xff}}, nil
 }
 
 func (ic *Icon) Layout(gtx layout.Context, sz unit.Value) layout.Dimensions {
@@ -46,17 +116,14 @@ func (ic *Icon) image(sz int) paint.ImageOp {
 	if sz == ic.imgSize && ic.Color == ic.imgColor {
 		return ic.op
 	}
-	m, _ := iconvg.DecodeMetadata(ic.src)
-	dx, dy := m.ViewBox.AspectRatio()
-	img := image.NewRGBA(image.Rectangle{Max: image.Point{X: sz, Y: int(float32(sz) * dy / dx)}})
-	var ico iconvg.Rasterizer
-	ico.SetDstImage(img, img.Bounds(), draw.Src)
-	m.Palette[0] = f32color.NRGBAToLinearRGBA(ic.Color)
-	iconvg.Decode(&ico, ic.src, &iconvg.DecodeOptions{
-		Palette: &m.Palette,
-	})
+	if op, ok := iconCache.get(ic.src, sz, ic.Color); ok {
+		ic.op, ic.imgSize, ic.imgColor = op, sz, ic.Color
+		return ic.op
+	}
+	img := ic.src.Rasterize(image.Pt(sz, sz), ic.Color)
 	ic.op = paint.NewImageOp(img)
 	ic.imgSize = sz
 	ic.imgColor = ic.Color
+	iconCache.put(ic.src, sz, ic.Color, ic.op)
 	return ic.op
 }
@@ -0,0 +1,74 @@
+// SPDX-License-Identifier: Unlicense OR MIT
+
+package widget
+
+import (
+	"container/list"
+	"image/color"
+
+	"gioui.org/op/paint"
+)
+
+// maxIconCacheSize bounds iconCache: enough for a handful of icon sets
+// drawn at a few sizes and a couple of theme colors each, without
+// growing unbounded in an app that generates many short-lived Icon
+// sources.
+const maxIconCacheSize = 256
+
+// iconRasterCache is an LRU of rasterized icon bitmaps keyed on the
+// (source, size, color) triple that produced them, so that resizing a
+// layout or flipping a theme's Color doesn't re-rasterize every Icon
+// sharing a source every frame. IconSource implementations must be
+// comparable (typically by being pointers) to be usable as a key.
+type iconRasterCache struct {
+	limit   int
+	entries map[iconCacheKey]*list.Element
+	order   *list.List // front is most recently used
+}
+
+type iconCacheKey struct {
+	src   IconSource
+	size  int
+	color color.NRGBA
+}
+
+type iconCacheEntry struct {
+	key iconCacheKey
+	op  paint.ImageOp
+}
+
+var iconCache = newIconRasterCache(maxIconCacheSize)
+
+func newIconRasterCache(limit int) *iconRasterCache {
+	return &iconRasterCache{
+		limit:   limit,
+		entries: make(map[iconCacheKey]*list.Element),
+		order:   list.New(),
+	}
+}
+
+func (c *iconRasterCache) get(src IconSource, size int, col color.NRGBA) (paint.ImageOp, bool) {
+	key := iconCacheKey{src, size, col}
+	e, ok := c.entries[key]
+	if !ok {
+		return paint.ImageOp{}, false
+	}
+	c.order.MoveToFront(e)
+	return e.Value.(*iconCacheEntry).op, true
+}
+
+func (c *iconRasterCache) put(src IconSource, size int, col color.NRGBA, op paint.ImageOp) {
+	key := iconCacheKey{src, size, col}
+	if e, ok := c.entries[key]; ok {
+		e.Value.(*iconCacheEntry).op = op
+		c.order.MoveToFront(e)
+		return
+	}
+	e := c.order.PushFront(&iconCacheEntry{key: key, op: op})
+	c.entries[key] = e
+	for len(c.entries) > c.limit {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*iconCacheEntry).key)
+	}
+}
@@ -0,0 +1,566 @@
+// SPDX-License-Identifier: Unlicense OR MIT
+
+package widget
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"math"
+	"strconv"
+	"strings"
+
+	"golang.org/x/image/vector"
+
+	"gioui.org/internal/f32color"
+)
+
+// svgIcon is an IconSource rasterizing a minimal SVG subset: path data
+// (M/L/H/V/C/Q/Z, relative and absolute) plus rect shapes, filled with
+// a solid color or a linearGradient, and stroked with a solid color.
+// Anything else — circles and other shapes, text, filters, clipPaths,
+// nested documents, radial gradients — is ignored, so an icon using
+// unsupported features still renders whatever part of it this package
+// understands rather than failing outright.
+type svgIcon struct {
+	viewBox  [4]float64
+	elements []svgElement
+	grads    map[string]svgGradient
+}
+
+type svgElement struct {
+	segs       []svgSeg
+	fill       svgPaint
+	hasFill    bool
+	stroke     svgPaint
+	hasStroke  bool
+	strokeWide float64
+}
+
+// svgSeg is one flattened line segment of a path, already in viewBox
+// coordinates.
+type svgSeg struct {
+	x0, y0, x1, y1 float64
+}
+
+type svgPaint struct {
+	color    color.NRGBA
+	gradient string // non-empty selects grads[gradient] instead of color
+}
+
+type svgGradient struct {
+	x1, y1, x2, y2 float64
+	stops          []svgStop
+}
+
+type svgStop struct {
+	offset float64
+	color  color.NRGBA
+}
+
+// NewSVGIcon parses an SVG document into an Icon. Only the path/rect,
+// fill, stroke and linearGradient subset documented on svgIcon is
+// understood.
+func NewSVGIcon(data []byte) (*Icon, error) {
+	doc, err := parseSVG(data)
+	if err != nil {
+		return nil, err
+	}
+	return &Icon{src: doc, Color: color.NRGBA{A: 0xff}}, nil
+}
+
+func parseSVG(data []byte) (*svgIcon, error) {
+	var root struct {
+		ViewBox string `xml:"viewBox,attr"`
+		Defs    struct {
+			LinearGradients []struct {
+				ID    string `xml:"id,attr"`
+				X1    string `xml:"x1,attr"`
+				Y1    string `xml:"y1,attr"`
+				X2    string `xml:"x2,attr"`
+				Y2    string `xml:"y2,attr"`
+				Stops []struct {
+					Offset string `xml:"offset,attr"`
+					Color  string `xml:"stop-color,attr"`
+				} `xml:"stop"`
+			} `xml:"linearGradient"`
+		} `xml:"defs"`
+		Paths []struct {
+			D      string `xml:"d,attr"`
+			Fill   string `xml:"fill,attr"`
+			Stroke string `xml:"stroke,attr"`
+			Width  string `xml:"stroke-width,attr"`
+		} `xml:"path"`
+		Rects []struct {
+			X      string `xml:"x,attr"`
+			Y      string `xml:"y,attr"`
+			W      string `xml:"width,attr"`
+			H      string `xml:"height,attr"`
+			Fill   string `xml:"fill,attr"`
+			Stroke string `xml:"stroke,attr"`
+			Width  string `xml:"stroke-width,attr"`
+		} `xml:"rect"`
+	}
+	if err := xml.Unmarshal(data, &root); err != nil {
+		return nil, fmt.Errorf("widget: invalid SVG: %w", err)
+	}
+	doc := &svgIcon{grads: make(map[string]svgGradient)}
+	vb := strings.Fields(root.ViewBox)
+	if len(vb) != 4 {
+		return nil, errors.New("widget: SVG has no viewBox")
+	}
+	for i, f := range vb {
+		v, err := strconv.ParseFloat(f, 64)
+		if err != nil {
+			return nil, fmt.Errorf("widget: invalid SVG viewBox: %w", err)
+		}
+		doc.viewBox[i] = v
+	}
+	for _, g := range root.Defs.LinearGradients {
+		grad := svgGradient{
+			x1: parseFloat(g.X1), y1: parseFloat(g.Y1),
+			x2: parseFloat(g.X2, 1), y2: parseFloat(g.Y2),
+		}
+		for _, s := range g.Stops {
+			grad.stops = append(grad.stops, svgStop{
+				offset: parseFloat(s.Offset),
+				color:  parseSVGColor(s.Color),
+			})
+		}
+		doc.grads[g.ID] = grad
+	}
+	for _, p := range root.Paths {
+		segs, err := flattenPath(p.D)
+		if err != nil {
+			return nil, err
+		}
+		doc.elements = append(doc.elements, newSVGElement(segs, p.Fill, p.Stroke, p.Width))
+	}
+	for _, r := range root.Rects {
+		x, y := parseFloat(r.X), parseFloat(r.Y)
+		w, h := parseFloat(r.W), parseFloat(r.H)
+		segs := []svgSeg{
+			{x, y, x + w, y}, {x + w, y, x + w, y + h},
+			{x + w, y + h, x, y + h}, {x, y + h, x, y},
+		}
+		doc.elements = append(doc.elements, newSVGElement(segs, r.Fill, r.Stroke, r.Width))
+	}
+	return doc, nil
+}
+
+func newSVGElement(segs []svgSeg, fill, stroke, width string) svgElement {
+	el := svgElement{segs: segs, strokeWide: parseFloat(width, 1)}
+	if p, ok := parseSVGPaint(fill); ok {
+		el.fill, el.hasFill = p, true
+	} else if fill == "" {
+		// Fill defaults to black per the SVG spec; Icon's tint color
+		// stands in for "black" here, so an unset fill still paints.
+		el.fill, el.hasFill = svgPaint{color: color.NRGBA{A: 0xff}}, true
+	}
+	if p, ok := parseSVGPaint(stroke); ok {
+		el.stroke, el.hasStroke = p, true
+	}
+	return el
+}
+
+func parseSVGPaint(v string) (svgPaint, bool) {
+	switch {
+	case v == "" || v == "none":
+		return svgPaint{}, false
+	case strings.HasPrefix(v, "url(#") && strings.HasSuffix(v, ")"):
+		return svgPaint{gradient: v[len("url(#") : len(v)-1]}, true
+	default:
+		return svgPaint{color: parseSVGColor(v)}, true
+	}
+}
+
+func parseSVGColor(v string) color.NRGBA {
+	v = strings.TrimSpace(v)
+	if !strings.HasPrefix(v, "#") {
+		// Named colors beyond "black"/"white" aren't worth a lookup
+		// table for icon artwork; treat anything else as opaque black.
+		if v == "white" {
+			return color.NRGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xff}
+		}
+		return color.NRGBA{A: 0xff}
+	}
+	v = v[1:]
+	if len(v) == 3 {
+		v = string([]byte{v[0], v[0], v[1], v[1], v[2], v[2]})
+	}
+	n, err := strconv.ParseUint(v, 16, 32)
+	if err != nil || len(v) != 6 {
+		return color.NRGBA{A: 0xff}
+	}
+	return color.NRGBA{R: uint8(n >> 16), G: uint8(n >> 8), B: uint8(n), A: 0xff}
+}
+
+func parseFloat(v string, def ...float64) float64 {
+	if v == "" {
+		if len(def) > 0 {
+			return def[0]
+		}
+		return 0
+	}
+	f, err := strconv.ParseFloat(strings.TrimSuffix(v, "px"), 64)
+	if err != nil {
+		if len(def) > 0 {
+			return def[0]
+		}
+		return 0
+	}
+	return f
+}
+
+// Rasterize implements IconSource. It maps the SVG's viewBox onto size,
+// fills and strokes every element with vector.Rasterizer, and composites
+// the result with tint applied in linear space: tint entirely replaces
+// solid black fills/strokes (the common case for monochrome icon
+// artwork) while gradient stops keep their own hue but are scaled by
+// tint's alpha, so a partly-transparent tint fades the whole icon.
+func (doc *svgIcon) Rasterize(size image.Point, tint color.NRGBA) *image.RGBA {
+	out := image.NewRGBA(image.Rectangle{Max: size})
+	vbw, vbh := doc.viewBox[2], doc.viewBox[3]
+	if vbw == 0 || vbh == 0 {
+		return out
+	}
+	sx, sy := float64(size.X)/vbw, float64(size.Y)/vbh
+	var rast vector.Rasterizer
+	rast.Reset(size.X, size.Y)
+	for _, el := range doc.elements {
+		if el.hasFill {
+			doc.rasterize(&rast, el.segs, sx, sy)
+			doc.paint(out, &rast, size, el.fill, tint)
+		}
+		if el.hasStroke {
+			doc.rasterize(&rast, strokeSegs(el.segs, el.strokeWide), sx, sy)
+			doc.paint(out, &rast, size, el.stroke, tint)
+		}
+	}
+	return out
+}
+
+func (doc *svgIcon) rasterize(rast *vector.Rasterizer, segs []svgSeg, sx, sy float64) {
+	rast.Reset(rast.Size().X, rast.Size().Y)
+	var prevX, prevY float64
+	havePrev := false
+	for _, seg := range segs {
+		// segs is a flattened path: most consecutive entries continue
+		// the same subpath, but strokeSegs emits disjoint quads, so a
+		// gap from the last segment's endpoint starts a new subpath.
+		if !havePrev || seg.x0 != prevX || seg.y0 != prevY {
+			rast.ClosePath()
+			rast.MoveTo(float32(seg.x0*sx), float32(seg.y0*sy))
+		}
+		rast.LineTo(float32(seg.x1*sx), float32(seg.y1*sy))
+		prevX, prevY = seg.x1, seg.y1
+		havePrev = true
+	}
+	rast.ClosePath()
+}
+
+func (doc *svgIcon) paint(out *image.RGBA, rast *vector.Rasterizer, size image.Point, p svgPaint, tint color.NRGBA) {
+	var src image.Image
+	if grad, ok := doc.grads[p.gradient]; ok {
+		src = doc.gradientImage(grad, size, tint)
+	} else {
+		c := p.color
+		if c == (color.NRGBA{A: 0xff}) {
+			// Black fills/strokes are redrawn in the icon's tint color,
+			// the same convention IconVG's palette[0] uses.
+			c = tint
+		} else {
+			c.A = uint8(uint32(c.A) * uint32(tint.A) / 0xff)
+		}
+		src = image.NewUniform(f32color.NRGBAToLinearRGBA(c))
+	}
+	rast.Draw(out, out.Bounds(), src, image.Point{})
+}
+
+func (doc *svgIcon) gradientImage(grad svgGradient, size image.Point, tint color.NRGBA) image.Image {
+	img := image.NewRGBA(image.Rectangle{Max: size})
+	vbw, vbh := doc.viewBox[2], doc.viewBox[3]
+	dx, dy := grad.x2-grad.x1, grad.y2-grad.y1
+	length := dx*dx + dy*dy
+	for y := 0; y < size.Y; y++ {
+		for x := 0; x < size.X; x++ {
+			// Project (x, y) onto the gradient vector to find its stop
+			// position; degenerate (zero-length) gradients fall back to
+			// the first stop everywhere.
+			t := 0.0
+			if length > 0 {
+				px, py := float64(x)/float64(size.X)*vbw-grad.x1, float64(y)/float64(size.Y)*vbh-grad.y1
+				t = (px*dx + py*dy) / length
+			}
+			c := gradientAt(grad, t)
+			c.A = uint8(uint32(c.A) * uint32(tint.A) / 0xff)
+			img.SetRGBA(x, y, f32color.NRGBAToLinearRGBA(c))
+		}
+	}
+	return img
+}
+
+func gradientAt(grad svgGradient, t float64) color.NRGBA {
+	if len(grad.stops) == 0 {
+		return color.NRGBA{A: 0xff}
+	}
+	if t <= grad.stops[0].offset {
+		return grad.stops[0].color
+	}
+	last := grad.stops[len(grad.stops)-1]
+	if t >= last.offset {
+		return last.color
+	}
+	for i := 1; i < len(grad.stops); i++ {
+		a, b := grad.stops[i-1], grad.stops[i]
+		if t <= b.offset {
+			span := b.offset - a.offset
+			if span <= 0 {
+				return b.color
+			}
+			f := (t - a.offset) / span
+			return color.NRGBA{
+				R: lerp8(a.color.R, b.color.R, f),
+				G: lerp8(a.color.G, b.color.G, f),
+				B: lerp8(a.color.B, b.color.B, f),
+				A: lerp8(a.color.A, b.color.A, f),
+			}
+		}
+	}
+	return last.color
+}
+
+func lerp8(a, b uint8, f float64) uint8 {
+	return uint8(float64(a) + (float64(b)-float64(a))*f)
+}
+
+// strokeSegs approximates a stroke by expanding each segment into a thin
+// quadrilateral of the given width, rather than implementing proper
+// mitered/rounded joins: plenty for the short, mostly-straight strokes
+// icon artwork tends to use, not a general stroker.
+func strokeSegs(segs []svgSeg, width float64) []svgSeg {
+	if width <= 0 {
+		width = 1
+	}
+	half := width / 2
+	out := make([]svgSeg, 0, len(segs)*4)
+	for _, s := range segs {
+		dx, dy := s.x1-s.x0, s.y1-s.y0
+		length := hypot(dx, dy)
+		if length == 0 {
+			continue
+		}
+		nx, ny := -dy/length*half, dx/length*half
+		x0, y0 := s.x0+nx, s.y0+ny
+		x1, y1 := s.x1+nx, s.y1+ny
+		x2, y2 := s.x1-nx, s.y1-ny
+		x3, y3 := s.x0-nx, s.y0-ny
+		out = append(out,
+			svgSeg{x0, y0, x1, y1}, svgSeg{x1, y1, x2, y2},
+			svgSeg{x2, y2, x3, y3}, svgSeg{x3, y3, x0, y0},
+		)
+	}
+	return out
+}
+
+func hypot(dx, dy float64) float64 {
+	return math.Sqrt(dx*dx + dy*dy)
+}
+
+// flattenPath parses an SVG path "d" attribute and flattens it to line
+// segments in viewBox coordinates. It understands the M/L/H/V/C/Q/Z
+// commands, both absolute and relative (lowercase). A command applies
+// again to further coordinates that follow it without a new letter, with
+// pairs after M or m read as L or l. S/T smooth curves and arcs (A) are
+// not supported.
+func flattenPath(d string) ([]svgSeg, error) {
+	toks := tokenizePath(d)
+	var segs []svgSeg
+	var cx, cy, startX, startY float64
+	i := 0
+	next := func() (float64, error) {
+		if i >= len(toks) {
+			return 0, errors.New("widget: truncated SVG path")
+		}
+		v, err := strconv.ParseFloat(toks[i], 64)
+		i++
+		return v, err
+	}
+	line := func(x, y float64) {
+		segs = append(segs, svgSeg{cx, cy, x, y})
+		cx, cy = x, y
+	}
+	cubic := func(x1, y1, x2, y2, x, y float64) {
+		const n = 16
+		x0, y0 := cx, cy
+		for s := 1; s <= n; s++ {
+			t := float64(s) / n
+			mt := 1 - t
+			bx := mt*mt*mt*x0 + 3*mt*mt*t*x1 + 3*mt*t*t*x2 + t*t*t*x
+			by := mt*mt*mt*y0 + 3*mt*mt*t*y1 + 3*mt*t*t*y2 + t*t*t*y
+			segs = append(segs, svgSeg{cx, cy, bx, by})
+			cx, cy = bx, by
+		}
+	}
+	quad := func(x1, y1, x, y float64) {
+		const n = 12
+		x0, y0 := cx, cy
+		for s := 1; s <= n; s++ {
+			t := float64(s) / n
+			mt := 1 - t
+			bx := mt*mt*x0 + 2*mt*t*x1 + t*t*x
+			by := mt*mt*y0 + 2*mt*t*y1 + t*t*y
+			segs = append(segs, svgSeg{cx, cy, bx, by})
+			cx, cy = bx, by
+		}
+	}
+	var cmd byte
+	for i < len(toks) {
+		if len(toks[i]) == 1 && isPathCommand(toks[i][0]) {
+			cmd = toks[i][0]
+			i++
+		}
+		rel := cmd >= 'a' && cmd <= 'z'
+		switch cmd {
+		case 'M', 'm':
+			x, err := next()
+			if err != nil {
+				return nil, err
+			}
+			y, err := next()
+			if err != nil {
+				return nil, err
+			}
+			if rel {
+				x, y = cx+x, cy+y
+			}
+			cx, cy = x, y
+			startX, startY = x, y
+			// A subsequent coordinate pair with no command letter is an
+			// implicit lineto, per the SVG path grammar.
+			if rel {
+				cmd = 'l'
+			} else {
+				cmd = 'L'
+			}
+		case 'L', 'l':
+			x, err := next()
+			if err != nil {
+				return nil, err
+			}
+			y, err := next()
+			if err != nil {
+				return nil, err
+			}
+			if rel {
+				x, y = cx+x, cy+y
+			}
+			line(x, y)
+		case 'H', 'h':
+			x, err := next()
+			if err != nil {
+				return nil, err
+			}
+			if rel {
+				x = cx + x
+			}
+			line(x, cy)
+		case 'V', 'v':
+			y, err := next()
+			if err != nil {
+				return nil, err
+			}
+			if rel {
+				y = cy + y
+			}
+			line(cx, y)
+		case 'C', 'c':
+			vals := make([]float64, 6)
+			for k := range vals {
+				v, err := next()
+				if err != nil {
+					return nil, err
+				}
+				vals[k] = v
+			}
+			x1, y1, x2, y2, x, y := vals[0], vals[1], vals[2], vals[3], vals[4], vals[5]
+			if rel {
+				x1, y1, x2, y2, x, y = cx+x1, cy+y1, cx+x2, cy+y2, cx+x, cy+y
+			}
+			cubic(x1, y1, x2, y2, x, y)
+		case 'Q', 'q':
+			vals := make([]float64, 4)
+			for k := range vals {
+				v, err := next()
+				if err != nil {
+					return nil, err
+				}
+				vals[k] = v
+			}
+			x1, y1, x, y := vals[0], vals[1], vals[2], vals[3]
+			if rel {
+				x1, y1, x, y = cx+x1, cy+y1, cx+x, cy+y
+			}
+			quad(x1, y1, x, y)
+		case 'Z', 'z':
+			line(startX, startY)
+		default:
+			return nil, fmt.Errorf("widget: unsupported SVG path command %q", string(cmd))
+		}
+	}
+	return segs, nil
+}
+
+func isPathCommand(b byte) bool {
+	switch b {
+	case 'M', 'm', 'L', 'l', 'H', 'h', 'V', 'v', 'C', 'c', 'Q', 'q', 'Z', 'z':
+		return true
+	}
+	return false
+}
+
+// tokenizePath splits an SVG path's "d" attribute into command letters
+// and numbers, the two token kinds flattenPath consumes. SVG allows
+// numbers to run together without separators (e.g. "1.5.5" is "1.5"
+// then ".5", and "-1-2" is "-1" then "-2"), so token boundaries are
+// found by scanning rather than splitting on whitespace/commas alone.
+func tokenizePath(d string) []string {
+	var toks []string
+	i := 0
+	for i < len(d) {
+		c := d[i]
+		switch {
+		case c == ' ' || c == ',' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case isPathCommand(c):
+			toks = append(toks, string(c))
+			i++
+		case c == '-' || c == '+' || c == '.' || (c >= '0' && c <= '9'):
+			j := i + 1
+			seenDot := c == '.'
+		scanNumber:
+			for j < len(d) {
+				switch {
+				case d[j] >= '0' && d[j] <= '9':
+					j++
+				case d[j] == '.' && !seenDot:
+					seenDot = true
+					j++
+				case (d[j] == 'e' || d[j] == 'E') && j+1 < len(d):
+					j++
+				case (d[j] == '-' || d[j] == '+') && j > 0 && (d[j-1] == 'e' || d[j-1] == 'E'):
+					j++
+				default:
+					break scanNumber
+				}
+			}
+			toks = append(toks, d[i:j])
+			i = j
+		default:
+			i++
+		}
+	}
+	return toks
+}
@@ -26,6 +26,14 @@ type Label struct {
 	// Truncator is the text that will be shown at the end of the final
 	// line if MaxLines is exceeded. Defaults to "…" if empty.
 	Truncator string
+	// LineBreaker, if set, re-justifies each line the shaper already
+	// wrapped so that inter-word glue fills the full line width, instead
+	// of leaving the greedy shaper's ragged right edge. This is glue
+	// justification only: LineBreaker never chooses where a paragraph
+	// wraps, that decision stays with text.Shaper's own greedy breaker.
+	// A line of more than 32 glyphs is painted in several pieces and is
+	// not justified. See the KnuthPlassBreaker doc comment in linebreak.go.
+	LineBreaker *KnuthPlassBreaker
 }
 
 // Layout the label with the given shaper, font, size, text, and material.
@@ -42,9 +50,10 @@ func (l Label) Layout(gtx layout.Context, lt *text.Shaper, font text.Font, size
 	m := op.Record(gtx.Ops)
 	viewport := image.Rectangle{Max: cs.Max}
 	it := textIterator{
-		viewport: viewport,
-		maxLines: l.MaxLines,
-		material: textMaterial,
+		viewport:    viewport,
+		maxLines:    l.MaxLines,
+		material:    textMaterial,
+		lineBreaker: l.LineBreaker,
 	}
 	semantic.LabelOp(txt).Add(gtx.Ops)
 	var glyphs [32]text.Glyph
@@ -67,6 +76,57 @@ func (l Label) Layout(gtx layout.Context, lt *text.Shaper, font text.Font, size
 	return dims
 }
 
+// justify stretches or shrinks the inter-word glue in line so it fills the
+// iterator's viewport width, using it.lineBreaker. line is always a line
+// the shaper's own greedy breaker already chose to end here; justify only
+// adjusts the glue within it; it does not revisit where the line itself
+// ended. It leaves line alone if there is no glue to adjust, if line ends
+// a paragraph (a short final line should stay ragged, not stretched to
+// fill the column), or if no within-tolerance adjustment exists.
+func (it *textIterator) justify(line []text.Glyph) {
+	if it.lineBreaker == nil || len(line) == 0 {
+		return
+	}
+	if line[len(line)-1].Flags&text.FlagParagraphBreak != 0 {
+		return
+	}
+	items := make([]BreakItem, len(line))
+	for i, g := range line {
+		if isGlue(g) {
+			items[i] = BreakItem{Kind: BreakGlue, Width: g.Advance, Stretch: g.Advance / 2, Shrink: g.Advance / 3}
+		} else {
+			items[i] = BreakItem{Kind: BreakBox, Width: g.Advance}
+		}
+	}
+	width, stretch, shrink := lineMetrics(items)
+	target := fixed.I(it.viewport.Dx())
+	ratio, overfull := adjustmentRatio(target, width, stretch, shrink)
+	if overfull || ratio < -1 || ratio > it.lineBreaker.tolerance() {
+		return
+	}
+	var shift fixed.Int26_6
+	for i := range line {
+		line[i].X += shift
+		if items[i].Kind != BreakGlue {
+			continue
+		}
+		delta := items[i].Stretch
+		if ratio < 0 {
+			delta = items[i].Shrink
+		}
+		shift += fixed.Int26_6(ratio * float64(delta))
+	}
+}
+
+// isGlue reports whether g looks like inter-word whitespace: a glyph with
+// a non-zero advance but no ink. This is a heuristic, since text.Glyph
+// does not carry the original rune; it holds for ordinary space characters
+// in every font tested, while zero-advance marks (which also lack ink)
+// are correctly excluded.
+func isGlue(g text.Glyph) bool {
+	return g.Advance > 0 && g.Bounds.Min == g.Bounds.Max
+}
+
 func r2p(r clip.Rect) clip.Op {
 	return clip.Stroke{Path: r.Path(), Width: 1}.Op()
 }
@@ -98,6 +158,12 @@ type textIterator struct {
 	first bool
 	// baseline tracks the location of the first line of text's baseline.
 	baseline int
+	// lineBreaker, if set, is used to justify each completed line; see
+	// Label.LineBreaker.
+	lineBreaker *KnuthPlassBreaker
+	// lineSplit records that part of the current line was already painted
+	// because the glyph buffer filled up.
+	lineSplit bool
 }
 
 // processGlyph checks whether the glyph is visible within the iterator's configured
@@ -159,6 +225,11 @@ func (it *textIterator) paintGlyph(gtx layout.Context, shaper *text.Shaper, glyp
 		line = append(line, glyph)
 	}
 	if glyph.Flags&text.FlagLineBreak != 0 || cap(line)-len(line) == 0 || !visibleOrBefore {
+		// Only a line held whole in the buffer is justified.
+		if glyph.Flags&text.FlagLineBreak != 0 && !it.lineSplit {
+			it.justify(line)
+		}
+		it.lineSplit = glyph.Flags&text.FlagLineBreak == 0
 		t := op.Offset(it.lineOff).Push(gtx.Ops)
 		path := shaper.Shape(line)
 		outline := clip.Outline{Path: path}.Op().Push(gtx.Ops)
@@ -168,6 +239,9 @@ func (it *textIterator) paintGlyph(gtx layout.Context, shaper *text.Shaper, glyp
 		if call := shaper.Bitmaps(line); call != (op.CallOp{}) {
 			call.Add(gtx.Ops)
 		}
+		if call := shaper.Layers(line); call != (op.CallOp{}) {
+			call.Add(gtx.Ops)
+		}
 		t.Pop()
 		line = line[:0]
 	}
@@ -0,0 +1,283 @@
+// SPDX-License-Identifier: Unlicense OR MIT
+
+package widget
+
+import (
+	"golang.org/x/image/math/fixed"
+)
+
+// BreakKind classifies an item in a Knuth-Plass break sequence.
+type BreakKind uint8
+
+const (
+	// BreakBox is a glyph cluster: its Width contributes to a line but it
+	// is never itself a candidate break point.
+	BreakBox BreakKind = iota
+	// BreakGlue is inter-word whitespace: a candidate break point whose
+	// Width may be stretched (up to Stretch) or shrunk (up to Shrink) to
+	// make a justified line fit its target width exactly.
+	BreakGlue
+	// BreakPenalty is an explicit candidate break point, such as a
+	// hyphenation opportunity or a mandatory paragraph end. PenaltyCost is
+	// added to the demerits of any line ending here; a cost of
+	// PenaltyForbidden rules the break out entirely, and a cost at or
+	// below PenaltyForced requires it.
+	BreakPenalty
+)
+
+// Forced and forbidden penalty costs, following TeX's convention of using
+// the extremes of the range to mean "always" and "never".
+const (
+	PenaltyForced    = -10000
+	PenaltyForbidden = 10000
+)
+
+// BreakItem is one element of the box-glue-penalty stream that
+// KnuthPlassBreaker.Break consumes. A paragraph is represented as a slice of
+// BreakItems in reading order, and must end with a BreakPenalty item whose
+// PenaltyCost is at most PenaltyForced to anchor the final line.
+type BreakItem struct {
+	Kind BreakKind
+	// Width is the nominal width of a box or glue item.
+	Width fixed.Int26_6
+	// Stretch and Shrink bound how far Width may be adjusted when
+	// justifying a glue item.
+	Stretch, Shrink fixed.Int26_6
+	// PenaltyCost is the cost of breaking at a BreakPenalty item.
+	PenaltyCost int
+	// Flagged marks a penalty as a hyphenation point; two consecutive
+	// flagged breaks are discouraged, matching TeX's \doublehyphendemerits.
+	Flagged bool
+}
+
+// KnuthPlassBreaker chooses line breaks for a paragraph of BreakItems
+// using the total-fit algorithm used by TeX-family typesetting engines:
+// every feasible break point is scored by the badness of the line it
+// ends plus demerits for consecutive hyphens and for adjacent lines of
+// very different tightness, and the dynamic program keeps only the
+// lowest-demerit path reaching each break point. Break implements this
+// in full and is unit-tested on its own.
+//
+// Label.LineBreaker does not call Break, though: wiring its chosen break
+// indices back into Label.Layout would mean replacing the shaper's own
+// greedy wrap with a second, paragraph-level pass that re-derives line
+// heights and baselines, which this package does not attempt. Label only
+// uses a KnuthPlassBreaker to justify the lines the shaper's greedy
+// breaker already chose, by stretching or shrinking each line's
+// inter-word glue to fill the width; see Label.LineBreaker and
+// textIterator.justify in label.go. Despite the type name, Label never
+// performs Knuth-Plass line breaking today, only this glue justification.
+type KnuthPlassBreaker struct {
+	// Tolerance is the largest adjustment ratio magnitude a line may have
+	// before it is considered infeasible. The zero value defaults to 1,
+	// TeX's \tolerance for a single pass with no emergency stretch.
+	Tolerance float64
+}
+
+// breakpoint is the best-known path reaching a given candidate break.
+type breakpoint struct {
+	item     int // index into items this breakpoint ends a line at, or -1 for the paragraph start
+	line     int // 0-based index of the line ending here
+	fitness  int // TeX fitness class of the line ending here
+	flagged  bool
+	demerits float64
+	prev     *breakpoint
+}
+
+// Break returns the indices into items of the chosen breakpoints: the
+// BreakItem at each returned index ends a line. widthForLine(i) reports the
+// target width of the (0-based) i'th line, allowing ragged shapes such as a
+// narrower first line for a drop cap.
+//
+// Break is a standalone total-fit line breaker: nothing in this package
+// calls it on Label's behalf today, see the KnuthPlassBreaker doc.
+func (k KnuthPlassBreaker) Break(items []BreakItem, widthForLine func(line int) fixed.Int26_6) []int {
+	tolerance := k.Tolerance
+	if tolerance <= 0 {
+		tolerance = 1
+	}
+	width, stretch, shrink := prefixSums(items)
+	active := []*breakpoint{{item: -1, fitness: 1}}
+	var final *breakpoint
+	for i, it := range items {
+		forced := it.Kind == BreakPenalty && it.PenaltyCost <= PenaltyForced
+		if !forced && !isLegalBreak(items, i) {
+			continue
+		}
+		var kept []*breakpoint
+		var best *breakpoint
+		for _, bp := range active {
+			w := width[i] - width[bp.item+1]
+			st := stretch[i] - stretch[bp.item+1]
+			sh := shrink[i] - shrink[bp.item+1]
+			ratio, overfull := adjustmentRatio(widthForLine(bp.line), w, st, sh)
+			if overfull && !forced {
+				// bp can never fit a later break either: drop it.
+				continue
+			}
+			kept = append(kept, bp)
+			if !forced && ratio > tolerance {
+				// Too loose from here, but bp may still work starting a
+				// line later, so it stays in kept above.
+				continue
+			}
+			cost := 0.0
+			if it.Kind == BreakPenalty {
+				cost = float64(it.PenaltyCost)
+			}
+			fitness := fitnessClass(ratio)
+			badness := 100 * cube(abs(ratio))
+			d := bp.demerits + demerits(badness, cost, fitness, bp.fitness, it.Flagged, bp.flagged)
+			if best == nil || d < best.demerits {
+				best = &breakpoint{item: i, line: bp.line + 1, fitness: fitness, flagged: it.Flagged, demerits: d, prev: bp}
+			}
+		}
+		active = kept
+		if forced {
+			// No later line may start before a forced break.
+			active = nil
+		}
+		if best != nil {
+			active = append(active, best)
+			if i == len(items)-1 {
+				final = best
+			}
+		}
+	}
+	if final == nil {
+		return nil
+	}
+	var breaks []int
+	for bp := final; bp != nil && bp.item >= 0; bp = bp.prev {
+		breaks = append(breaks, bp.item)
+	}
+	for l, r := 0, len(breaks)-1; l < r; l, r = l+1, r-1 {
+		breaks[l], breaks[r] = breaks[r], breaks[l]
+	}
+	return breaks
+}
+
+// isLegalBreak reports whether items[i] is a candidate break point: a
+// penalty cheaper than PenaltyForbidden, or glue immediately following a
+// box, per TeX's rule that a paragraph never breaks between two adjacent
+// glue items.
+func isLegalBreak(items []BreakItem, i int) bool {
+	switch items[i].Kind {
+	case BreakPenalty:
+		return items[i].PenaltyCost < PenaltyForbidden
+	case BreakGlue:
+		return i > 0 && items[i-1].Kind == BreakBox
+	default:
+		return false
+	}
+}
+
+// prefixSums returns, for each prefix items[:i], the cumulative box+glue
+// width and the cumulative glue stretch and shrink, so the content between
+// any two breakpoints can be measured in O(1).
+func prefixSums(items []BreakItem) (width, stretch, shrink []fixed.Int26_6) {
+	width = make([]fixed.Int26_6, len(items)+1)
+	stretch = make([]fixed.Int26_6, len(items)+1)
+	shrink = make([]fixed.Int26_6, len(items)+1)
+	for i, it := range items {
+		w, st, sh := width[i], stretch[i], shrink[i]
+		if it.Kind == BreakBox || it.Kind == BreakGlue {
+			w += it.Width
+		}
+		if it.Kind == BreakGlue {
+			st += it.Stretch
+			sh += it.Shrink
+		}
+		width[i+1], stretch[i+1], shrink[i+1] = w, st, sh
+	}
+	return
+}
+
+// adjustmentRatio computes how far the glue between two breakpoints must
+// stretch (positive) or shrink (negative) to make natural width w fill
+// target exactly, along with whether the line is overfull: too wide even
+// after shrinking all its glue to nothing.
+func adjustmentRatio(target, w, stretch, shrink fixed.Int26_6) (ratio float64, overfull bool) {
+	gap := target - w
+	switch {
+	case gap > 0:
+		if stretch == 0 {
+			return 1e6, false
+		}
+		return float64(gap) / float64(stretch), false
+	case gap < 0:
+		if shrink == 0 {
+			return -1e6, true
+		}
+		r := float64(gap) / float64(shrink)
+		return r, r < -1
+	default:
+		return 0, false
+	}
+}
+
+func fitnessClass(ratio float64) int {
+	switch {
+	case ratio < -0.5:
+		return 0 // tight
+	case ratio <= 0.5:
+		return 1 // normal
+	case ratio <= 1:
+		return 2 // loose
+	default:
+		return 3 // very loose
+	}
+}
+
+// demerits scores a line with the given badness and explicit penalty cost,
+// adding TeX-style demerits for consecutive flagged (hyphenated) breaks and
+// for adjacent lines whose fitness classes differ by more than one step.
+func demerits(badness, cost float64, fitness, prevFitness int, flagged, prevFlagged bool) float64 {
+	d := (10 + badness) * (10 + badness)
+	switch {
+	case cost >= 0:
+		d += cost * cost
+	case cost > PenaltyForced:
+		d -= cost * cost
+	}
+	if flagged && prevFlagged {
+		d += 100 * 100
+	}
+	if diff := fitness - prevFitness; diff > 1 || diff < -1 {
+		d += 10 * 10
+	}
+	return d
+}
+
+// tolerance returns k.Tolerance, or the default of 1 if unset.
+func (k *KnuthPlassBreaker) tolerance() float64 {
+	if k.Tolerance <= 0 {
+		return 1
+	}
+	return k.Tolerance
+}
+
+// lineMetrics sums the box and glue widths of items, a single line's worth
+// of BreakItems, along with its total glue stretch and shrink.
+func lineMetrics(items []BreakItem) (width, stretch, shrink fixed.Int26_6) {
+	for _, it := range items {
+		switch it.Kind {
+		case BreakBox:
+			width += it.Width
+		case BreakGlue:
+			width += it.Width
+			stretch += it.Stretch
+			shrink += it.Shrink
+		}
+	}
+	return
+}
+
+func cube(x float64) float64 { return x * x * x }
+
+func abs(x float64) float64 {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
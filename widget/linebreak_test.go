@@ -0,0 +1,64 @@
+// SPDX-License-Identifier: Unlicense OR MIT
+
+package widget
+
+import (
+	"testing"
+
+	"golang.org/x/image/math/fixed"
+)
+
+// words builds a sequence of BreakItems for n words of the given width
+// separated by ordinary interword glue, terminated with a forced break.
+func words(n int, width fixed.Int26_6) []BreakItem {
+	var items []BreakItem
+	for i := 0; i < n; i++ {
+		if i > 0 {
+			items = append(items, BreakItem{Kind: BreakGlue, Width: fixed.I(4), Stretch: fixed.I(2), Shrink: fixed.I(1)})
+		}
+		items = append(items, BreakItem{Kind: BreakBox, Width: width})
+	}
+	items = append(items, BreakItem{Kind: BreakPenalty, PenaltyCost: PenaltyForced})
+	return items
+}
+
+func TestKnuthPlassBreakerFitsOneLine(t *testing.T) {
+	items := words(3, fixed.I(10))
+	var k KnuthPlassBreaker
+	breaks := k.Break(items, func(int) fixed.Int26_6 { return fixed.I(1000) })
+	if len(breaks) != 1 {
+		t.Fatalf("expected a single break ending the paragraph, got %v", breaks)
+	}
+	if breaks[0] != len(items)-1 {
+		t.Fatalf("expected the only break to be the trailing forced penalty, got index %d of %d items", breaks[0], len(items))
+	}
+}
+
+func TestKnuthPlassBreakerWrapsLongParagraph(t *testing.T) {
+	items := words(20, fixed.I(10))
+	var k KnuthPlassBreaker
+	breaks := k.Break(items, func(int) fixed.Int26_6 { return fixed.I(40) })
+	if len(breaks) < 2 {
+		t.Fatalf("expected the paragraph to wrap across multiple lines, got %v", breaks)
+	}
+	if last := breaks[len(breaks)-1]; last != len(items)-1 {
+		t.Fatalf("expected the final break to be the trailing forced penalty, got index %d of %d items", last, len(items))
+	}
+	for _, b := range breaks {
+		if !isLegalBreak(items, b) && items[b].PenaltyCost > PenaltyForced {
+			t.Errorf("break at %d is not a legal candidate", b)
+		}
+	}
+}
+
+func TestKnuthPlassBreakerNoGlueIsInfeasible(t *testing.T) {
+	items := []BreakItem{
+		{Kind: BreakBox, Width: fixed.I(1000)},
+		{Kind: BreakPenalty, PenaltyCost: PenaltyForced},
+	}
+	var k KnuthPlassBreaker
+	breaks := k.Break(items, func(int) fixed.Int26_6 { return fixed.I(10) })
+	if len(breaks) != 1 || breaks[0] != 1 {
+		t.Fatalf("expected only the forced break to be reachable, got %v", breaks)
+	}
+}
@@ -0,0 +1,315 @@
+// SPDX-License-Identifier: Unlicense OR MIT
+
+package widget
+
+import (
+	"image"
+	"image/color"
+	"strings"
+
+	"gioui.org/layout"
+	"gioui.org/text"
+	"gioui.org/unit"
+)
+
+// MarkdownTheme carries the fonts, sizes, colors and list marker used to
+// render a Markdown. Fields are plain values rather than a pointer to a
+// shared theme, matching the rest of this package's widgets: callers that
+// want a material look should derive a MarkdownTheme from their
+// material.Theme's palette once, rather than threading the theme through
+// on every frame.
+type MarkdownTheme struct {
+	Regular, Bold, Italic, Monospace text.Font
+	// HeadingSize holds the font size for h1 (index 0) through h6 (index 5).
+	HeadingSize [6]unit.Sp
+	BodySize    unit.Sp
+	CodeSize    unit.Sp
+
+	TextColor  color.NRGBA
+	LinkColor  color.NRGBA
+	CodeColor  color.NRGBA
+	QuoteColor color.NRGBA
+
+	CodeBackground color.NRGBA
+	// QuoteBar is not painted by Markdown.Layout; blockquotes are drawn
+	// in QuoteColor only.
+	QuoteBar color.NRGBA
+
+	// ListMarker precedes each list item, e.g. "•".
+	ListMarker string
+	// BlockSpacing is the vertical gap left between blocks.
+	BlockSpacing unit.Dp
+}
+
+// LightMarkdownTheme returns a MarkdownTheme with dark text on a light
+// background, using the built-in Go font family.
+func LightMarkdownTheme() MarkdownTheme {
+	return MarkdownTheme{
+		Regular:        text.Font{},
+		Bold:           text.Font{Weight: text.Bold},
+		Italic:         text.Font{Style: text.Italic},
+		Monospace:      text.Font{Variant: "Mono"},
+		HeadingSize:    [6]unit.Sp{28, 24, 20, 18, 16, 14},
+		BodySize:       14,
+		CodeSize:       13,
+		TextColor:      color.NRGBA{A: 0xff},
+		LinkColor:      color.NRGBA{B: 0xee, A: 0xff},
+		CodeColor:      color.NRGBA{R: 0xa0, A: 0xff},
+		QuoteColor:     color.NRGBA{R: 0x60, G: 0x60, B: 0x60, A: 0xff},
+		CodeBackground: color.NRGBA{R: 0xf0, G: 0xf0, B: 0xf0, A: 0xff},
+		QuoteBar:       color.NRGBA{R: 0xc0, G: 0xc0, B: 0xc0, A: 0xff},
+		ListMarker:     "•",
+		BlockSpacing:   8,
+	}
+}
+
+// DarkMarkdownTheme returns a MarkdownTheme with light text on a dark
+// background, otherwise identical to LightMarkdownTheme.
+func DarkMarkdownTheme() MarkdownTheme {
+	th := LightMarkdownTheme()
+	th.TextColor = color.NRGBA{R: 0xee, G: 0xee, B: 0xee, A: 0xff}
+	th.LinkColor = color.NRGBA{R: 0x8a, G: 0xb4, B: 0xff, A: 0xff}
+	th.CodeColor = color.NRGBA{R: 0xff, G: 0xb0, B: 0x80, A: 0xff}
+	th.QuoteColor = color.NRGBA{R: 0xa0, G: 0xa0, B: 0xa0, A: 0xff}
+	th.CodeBackground = color.NRGBA{R: 0x30, G: 0x30, B: 0x30, A: 0xff}
+	th.QuoteBar = color.NRGBA{R: 0x50, G: 0x50, B: 0x50, A: 0xff}
+	return th
+}
+
+// Markdown renders a CommonMark subset — headings, bold/italic, inline
+// code, fenced code blocks, unordered lists, blockquotes and links —
+// through the styled-span pipeline (see StyledLabel), so the result is
+// drawn as native Gio ops rather than composed from child widgets.
+//
+// The supported grammar is deliberately small: no nested lists or
+// blockquotes, no ordered lists, no images, no tables, and no reference
+// links. Each of these can be added as its own block or inline case
+// without changing the overall structure, but a full CommonMark parser
+// is out of scope here.
+type Markdown struct {
+	Source string
+	Theme  MarkdownTheme
+
+	// links holds the Clickables backing this frame's link spans, reused
+	// across frames by source position so that a Clickable's gesture
+	// state survives a re-layout of unchanged text.
+	links map[int]*Clickable
+}
+
+// markdownBlock is one top-level element of the document.
+type markdownBlock struct {
+	kind    blockKind
+	level   int    // heading level, 1-6
+	lang    string // fenced code block info string, unused beyond detection
+	lines   []string
+	spanPos int // byte offset in Source of the block's first line (past a list marker), for link identity
+}
+
+type blockKind int
+
+const (
+	blockParagraph blockKind = iota
+	blockHeading
+	blockCode
+	blockQuote
+	blockListItem
+)
+
+// Layout parses Source and lays out its blocks top to bottom.
+func (m *Markdown) Layout(gtx layout.Context, lt *text.Shaper) layout.Dimensions {
+	if m.links == nil {
+		m.links = map[int]*Clickable{}
+	}
+	blocks := parseMarkdown(m.Source)
+	spacing := gtx.Dp(m.Theme.BlockSpacing)
+
+	children := make([]layout.FlexChild, 0, 2*len(blocks))
+	for i, b := range blocks {
+		if i > 0 {
+			children = append(children, layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				return layout.Dimensions{Size: image.Pt(0, spacing)}
+			}))
+		}
+		children = append(children, layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+			return m.layoutBlock(gtx, lt, b)
+		}))
+	}
+	return layout.Flex{Axis: layout.Vertical}.Layout(gtx, children...)
+}
+
+func (m *Markdown) layoutBlock(gtx layout.Context, lt *text.Shaper, b markdownBlock) layout.Dimensions {
+	th := m.Theme
+	switch b.kind {
+	case blockCode:
+		spans := []TextSpan{{
+			Text:       strings.Join(b.lines, "\n"),
+			Font:       th.Monospace,
+			Size:       th.CodeSize,
+			Color:      th.CodeColor,
+			Background: th.CodeBackground,
+		}}
+		l := StyledLabel{Spans: spans}
+		return l.Layout(gtx, lt)
+	case blockHeading:
+		size := th.BodySize
+		if b.level >= 1 && b.level <= len(th.HeadingSize) {
+			size = th.HeadingSize[b.level-1]
+		}
+		spans := m.inline(strings.Join(b.lines, " "), b.spanPos, th.Bold, size, th.TextColor)
+		l := StyledLabel{Spans: spans}
+		return l.Layout(gtx, lt)
+	case blockQuote:
+		spans := m.inline(strings.Join(b.lines, " "), b.spanPos, th.Regular, th.BodySize, th.QuoteColor)
+		l := StyledLabel{Spans: spans}
+		return l.Layout(gtx, lt)
+	case blockListItem:
+		spans := m.inline(strings.Join(b.lines, " "), b.spanPos, th.Regular, th.BodySize, th.TextColor)
+		marker := []TextSpan{
+			{Text: th.ListMarker + " ", Font: th.Regular, Size: th.BodySize, Color: th.TextColor},
+		}
+		l := StyledLabel{Spans: append(marker, spans...)}
+		return l.Layout(gtx, lt)
+	default:
+		spans := m.inline(strings.Join(b.lines, " "), b.spanPos, th.Regular, th.BodySize, th.TextColor)
+		l := StyledLabel{Spans: spans}
+		return l.Layout(gtx, lt)
+	}
+}
+
+// inline parses bold, italic, inline code and link syntax within a single
+// block's text, producing spans with the given default font, size and
+// color. pos is the block's spanPos; pos plus a link's byte index in src
+// is the stable key for reusing that link's Clickable across frames.
+func (m *Markdown) inline(src string, pos int, font text.Font, size unit.Sp, col color.NRGBA) []TextSpan {
+	th := m.Theme
+	var spans []TextSpan
+	plain := func(s string) {
+		if s != "" {
+			spans = append(spans, TextSpan{Text: s, Font: font, Size: size, Color: col})
+		}
+	}
+	i := 0
+	for i < len(src) {
+		switch {
+		case strings.HasPrefix(src[i:], "**"):
+			if end := strings.Index(src[i+2:], "**"); end >= 0 {
+				spans = append(spans, TextSpan{Text: src[i+2 : i+2+end], Font: th.Bold, Size: size, Color: col})
+				i += 2 + end + 2
+				continue
+			}
+		case src[i] == '*' || src[i] == '_':
+			delim := src[i]
+			if end := strings.IndexByte(src[i+1:], delim); end >= 0 {
+				spans = append(spans, TextSpan{Text: src[i+1 : i+1+end], Font: th.Italic, Size: size, Color: col})
+				i += 1 + end + 1
+				continue
+			}
+		case src[i] == '`':
+			if end := strings.IndexByte(src[i+1:], '`'); end >= 0 {
+				spans = append(spans, TextSpan{
+					Text:       src[i+1 : i+1+end],
+					Font:       th.Monospace,
+					Size:       th.CodeSize,
+					Color:      th.CodeColor,
+					Background: th.CodeBackground,
+				})
+				i += 1 + end + 1
+				continue
+			}
+		case src[i] == '[':
+			if close := strings.IndexByte(src[i:], ']'); close >= 0 && i+close+1 < len(src) && src[i+close+1] == '(' {
+				if paren := strings.IndexByte(src[i+close+1:], ')'); paren >= 0 {
+					linkText := src[i+1 : i+close]
+					key := pos + i
+					link, ok := m.links[key]
+					if !ok {
+						link = new(Clickable)
+						m.links[key] = link
+					}
+					spans = append(spans, TextSpan{
+						Text:      linkText,
+						Font:      font,
+						Size:      size,
+						Color:     th.LinkColor,
+						Underline: true,
+						Link:      link,
+					})
+					i += close + 1 + paren + 1
+					continue
+				}
+			}
+		}
+		j := i
+		for j < len(src) && src[j] != '*' && src[j] != '_' && src[j] != '`' && src[j] != '[' {
+			j++
+		}
+		if j == i {
+			j++
+		}
+		plain(src[i:j])
+		i = j
+	}
+	return spans
+}
+
+// parseMarkdown splits src into top-level blocks: headings, fenced code
+// blocks, blockquotes, unordered list items and paragraphs, separated by
+// blank lines.
+func parseMarkdown(src string) []markdownBlock {
+	var blocks []markdownBlock
+	lines := strings.Split(src, "\n")
+	pos := 0
+	lineStart := make([]int, len(lines))
+	for i, l := range lines {
+		lineStart[i] = pos
+		pos += len(l) + 1
+	}
+
+	var para []string
+	var paraPos int
+	flush := func() {
+		if len(para) > 0 {
+			blocks = append(blocks, markdownBlock{kind: blockParagraph, lines: para, spanPos: paraPos})
+		}
+		para = nil
+	}
+
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case trimmed == "":
+			flush()
+		case strings.HasPrefix(trimmed, "```"):
+			flush()
+			lang := strings.TrimPrefix(trimmed, "```")
+			var body []string
+			start := i + 1
+			for i++; i < len(lines) && !strings.HasPrefix(strings.TrimSpace(lines[i]), "```"); i++ {
+				body = append(body, lines[i])
+			}
+			blocks = append(blocks, markdownBlock{kind: blockCode, lang: lang, lines: body, spanPos: lineStart[start]})
+		case strings.HasPrefix(trimmed, "#"):
+			flush()
+			level := 0
+			for level < len(trimmed) && trimmed[level] == '#' {
+				level++
+			}
+			text := strings.TrimSpace(trimmed[level:])
+			blocks = append(blocks, markdownBlock{kind: blockHeading, level: level, lines: []string{text}, spanPos: lineStart[i]})
+		case strings.HasPrefix(trimmed, ">"):
+			flush()
+			blocks = append(blocks, markdownBlock{kind: blockQuote, lines: []string{strings.TrimSpace(trimmed[1:])}, spanPos: lineStart[i]})
+		case strings.HasPrefix(trimmed, "- ") || strings.HasPrefix(trimmed, "* ") || strings.HasPrefix(trimmed, "+ "):
+			flush()
+			blocks = append(blocks, markdownBlock{kind: blockListItem, lines: []string{trimmed[2:]}, spanPos: lineStart[i] + 2})
+		default:
+			if len(para) == 0 {
+				paraPos = lineStart[i]
+			}
+			para = append(para, trimmed)
+		}
+	}
+	flush()
+	return blocks
+}
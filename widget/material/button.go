@@ -5,9 +5,10 @@ package material
 import (
 	"image"
 	"image/color"
+	"time"
 
 	"gioui.org/f32"
-	"gioui.org/io/pointer"
+	"gioui.org/internal/f32color"
 	"gioui.org/layout"
 	"gioui.org/op"
 	"gioui.org/op/clip"
@@ -17,48 +18,148 @@ import (
 	"gioui.org/widget"
 )
 
+// ButtonStyle is a filled material button: a colored, rounded rectangle
+// surface with centered label text.
 type ButtonStyle struct {
 	Text string
 	// Color is the text color.
-	Color        color.RGBA
+	Color        color.NRGBA
 	Font         text.Font
-	TextSize     unit.Value
-	Background   color.RGBA
-	CornerRadius unit.Value
+	TextSize     unit.Sp
+	Background   color.NRGBA
+	CornerRadius unit.Dp
 	Inset        layout.Inset
-	Button       *widget.Clickable
-	shaper       text.Shaper
+	// Ink controls the ripple animation played from Button's presses.
+	Ink    InkStyle
+	Button *widget.Clickable
+	shaper *text.Shaper
 }
 
+// ButtonLayoutStyle is the background and ripple shared by ButtonStyle,
+// with an arbitrary widget in place of a text label.
 type ButtonLayoutStyle struct {
-	Background   color.RGBA
-	CornerRadius unit.Value
+	Background   color.NRGBA
+	CornerRadius unit.Dp
 	Inset        layout.Inset
-	Button       *widget.Clickable
+	// Ink controls the ripple animation played from Button's presses.
+	Ink InkStyle
+	// StateLayerColor tints the hover and focus overlays. The zero value
+	// disables the overlays.
+	StateLayerColor color.NRGBA
+	Button          *widget.Clickable
 }
 
+// IconButtonStyle is a circular, filled button showing a single icon.
 type IconButtonStyle struct {
-	Background color.RGBA
-	// Color is the icon color.
-	Color color.RGBA
+	Background color.NRGBA
+	// Color is the icon color, also used to tint the hover and focus
+	// overlays.
+	Color color.NRGBA
 	Icon  *widget.Icon
 	// Size is the icon size.
-	Size   unit.Value
+	Size unit.Dp
+	// Ink controls the ripple animation played from Button's presses.
+	Ink    InkStyle
 	Inset  layout.Inset
 	Button *widget.Clickable
 }
 
+// OutlinedButtonStyle is a button stroked in its Color rather than filled,
+// for actions with less emphasis than a ButtonStyle.
+type OutlinedButtonStyle struct {
+	Text string
+	// Color is the text and stroke color.
+	Color        color.NRGBA
+	Font         text.Font
+	TextSize     unit.Sp
+	CornerRadius unit.Dp
+	// StrokeWidth is the width of the outline.
+	StrokeWidth unit.Dp
+	Inset       layout.Inset
+	// Ink controls the ripple animation played from Button's presses.
+	Ink InkStyle
+	// StateLayerColor tints the hover and focus overlays. The zero value
+	// disables the overlays.
+	StateLayerColor color.NRGBA
+	Button          *widget.Clickable
+	shaper          *text.Shaper
+}
+
+// TextButtonStyle is a button with no surface besides its ripple, for the
+// lowest-emphasis actions.
+type TextButtonStyle struct {
+	Text string
+	// Color is the text color.
+	Color        color.NRGBA
+	Font         text.Font
+	TextSize     unit.Sp
+	CornerRadius unit.Dp
+	Inset        layout.Inset
+	// Ink controls the ripple animation played from Button's presses.
+	Ink InkStyle
+	// StateLayerColor tints the hover and focus overlays. The zero value
+	// disables the overlays.
+	StateLayerColor color.NRGBA
+	Button          *widget.Clickable
+	shaper          *text.Shaper
+}
+
+// ElevatedButtonStyle is a ButtonStyle that casts a soft drop-shadow, for
+// actions that need to stand out from a flat background the same color as
+// Background.
+type ElevatedButtonStyle struct {
+	Text string
+	// Color is the text color.
+	Color      color.NRGBA
+	Font       text.Font
+	TextSize   unit.Sp
+	Background color.NRGBA
+	// Shadow is the color of the drop-shadow painted beneath the surface.
+	Shadow       color.NRGBA
+	CornerRadius unit.Dp
+	Inset        layout.Inset
+	// Ink controls the ripple animation played from Button's presses.
+	Ink InkStyle
+	// StateLayerColor tints the hover and focus overlays. The zero value
+	// disables the overlays.
+	StateLayerColor color.NRGBA
+	Button          *widget.Clickable
+	shaper          *text.Shaper
+}
+
+// TonalButtonStyle is a ButtonStyle filled with a desaturated tint of the
+// theme's accent color instead of the accent color itself, for emphasis
+// between a TextButtonStyle and a primary ButtonStyle.
+type TonalButtonStyle struct {
+	Text string
+	// Color is the text color.
+	Color        color.NRGBA
+	Font         text.Font
+	TextSize     unit.Sp
+	Background   color.NRGBA
+	CornerRadius unit.Dp
+	Inset        layout.Inset
+	// Ink controls the ripple animation played from Button's presses.
+	Ink InkStyle
+	// StateLayerColor tints the hover and focus overlays. The zero value
+	// disables the overlays.
+	StateLayerColor color.NRGBA
+	Button          *widget.Clickable
+	shaper          *text.Shaper
+}
+
 func Button(th *Theme, button *widget.Clickable, txt string) ButtonStyle {
 	return ButtonStyle{
 		Text:         txt,
-		Color:        rgb(0xffffff),
+		Color:        th.Palette.ContrastFg,
 		CornerRadius: unit.Dp(4),
-		Background:   th.Color.Primary,
-		TextSize:     th.TextSize.Scale(14.0 / 16.0),
+		Background:   th.Palette.ContrastBg,
+		TextSize:     th.TextSize * 14 / 16,
 		Inset: layout.Inset{
 			Top: unit.Dp(10), Bottom: unit.Dp(10),
 			Left: unit.Dp(12), Right: unit.Dp(12),
 		},
+		Ink:    defaultInk(),
 		Button: button,
 		shaper: th.Shaper,
 	}
@@ -66,74 +167,241 @@ func Button(th *Theme, button *widget.Clickable, txt string) ButtonStyle {
 
 func ButtonLayout(th *Theme, button *widget.Clickable) ButtonLayoutStyle {
 	return ButtonLayoutStyle{
-		Button:       button,
-		Background:   th.Color.Primary,
-		CornerRadius: unit.Dp(4),
-		Inset:        layout.UniformInset(unit.Dp(12)),
+		Button:          button,
+		Background:      th.Palette.ContrastBg,
+		CornerRadius:    unit.Dp(4),
+		Inset:           layout.UniformInset(unit.Dp(12)),
+		Ink:             defaultInk(),
+		StateLayerColor: th.Palette.ContrastFg,
 	}
 }
 
 func IconButton(th *Theme, button *widget.Clickable, icon *widget.Icon) IconButtonStyle {
 	return IconButtonStyle{
-		Background: th.Color.Primary,
-		Color:      th.Color.InvText,
+		Background: th.Palette.ContrastBg,
+		Color:      th.Palette.ContrastFg,
 		Icon:       icon,
 		Size:       unit.Dp(24),
 		Inset:      layout.UniformInset(unit.Dp(12)),
+		Ink:        defaultInk(),
 		Button:     button,
 	}
 }
 
+// OutlinedButton returns a button outlined in the theme's accent color,
+// for secondary actions that shouldn't compete visually with a ButtonStyle.
+func OutlinedButton(th *Theme, button *widget.Clickable, txt string) OutlinedButtonStyle {
+	return OutlinedButtonStyle{
+		Text:         txt,
+		Color:        th.Palette.ContrastBg,
+		CornerRadius: unit.Dp(4),
+		StrokeWidth:  unit.Dp(1),
+		TextSize:     th.TextSize * 14 / 16,
+		Inset: layout.Inset{
+			Top: unit.Dp(10), Bottom: unit.Dp(10),
+			Left: unit.Dp(12), Right: unit.Dp(12),
+		},
+		Ink:             defaultInk(),
+		StateLayerColor: th.Palette.ContrastBg,
+		Button:          button,
+		shaper:          th.Shaper,
+	}
+}
+
+// TextButton returns a button with no surface besides its ripple.
+func TextButton(th *Theme, button *widget.Clickable, txt string) TextButtonStyle {
+	return TextButtonStyle{
+		Text:         txt,
+		Color:        th.Palette.ContrastBg,
+		CornerRadius: unit.Dp(4),
+		TextSize:     th.TextSize * 14 / 16,
+		Inset: layout.Inset{
+			Top: unit.Dp(10), Bottom: unit.Dp(10),
+			Left: unit.Dp(12), Right: unit.Dp(12),
+		},
+		Ink:             defaultInk(),
+		StateLayerColor: th.Palette.ContrastBg,
+		Button:          button,
+		shaper:          th.Shaper,
+	}
+}
+
+// ElevatedButton returns a ButtonStyle that casts a soft shadow, for
+// actions that need to stand out against a background of the same color
+// as Background.
+func ElevatedButton(th *Theme, button *widget.Clickable, txt string) ElevatedButtonStyle {
+	return ElevatedButtonStyle{
+		Text:         txt,
+		Color:        th.Palette.Fg,
+		Background:   th.Palette.Bg,
+		Shadow:       argb(0x55000000),
+		CornerRadius: unit.Dp(4),
+		TextSize:     th.TextSize * 14 / 16,
+		Inset: layout.Inset{
+			Top: unit.Dp(10), Bottom: unit.Dp(10),
+			Left: unit.Dp(12), Right: unit.Dp(12),
+		},
+		Ink:             defaultInk(),
+		StateLayerColor: th.Palette.Fg,
+		Button:          button,
+		shaper:          th.Shaper,
+	}
+}
+
+// TonalButton returns a ButtonStyle filled with a light tint of the
+// theme's accent color instead of the accent color itself.
+func TonalButton(th *Theme, button *widget.Clickable, txt string) TonalButtonStyle {
+	return TonalButtonStyle{
+		Text:         txt,
+		Color:        th.Palette.ContrastBg,
+		Background:   f32color.MulAlpha(th.Palette.ContrastBg, 0x33),
+		CornerRadius: unit.Dp(4),
+		TextSize:     th.TextSize * 14 / 16,
+		Inset: layout.Inset{
+			Top: unit.Dp(10), Bottom: unit.Dp(10),
+			Left: unit.Dp(12), Right: unit.Dp(12),
+		},
+		Ink:             defaultInk(),
+		StateLayerColor: th.Palette.ContrastBg,
+		Button:          button,
+		shaper:          th.Shaper,
+	}
+}
+
 // Clickable lays out a rectangular clickable widget without further
 // decoration.
 func Clickable(gtx layout.Context, button *widget.Clickable, w layout.Widget) layout.Dimensions {
 	return layout.Stack{}.Layout(gtx,
-		layout.Expanded(button.Layout),
-		layout.Expanded(func(gtx layout.Context) layout.Dimensions {
-			clip.Rect{
-				Rect: f32.Rectangle{Max: f32.Point{
-					X: float32(gtx.Constraints.Min.X),
-					Y: float32(gtx.Constraints.Min.Y),
-				}},
-			}.Op(gtx.Ops).Add(gtx.Ops)
+		layout.Expanded(func(gtx layout.Context) layout.Dimensions {
+			return button.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+				return layout.Dimensions{Size: gtx.Constraints.Min}
+			})
+		}),
+		layout.Expanded(func(gtx layout.Context) layout.Dimensions {
+			cl := clip.Rect(image.Rectangle{Max: gtx.Constraints.Min}).Push(gtx.Ops)
+			ink := defaultInk()
 			for _, c := range button.History() {
-				drawInk(gtx, c)
+				drawInk(gtx, c, ink)
 			}
+			cl.Pop()
 			return layout.Dimensions{Size: gtx.Constraints.Min}
 		}),
 		layout.Stacked(w),
 	)
 }
 
+// colorMaterial records a ColorOp for use as a widget.Label's paint
+// material, so a label can be painted in a color independent of whatever
+// paint state is ambient at its call site.
+func colorMaterial(gtx layout.Context, c color.NRGBA) op.CallOp {
+	m := op.Record(gtx.Ops)
+	paint.ColorOp{Color: c}.Add(gtx.Ops)
+	return m.Stop()
+}
+
+// buttonSurface paints the area shared by the filled, outlined, elevated
+// and tonal button styles: an optional drop-shadow, an optional fill, the
+// ripple ink from History (fill and ink clipped to the same rounded
+// rectangle), and an optional stroked outline.
+type buttonSurface struct {
+	CornerRadius unit.Dp
+	// Background is the fill color. The zero value paints no fill.
+	Background color.NRGBA
+	// Shadow is the drop-shadow color painted beneath the surface. The
+	// zero value paints no shadow.
+	Shadow color.NRGBA
+	// StrokeWidth and StrokeColor describe an outline traced just inside
+	// the rounded rectangle. A zero StrokeWidth paints no outline.
+	StrokeWidth unit.Dp
+	StrokeColor color.NRGBA
+	// Ink controls the ripple animation drawn from btn's press History.
+	// The zero value uses defaultInk.
+	Ink InkStyle
+	// StateLayerColor tints the hover and focus state-layer overlays
+	// painted over the surface. The zero value disables the overlays.
+	StateLayerColor color.NRGBA
+}
+
+// hoverAlpha and focusAlpha are the Material Design 3 state-layer
+// opacities, 8% and 12% of StateLayerColor respectively.
+const (
+	hoverAlpha = 0x14
+	focusAlpha = 0x1e
+)
+
+func (s buttonSurface) layout(gtx layout.Context, btn *widget.Clickable) layout.Dimensions {
+	rr := float32(gtx.Px(s.CornerRadius))
+	rect := f32.Rectangle{Max: layout.FPt(gtx.Constraints.Min)}
+	if s.Shadow.A > 0 {
+		off := op.Offset(f32.Point{Y: float32(gtx.Px(unit.Dp(2)))}).Push(gtx.Ops)
+		paint.FillShape(gtx.Ops, s.Shadow, clip.UniformRRect(rect, rr).Op(gtx.Ops))
+		off.Pop()
+	}
+	cl := clip.UniformRRect(rect, rr).Push(gtx.Ops)
+	if s.Background.A > 0 {
+		paint.Fill(gtx.Ops, s.Background)
+	}
+	ink := s.Ink
+	if ink == (InkStyle{}) {
+		ink = defaultInk()
+	}
+	for _, c := range btn.History() {
+		drawInk(gtx, c, ink)
+	}
+	if s.StateLayerColor.A > 0 {
+		switch {
+		case btn.Focused(gtx):
+			paint.Fill(gtx.Ops, f32color.MulAlpha(s.StateLayerColor, focusAlpha))
+		case btn.Hovered():
+			paint.Fill(gtx.Ops, f32color.MulAlpha(s.StateLayerColor, hoverAlpha))
+		}
+	}
+	cl.Pop()
+	if s.StrokeWidth > 0 {
+		st := clip.Border{
+			Rect:  rect,
+			Width: float32(gtx.Px(s.StrokeWidth)),
+			SE:    rr, SW: rr, NW: rr, NE: rr,
+		}.Op(gtx.Ops).Push(gtx.Ops)
+		paint.Fill(gtx.Ops, s.StrokeColor)
+		st.Pop()
+	}
+	return layout.Dimensions{Size: gtx.Constraints.Min}
+}
+
+// clickArea lays out btn over the current constraints without drawing
+// anything, establishing the hit-test and event area for a surface drawn
+// by an Expanded sibling.
+func clickArea(gtx layout.Context, btn *widget.Clickable) layout.Dimensions {
+	return btn.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+		return layout.Dimensions{Size: gtx.Constraints.Min}
+	})
+}
+
 func (b ButtonStyle) Layout(gtx layout.Context) layout.Dimensions {
 	return ButtonLayoutStyle{
-		Background:   b.Background,
-		CornerRadius: b.CornerRadius,
-		Inset:        b.Inset,
-		Button:       b.Button,
+		Background:      b.Background,
+		CornerRadius:    b.CornerRadius,
+		Inset:           b.Inset,
+		Ink:             b.Ink,
+		StateLayerColor: b.Color,
+		Button:          b.Button,
 	}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
-		paint.ColorOp{Color: b.Color}.Add(gtx.Ops)
-		return widget.Label{Alignment: text.Middle}.Layout(gtx, b.shaper, b.Font, b.TextSize, b.Text)
+		return widget.Label{Alignment: text.Middle}.Layout(gtx, b.shaper, b.Font, b.TextSize, b.Text, colorMaterial(gtx, b.Color))
 	})
 }
 
 func (b ButtonLayoutStyle) Layout(gtx layout.Context, w layout.Widget) layout.Dimensions {
 	min := gtx.Constraints.Min
+	surface := buttonSurface{
+		CornerRadius:    b.CornerRadius,
+		Background:      b.Background,
+		Ink:             b.Ink,
+		StateLayerColor: b.StateLayerColor,
+	}
 	return layout.Stack{Alignment: layout.Center}.Layout(gtx,
 		layout.Expanded(func(gtx layout.Context) layout.Dimensions {
-			rr := float32(gtx.Px(b.CornerRadius))
-			clip.Rect{
-				Rect: f32.Rectangle{Max: f32.Point{
-					X: float32(gtx.Constraints.Min.X),
-					Y: float32(gtx.Constraints.Min.Y),
-				}},
-				NE: rr, NW: rr, SE: rr, SW: rr,
-			}.Op(gtx.Ops).Add(gtx.Ops)
-			dims := fill(gtx, b.Background)
-			for _, c := range b.Button.History() {
-				drawInk(gtx, c)
-			}
-			return dims
+			return surface.layout(gtx, b.Button)
 		}),
 		layout.Stacked(func(gtx layout.Context) layout.Dimensions {
 			gtx.Constraints.Min = min
@@ -141,7 +409,9 @@ func (b ButtonLayoutStyle) Layout(gtx layout.Context, w layout.Widget) layout.Di
 				return b.Inset.Layout(gtx, w)
 			})
 		}),
-		layout.Expanded(b.Button.Layout),
+		layout.Expanded(func(gtx layout.Context) layout.Dimensions {
+			return clickArea(gtx, b.Button)
+		}),
 	)
 }
 
@@ -151,15 +421,23 @@ func (b IconButtonStyle) Layout(gtx layout.Context) layout.Dimensions {
 			size := gtx.Constraints.Min.X
 			sizef := float32(size)
 			rr := sizef * .5
-			clip.Rect{
-				Rect: f32.Rectangle{Max: f32.Point{X: sizef, Y: sizef}},
-				NE:   rr, NW: rr, SE: rr, SW: rr,
-			}.Op(gtx.Ops).Add(gtx.Ops)
-			dims := fill(gtx, b.Background)
+			cl := clip.UniformRRect(f32.Rectangle{Max: f32.Point{X: sizef, Y: sizef}}, rr).Push(gtx.Ops)
+			paint.Fill(gtx.Ops, b.Background)
+			ink := b.Ink
+			if ink == (InkStyle{}) {
+				ink = defaultInk()
+			}
 			for _, c := range b.Button.History() {
-				drawInk(gtx, c)
+				drawInk(gtx, c, ink)
 			}
-			return dims
+			switch {
+			case b.Button.Focused(gtx):
+				paint.Fill(gtx.Ops, f32color.MulAlpha(b.Color, focusAlpha))
+			case b.Button.Hovered():
+				paint.Fill(gtx.Ops, f32color.MulAlpha(b.Color, hoverAlpha))
+			}
+			cl.Pop()
+			return layout.Dimensions{Size: image.Pt(size, size)}
 		}),
 		layout.Stacked(func(gtx layout.Context) layout.Dimensions {
 			return b.Inset.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
@@ -174,39 +452,380 @@ func (b IconButtonStyle) Layout(gtx layout.Context) layout.Dimensions {
 			})
 		}),
 		layout.Expanded(func(gtx layout.Context) layout.Dimensions {
-			pointer.Ellipse(image.Rectangle{Max: gtx.Constraints.Min}).Add(gtx.Ops)
-			return b.Button.Layout(gtx)
+			return clickArea(gtx, b.Button)
+		}),
+	)
+}
+
+func (b OutlinedButtonStyle) Layout(gtx layout.Context) layout.Dimensions {
+	min := gtx.Constraints.Min
+	surface := buttonSurface{
+		CornerRadius:    b.CornerRadius,
+		StrokeWidth:     b.StrokeWidth,
+		StrokeColor:     b.Color,
+		Ink:             b.Ink,
+		StateLayerColor: b.StateLayerColor,
+	}
+	return layout.Stack{Alignment: layout.Center}.Layout(gtx,
+		layout.Expanded(func(gtx layout.Context) layout.Dimensions {
+			return surface.layout(gtx, b.Button)
+		}),
+		layout.Stacked(func(gtx layout.Context) layout.Dimensions {
+			gtx.Constraints.Min = min
+			return layout.Center.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+				return b.Inset.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+					return widget.Label{Alignment: text.Middle}.Layout(gtx, b.shaper, b.Font, b.TextSize, b.Text, colorMaterial(gtx, b.Color))
+				})
+			})
+		}),
+		layout.Expanded(func(gtx layout.Context) layout.Dimensions {
+			return clickArea(gtx, b.Button)
+		}),
+	)
+}
+
+func (b TextButtonStyle) Layout(gtx layout.Context) layout.Dimensions {
+	min := gtx.Constraints.Min
+	surface := buttonSurface{
+		CornerRadius:    b.CornerRadius,
+		Ink:             b.Ink,
+		StateLayerColor: b.StateLayerColor,
+	}
+	return layout.Stack{Alignment: layout.Center}.Layout(gtx,
+		layout.Expanded(func(gtx layout.Context) layout.Dimensions {
+			return surface.layout(gtx, b.Button)
+		}),
+		layout.Stacked(func(gtx layout.Context) layout.Dimensions {
+			gtx.Constraints.Min = min
+			return layout.Center.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+				return b.Inset.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+					return widget.Label{Alignment: text.Middle}.Layout(gtx, b.shaper, b.Font, b.TextSize, b.Text, colorMaterial(gtx, b.Color))
+				})
+			})
+		}),
+		layout.Expanded(func(gtx layout.Context) layout.Dimensions {
+			return clickArea(gtx, b.Button)
+		}),
+	)
+}
+
+func (b ElevatedButtonStyle) Layout(gtx layout.Context) layout.Dimensions {
+	min := gtx.Constraints.Min
+	surface := buttonSurface{
+		CornerRadius:    b.CornerRadius,
+		Background:      b.Background,
+		Shadow:          b.Shadow,
+		Ink:             b.Ink,
+		StateLayerColor: b.StateLayerColor,
+	}
+	return layout.Stack{Alignment: layout.Center}.Layout(gtx,
+		layout.Expanded(func(gtx layout.Context) layout.Dimensions {
+			return surface.layout(gtx, b.Button)
+		}),
+		layout.Stacked(func(gtx layout.Context) layout.Dimensions {
+			gtx.Constraints.Min = min
+			return layout.Center.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+				return b.Inset.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+					return widget.Label{Alignment: text.Middle}.Layout(gtx, b.shaper, b.Font, b.TextSize, b.Text, colorMaterial(gtx, b.Color))
+				})
+			})
+		}),
+		layout.Expanded(func(gtx layout.Context) layout.Dimensions {
+			return clickArea(gtx, b.Button)
+		}),
+	)
+}
+
+func (b TonalButtonStyle) Layout(gtx layout.Context) layout.Dimensions {
+	min := gtx.Constraints.Min
+	surface := buttonSurface{
+		CornerRadius:    b.CornerRadius,
+		Background:      b.Background,
+		Ink:             b.Ink,
+		StateLayerColor: b.StateLayerColor,
+	}
+	return layout.Stack{Alignment: layout.Center}.Layout(gtx,
+		layout.Expanded(func(gtx layout.Context) layout.Dimensions {
+			return surface.layout(gtx, b.Button)
+		}),
+		layout.Stacked(func(gtx layout.Context) layout.Dimensions {
+			gtx.Constraints.Min = min
+			return layout.Center.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+				return b.Inset.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+					return widget.Label{Alignment: text.Middle}.Layout(gtx, b.shaper, b.Font, b.TextSize, b.Text, colorMaterial(gtx, b.Color))
+				})
+			})
+		}),
+		layout.Expanded(func(gtx layout.Context) layout.Dimensions {
+			return clickArea(gtx, b.Button)
+		}),
+	)
+}
+
+// CardStyle is a rounded, optionally elevated container that clips its
+// content and forwards clicks and ripple ink to Button.
+type CardStyle struct {
+	Background color.NRGBA
+	// Shadow is the color of the drop-shadow painted beneath the card. The
+	// zero value paints no shadow, for a flat, unelevated card.
+	Shadow       color.NRGBA
+	CornerRadius unit.Dp
+	Inset        layout.Inset
+	// ImageOp, if non-zero, is painted by ListItemStyle as its leading
+	// avatar, cropped to a circle, in place of Leading. CardStyle.Layout
+	// does not paint it.
+	ImageOp paint.ImageOp
+	Button  *widget.Clickable
+}
+
+// Card returns a CardStyle with an unelevated, theme-background surface.
+func Card(th *Theme, button *widget.Clickable) CardStyle {
+	return CardStyle{
+		Background:   th.Palette.Bg,
+		CornerRadius: unit.Dp(12),
+		Inset:        layout.UniformInset(unit.Dp(16)),
+		Button:       button,
+	}
+}
+
+func (c CardStyle) Layout(gtx layout.Context, w layout.Widget) layout.Dimensions {
+	min := gtx.Constraints.Min
+	surface := buttonSurface{
+		CornerRadius: c.CornerRadius,
+		Background:   c.Background,
+		Shadow:       c.Shadow,
+	}
+	return layout.Stack{Alignment: layout.Center}.Layout(gtx,
+		layout.Expanded(func(gtx layout.Context) layout.Dimensions {
+			return surface.layout(gtx, c.Button)
+		}),
+		layout.Stacked(func(gtx layout.Context) layout.Dimensions {
+			gtx.Constraints.Min = min
+			m := op.Record(gtx.Ops)
+			dims := layout.Center.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+				return c.Inset.Layout(gtx, w)
+			})
+			call := m.Stop()
+			rr := float32(gtx.Px(c.CornerRadius))
+			defer clip.UniformRRect(f32.Rectangle{Max: layout.FPt(dims.Size)}, rr).Push(gtx.Ops).Pop()
+			call.Add(gtx.Ops)
+			return dims
+		}),
+		layout.Expanded(func(gtx layout.Context) layout.Dimensions {
+			return clickArea(gtx, c.Button)
+		}),
+	)
+}
+
+// ListItemStyle is a Card arranging a fixed-size leading widget, a flexed
+// vertical stack of headline and supporting labels, and a set of trailing
+// widgets in a horizontal row, for use as a row in a scrollable list.
+type ListItemStyle struct {
+	Card CardStyle
+	// LeadingSize is the width and height of the leading box.
+	LeadingSize                             unit.Dp
+	Leading, Headline, Supporting, Trailing layout.Widget
+}
+
+// ListItem returns a ListItemStyle composing leading, headline, supporting
+// and trailing widgets into a single clickable row.
+func ListItem(th *Theme, button *widget.Clickable, leading, headline, supporting, trailing layout.Widget) ListItemStyle {
+	card := Card(th, button)
+	card.CornerRadius = 0
+	card.Inset = layout.Inset{
+		Top: unit.Dp(8), Bottom: unit.Dp(8),
+		Left: unit.Dp(16), Right: unit.Dp(16),
+	}
+	return ListItemStyle{
+		Card:        card,
+		LeadingSize: unit.Dp(40),
+		Leading:     leading,
+		Headline:    headline,
+		Supporting:  supporting,
+		Trailing:    trailing,
+	}
+}
+
+func (l ListItemStyle) Layout(gtx layout.Context) layout.Dimensions {
+	return l.Card.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+		return layout.Flex{Axis: layout.Horizontal, Alignment: layout.Middle, Spacing: layout.SpaceBetween}.Layout(gtx,
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				return l.layoutLeading(gtx)
+			}),
+			layout.Flexed(1, func(gtx layout.Context) layout.Dimensions {
+				return layout.Inset{Left: unit.Dp(16), Right: unit.Dp(16)}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+					return layout.Flex{Axis: layout.Vertical}.Layout(gtx,
+						layout.Rigid(l.Headline),
+						layout.Rigid(l.Supporting),
+					)
+				})
+			}),
+			layout.Rigid(l.Trailing),
+		)
+	})
+}
+
+// layoutLeading reserves a LeadingSize square, painting the card's ImageOp
+// cropped to a circle when set, or l.Leading otherwise.
+func (l ListItemStyle) layoutLeading(gtx layout.Context) layout.Dimensions {
+	size := gtx.Px(l.LeadingSize)
+	gtx.Constraints = layout.Exact(image.Pt(size, size))
+	if l.Card.ImageOp == (paint.ImageOp{}) {
+		if l.Leading == nil {
+			return layout.Dimensions{Size: gtx.Constraints.Min}
+		}
+		return l.Leading(gtx)
+	}
+	rr := float32(size) * .5
+	defer clip.UniformRRect(f32.Rectangle{Max: layout.FPt(gtx.Constraints.Min)}, rr).Push(gtx.Ops).Pop()
+	return widget.Image{Src: l.Card.ImageOp, Fit: widget.Cover, Position: layout.Center}.Layout(gtx)
+}
+
+// ImageButtonStyle is a circular, filled button showing an arbitrary raster
+// image in place of IconButtonStyle's vector icon. The image is scaled to
+// fit the button while preserving its aspect ratio, with any letterboxed
+// area painted with Background, then the whole button is cropped to a
+// circle.
+type ImageButtonStyle struct {
+	Background color.NRGBA
+	Image      paint.ImageOp
+	Size       unit.Dp
+	Ink        InkStyle
+	// StateLayerColor tints the hover and focus overlays. The zero value
+	// disables the overlays.
+	StateLayerColor color.NRGBA
+	Inset           layout.Inset
+	// RingWidth and RingColor, if RingWidth is non-zero, stroke a ring
+	// just inside the circle's edge. AvatarButton sets these.
+	RingWidth unit.Dp
+	RingColor color.NRGBA
+	Button    *widget.Clickable
+}
+
+// ImageButton returns an ImageButtonStyle rendering img inside a plain
+// circular button.
+func ImageButton(th *Theme, button *widget.Clickable, img paint.ImageOp) ImageButtonStyle {
+	return ImageButtonStyle{
+		Background:      th.Palette.ContrastBg,
+		Image:           img,
+		Size:            unit.Dp(24),
+		Ink:             defaultInk(),
+		StateLayerColor: th.Palette.ContrastFg,
+		Button:          button,
+	}
+}
+
+// AvatarButton returns an ImageButtonStyle sized and ringed for use as a
+// tappable user avatar.
+func AvatarButton(th *Theme, button *widget.Clickable, img paint.ImageOp) ImageButtonStyle {
+	b := ImageButton(th, button, img)
+	b.Size = unit.Dp(40)
+	b.RingWidth = unit.Dp(1)
+	b.RingColor = th.Palette.ContrastBg
+	return b
+}
+
+func (b ImageButtonStyle) Layout(gtx layout.Context) layout.Dimensions {
+	return layout.Stack{Alignment: layout.Center}.Layout(gtx,
+		layout.Expanded(func(gtx layout.Context) layout.Dimensions {
+			size := gtx.Constraints.Min.X
+			sizef := float32(size)
+			rr := sizef * .5
+			cl := clip.UniformRRect(f32.Rectangle{Max: f32.Point{X: sizef, Y: sizef}}, rr).Push(gtx.Ops)
+			paint.Fill(gtx.Ops, b.Background)
+			ink := b.Ink
+			if ink == (InkStyle{}) {
+				ink = defaultInk()
+			}
+			for _, c := range b.Button.History() {
+				drawInk(gtx, c, ink)
+			}
+			if b.StateLayerColor.A > 0 {
+				switch {
+				case b.Button.Focused(gtx):
+					paint.Fill(gtx.Ops, f32color.MulAlpha(b.StateLayerColor, focusAlpha))
+				case b.Button.Hovered():
+					paint.Fill(gtx.Ops, f32color.MulAlpha(b.StateLayerColor, hoverAlpha))
+				}
+			}
+			cl.Pop()
+			if b.RingWidth > 0 {
+				st := clip.Border{
+					Rect:  f32.Rectangle{Max: f32.Point{X: sizef, Y: sizef}},
+					Width: float32(gtx.Px(b.RingWidth)),
+					SE:    rr, SW: rr, NW: rr, NE: rr,
+				}.Op(gtx.Ops).Push(gtx.Ops)
+				paint.Fill(gtx.Ops, b.RingColor)
+				st.Pop()
+			}
+			return layout.Dimensions{Size: image.Pt(size, size)}
+		}),
+		layout.Stacked(func(gtx layout.Context) layout.Dimensions {
+			return b.Inset.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+				size := gtx.Px(b.Size)
+				sizef := float32(size)
+				gtx.Constraints = layout.Exact(image.Pt(size, size))
+				defer clip.UniformRRect(f32.Rectangle{Max: f32.Point{X: sizef, Y: sizef}}, sizef*.5).Push(gtx.Ops).Pop()
+				widget.Image{Src: b.Image, Fit: widget.Contain, Position: layout.Center}.Layout(gtx)
+				return layout.Dimensions{Size: image.Pt(size, size)}
+			})
+		}),
+		layout.Expanded(func(gtx layout.Context) layout.Dimensions {
+			return clickArea(gtx, b.Button)
 		}),
 	)
 }
 
-func drawInk(gtx layout.Context, c widget.Press) {
-	d := gtx.Now().Sub(c.Time)
+// InkStyle controls the ripple ink animation drawn for a Clickable's press
+// History.
+type InkStyle struct {
+	// MaxRadius is the disc's width at the end of its animation; its
+	// radius then is MaxRadius/2.
+	MaxRadius unit.Dp
+	// Duration is how long the ripple takes to expand and fade out.
+	Duration time.Duration
+	// Color is the ink's color at full Opacity.
+	Color color.NRGBA
+	// Opacity scales Color.A (and its other channels, to match) at the
+	// start of the animation; it fades to zero over Duration.
+	Opacity float32
+}
+
+// defaultInk reproduces the gray, 700dp, 0.5s ripple material buttons have
+// always used.
+func defaultInk() InkStyle {
+	return InkStyle{
+		MaxRadius: unit.Dp(700),
+		Duration:  500 * time.Millisecond,
+		Color:     color.NRGBA{A: 0xaa, R: 0xaa, G: 0xaa, B: 0xaa},
+		Opacity:   1,
+	}
+}
+
+// drawInk animates a single past press from a widget's History as an
+// expanding, fading disc centered on where the press occurred.
+func drawInk(gtx layout.Context, c widget.Press, ink InkStyle) {
+	d := gtx.Now.Sub(c.Start)
 	t := float32(d.Seconds())
-	const duration = 0.5
+	duration := float32(ink.Duration.Seconds())
+	if duration <= 0 {
+		duration = 0.5
+	}
 	if t > duration {
 		return
 	}
 	t = t / duration
-	var stack op.StackOp
-	stack.Push(gtx.Ops)
-	size := float32(gtx.Px(unit.Dp(700))) * t
+	size := float32(gtx.Px(ink.MaxRadius)) * t
 	rr := size * .5
-	col := byte(0xaa * (1 - t*t))
-	ink := paint.ColorOp{Color: color.RGBA{A: col, R: col, G: col, B: col}}
-	ink.Add(gtx.Ops)
-	op.TransformOp{}.Offset(c.Position).Offset(f32.Point{
-		X: -rr,
-		Y: -rr,
-	}).Add(gtx.Ops)
-	clip.Rect{
-		Rect: f32.Rectangle{Max: f32.Point{
-			X: float32(size),
-			Y: float32(size),
-		}},
-		NE: rr, NW: rr, SE: rr, SW: rr,
-	}.Op(gtx.Ops).Add(gtx.Ops)
-	paint.PaintOp{Rect: f32.Rectangle{Max: f32.Point{X: float32(size), Y: float32(size)}}}.Add(gtx.Ops)
-	stack.Pop()
+	fade := ink.Opacity * (1 - t*t)
+	col := ink.Color
+	col.A = byte(float32(col.A) * fade)
+	col.R = byte(float32(col.R) * fade)
+	col.G = byte(float32(col.G) * fade)
+	col.B = byte(float32(col.B) * fade)
+	pos := layout.FPt(c.Position)
+	off := op.Offset(f32.Point{X: pos.X - rr, Y: pos.Y - rr}).Push(gtx.Ops)
+	paint.FillShape(gtx.Ops, col, clip.UniformRRect(f32.Rectangle{Max: f32.Point{X: size, Y: size}}, rr).Op(gtx.Ops))
+	off.Pop()
 	op.InvalidateOp{}.Add(gtx.Ops)
 }
@@ -17,12 +17,30 @@ import (
 )
 
 type LoaderStyle struct {
+	// Color is the color of the foreground arc.
 	Color color.NRGBA
+	// TrackColor is the color of the background ring drawn behind
+	// the arc. The zero value draws no track.
+	TrackColor color.NRGBA
+	// Progress is the fraction of the ring, in [0;1], that the
+	// foreground arc covers. NaN or a negative value (the default)
+	// renders an indeterminate spinner that sweeps continuously
+	// instead.
+	Progress float32
+	// Thickness is the width of the ring. The zero value defaults to
+	// a quarter of the loader's radius.
+	Thickness unit.Dp
+	// Period is the duration of one indeterminate sweep. The zero
+	// value defaults to one second. Unused in determinate mode.
+	Period time.Duration
+	// Cap is the stroke cap applied to both the arc and the track.
+	Cap clip.StrokeCap
 }
 
 func Loader(th *Theme) LoaderStyle {
 	return LoaderStyle{
-		Color: th.Palette.ContrastBg,
+		Color:    th.Palette.ContrastBg,
+		Progress: float32(math.NaN()),
 	}
 }
 
@@ -32,44 +50,65 @@ func (l LoaderStyle) Layout(gtx layout.Context) layout.Dimensions {
 		diam = minY
 	}
 	if diam == 0 {
-		diam = gtx.Px(unit.Dp(24))
+		diam = gtx.Dp(24)
 	}
 	sz := gtx.Constraints.Constrain(image.Pt(diam, diam))
 	radius := float64(sz.X) * .5
 	defer op.Push(gtx.Ops).Pop()
 	op.Offset(f32.Pt(float32(radius), float32(radius))).Add(gtx.Ops)
 
-	dt := (time.Duration(gtx.Now.UnixNano()) % (time.Second)).Seconds()
-	startAngle := dt * math.Pi * 2
-	endAngle := startAngle + math.Pi*1.5
+	width := float32(radius) * .25
+	if l.Thickness != 0 {
+		width = float32(gtx.Dp(l.Thickness))
+	}
+
+	determinate := !math.IsNaN(float64(l.Progress)) && l.Progress >= 0
+
+	if l.TrackColor != (color.NRGBA{}) {
+		clipArc(gtx.Ops, 0, math.Pi*2, radius, width, l.Cap)
+		paint.ColorOp{Color: l.TrackColor}.Add(gtx.Ops)
+		paint.PaintOp{}.Add(gtx.Ops)
+	}
+
+	var startAngle, endAngle float64
+	if determinate {
+		startAngle = -math.Pi / 2
+		endAngle = startAngle + float64(l.Progress)*math.Pi*2
+	} else {
+		period := l.Period
+		if period == 0 {
+			period = time.Second
+		}
+		dt := (time.Duration(gtx.Now.UnixNano()) % period).Seconds() / period.Seconds()
+		startAngle = dt * math.Pi * 2
+		endAngle = startAngle + math.Pi*1.5
+	}
 
-	clipLoader(gtx.Ops, startAngle, endAngle, radius)
+	clipArc(gtx.Ops, startAngle, endAngle, radius, width, l.Cap)
 	paint.ColorOp{
 		Color: l.Color,
 	}.Add(gtx.Ops)
-	op.Offset(f32.Pt(-float32(radius), -float32(radius))).Add(gtx.Ops)
 	paint.PaintOp{}.Add(gtx.Ops)
-	op.InvalidateOp{}.Add(gtx.Ops)
+
+	if !determinate {
+		// Only an indeterminate spinner needs to keep animating; a
+		// determinate loader is static between Progress updates, so
+		// don't force a redraw every frame.
+		op.InvalidateOp{}.Add(gtx.Ops)
+	}
 	return layout.Dimensions{
 		Size: sz,
 	}
 }
 
-func clipLoader(ops *op.Ops, startAngle, endAngle, radius float64) {
-	const thickness = .25
-
-	var (
-		width = float32(radius * thickness)
-		delta = float32(endAngle - startAngle)
-
-		vy, vx = math.Sincos(startAngle)
-
-		pen    = f32.Pt(float32(vx), float32(vy)).Mul(float32(radius))
-		center = f32.Pt(0, 0).Sub(pen)
+func clipArc(ops *op.Ops, startAngle, endAngle, radius float64, width float32, cap clip.StrokeCap) {
+	delta := float32(endAngle - startAngle)
 
-		p clip.Path
-	)
+	vy, vx := math.Sincos(startAngle)
+	pen := f32.Pt(float32(vx), float32(vy)).Mul(float32(radius))
+	center := f32.Pt(0, 0).Sub(pen)
 
+	var p clip.Path
 	p.Begin(ops)
 	p.Move(pen)
 	p.Arc(center, center, delta)
@@ -77,7 +116,7 @@ func clipLoader(ops *op.Ops, startAngle, endAngle, radius float64) {
 		Path: p.End(),
 		Style: clip.StrokeStyle{
 			Width: width,
-			Cap:   clip.FlatCap,
+			Cap:   cap,
 		},
 	}.Op().Add(ops)
 }
@@ -3,23 +3,101 @@
 package material
 
 import (
+	"bytes"
 	"image"
 	"testing"
 
+	"gioui.org/io/input"
+	"gioui.org/io/key"
 	"gioui.org/layout"
+	"gioui.org/op"
+	"gioui.org/unit"
 	"gioui.org/widget"
 )
 
 func TestButtonLayout(t *testing.T) {
-	var gtx layout.Context
-	gtx.Reset(nil, image.Point{X: 100, Y: 100})
+	gtx := layout.Context{
+		Ops:         new(op.Ops),
+		Constraints: layout.Exact(image.Point{X: 100, Y: 100}),
+	}
 
-	ButtonLayout{}.Layout(&gtx, new(widget.Button), func() {
-		if got, exp := gtx.Constraints.Width.Min, 100; got != exp {
-			t.Errorf("minimum width is %d, expected %d", got, exp)
-		}
-		if got, exp := gtx.Constraints.Height.Min, 100; got != exp {
-			t.Errorf("minimum width is %d, expected %d", got, exp)
-		}
+	var seen layout.Constraints
+	ButtonLayoutStyle{Button: new(widget.Clickable)}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+		seen = gtx.Constraints
+		return layout.Dimensions{Size: gtx.Constraints.Min}
 	})
+	if got, exp := seen.Max.X, 100; got != exp {
+		t.Errorf("maximum width is %d, expected %d", got, exp)
+	}
+	if got, exp := seen.Max.Y, 100; got != exp {
+		t.Errorf("maximum height is %d, expected %d", got, exp)
+	}
+}
+
+func TestListItemLayout(t *testing.T) {
+	gtx := layout.Context{
+		Ops:         new(op.Ops),
+		Constraints: layout.Exact(image.Point{X: 300, Y: 56}),
+	}
+
+	th := NewTheme()
+	item := ListItem(th, new(widget.Clickable),
+		func(gtx layout.Context) layout.Dimensions {
+			return layout.Dimensions{Size: gtx.Constraints.Min}
+		},
+		func(gtx layout.Context) layout.Dimensions {
+			return layout.Dimensions{Size: gtx.Constraints.Min}
+		},
+		func(gtx layout.Context) layout.Dimensions {
+			return layout.Dimensions{Size: gtx.Constraints.Min}
+		},
+		func(gtx layout.Context) layout.Dimensions {
+			return layout.Dimensions{Size: gtx.Constraints.Min}
+		},
+	)
+	dims := item.Layout(gtx)
+	if got, exp := dims.Size.X, 300; got != exp {
+		t.Errorf("width is %d, expected %d", got, exp)
+	}
+}
+
+// TestButtonStateLayer asserts that focusing a button's Clickable paints a
+// state-layer overlay, by comparing the ops recorded with and without focus.
+func TestButtonStateLayer(t *testing.T) {
+	var r input.Router
+	btn := new(widget.Clickable)
+	th := NewTheme()
+	style := ButtonLayoutStyle{
+		Button:          btn,
+		Background:      th.Palette.ContrastBg,
+		CornerRadius:    unit.Dp(4),
+		Ink:             defaultInk(),
+		StateLayerColor: th.Palette.ContrastFg,
+	}
+	gtx := layout.Context{
+		Ops:         new(op.Ops),
+		Constraints: layout.Exact(image.Point{X: 100, Y: 100}),
+		Source:      r.Source(),
+	}
+	recordedSize := func() int {
+		gtx.Ops.Reset()
+		style.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+			return layout.Dimensions{Size: gtx.Constraints.Min}
+		})
+		var buf bytes.Buffer
+		if _, err := gtx.Ops.WriteTo(&buf); err != nil {
+			t.Fatal(err)
+		}
+		r.Frame(gtx.Ops)
+		return buf.Len()
+	}
+
+	unfocused := recordedSize()
+	gtx.Execute(key.FocusCmd{Tag: btn})
+	recordedSize() // let the router process the queued focus command
+	focused := recordedSize()
+
+	if focused <= unfocused {
+		t.Errorf("focused layout recorded %d op bytes, expected more than the unfocused %d", focused, unfocused)
+	}
 }
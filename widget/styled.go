@@ -0,0 +1,213 @@
+// SPDX-License-Identifier: Unlicense OR MIT
+
+package widget
+
+import (
+	"image"
+	"image/color"
+
+	"gioui.org/layout"
+	"gioui.org/op"
+	"gioui.org/op/clip"
+	"gioui.org/op/paint"
+	"gioui.org/text"
+	"gioui.org/unit"
+
+	"golang.org/x/image/math/fixed"
+)
+
+// TextSpan is a run of text sharing a single set of visual attributes. A
+// StyledLabel is built from a sequence of TextSpans, the way ConTeXt
+// layers color, typeface and other attributes onto a run of text rather
+// than requiring a new paragraph per attribute change.
+type TextSpan struct {
+	Text string
+	Font text.Font
+	Size unit.Sp
+	// Color is the paint material for the span's glyphs. The zero value
+	// paints nothing, matching Label's untextured default.
+	Color color.NRGBA
+
+	Underline     bool
+	Strikethrough bool
+	// Background, if non-zero, is filled behind the span's glyphs,
+	// extending the full height of the line it appears on.
+	Background color.NRGBA
+
+	// Link, if set, turns the span into a clickable hit region; see
+	// widget.Clickable.
+	Link *Clickable
+}
+
+// StyledLabel lays out a sequence of TextSpans as a single paragraph,
+// each span keeping its own color, font, size and decorations.
+//
+// Spans are shaped independently, one text.Shaper.LayoutString call per
+// span, rather than as a single shaping run spanning every span. That
+// keeps per-span attributes simple to apply, but it means shaping
+// artifacts that depend on context outside a span — bidi reordering
+// between spans, and ligatures or kerning across a span boundary — do
+// not occur: a span boundary is shaped as if it were a paragraph
+// boundary. Use a single Label instead of StyledLabel for a paragraph
+// that needs no per-run attributes.
+//
+// There is no editable counterpart yet: Editor's selection, caret and
+// IME handling are built around a single Font and Size for the whole
+// document, and threading per-span attributes through that would be a
+// larger change than this type. A StyledLabel-backed Editor mode is left
+// for a follow-up.
+type StyledLabel struct {
+	Spans []TextSpan
+}
+
+// styledRun is one shaped TextSpan, positioned within its line.
+type styledRun struct {
+	span    *TextSpan
+	glyphs  []text.Glyph
+	path    clip.PathSpec
+	x       fixed.Int26_6
+	width   fixed.Int26_6
+	ascent  fixed.Int26_6
+	descent fixed.Int26_6
+}
+
+// Layout the label, greedily wrapping to a new line before any span that
+// would overflow the available width. Spans are not themselves
+// word-wrapped: a span too wide to fit any line is placed on a line by
+// itself rather than broken up.
+func (l StyledLabel) Layout(gtx layout.Context, lt *text.Shaper) layout.Dimensions {
+	cs := gtx.Constraints
+	maxWidth := cs.Max.X
+
+	var lines [][]styledRun
+	var line []styledRun
+	var lineX fixed.Int26_6
+	flush := func() {
+		if len(line) > 0 {
+			lines = append(lines, line)
+		}
+		line = nil
+		lineX = 0
+	}
+	for i := range l.Spans {
+		span := &l.Spans[i]
+		textSize := fixed.I(gtx.Sp(span.Size))
+		lt.LayoutString(text.Parameters{
+			Font:    span.Font,
+			PxPerEm: textSize,
+		}, 0, 0, gtx.Locale, span.Text)
+		var glyphs []text.Glyph
+		var width, ascent, descent fixed.Int26_6
+		for g, ok := lt.NextGlyph(); ok; g, ok = lt.NextGlyph() {
+			glyphs = append(glyphs, g)
+			if end := g.X + g.Advance; end > width {
+				width = end
+			}
+			if g.Ascent > ascent {
+				ascent = g.Ascent
+			}
+			if g.Descent > descent {
+				descent = g.Descent
+			}
+		}
+		if len(line) > 0 && maxWidth > 0 && (lineX+width).Ceil() > maxWidth {
+			flush()
+		}
+		line = append(line, styledRun{
+			span:    span,
+			glyphs:  glyphs,
+			path:    lt.Shape(glyphs),
+			x:       lineX,
+			width:   width,
+			ascent:  ascent,
+			descent: descent,
+		})
+		lineX += width
+	}
+	flush()
+
+	var bounds image.Rectangle
+	var y fixed.Int26_6
+	type placed struct {
+		run *styledRun
+		y   fixed.Int26_6
+		// ascent and descent are the line's, for span backgrounds.
+		ascent, descent fixed.Int26_6
+	}
+	var placements []placed
+	for _, ln := range lines {
+		var lineAscent, lineDescent fixed.Int26_6
+		for i := range ln {
+			if ln[i].ascent > lineAscent {
+				lineAscent = ln[i].ascent
+			}
+			if ln[i].descent > lineDescent {
+				lineDescent = ln[i].descent
+			}
+		}
+		baseline := y + lineAscent
+		for i := range ln {
+			placements = append(placements, placed{run: &ln[i], y: baseline, ascent: lineAscent, descent: lineDescent})
+			right := (ln[i].x + ln[i].width).Ceil()
+			if right > bounds.Max.X {
+				bounds.Max.X = right
+			}
+		}
+		bottom := (baseline + lineDescent).Ceil()
+		if bottom > bounds.Max.Y {
+			bounds.Max.Y = bottom
+		}
+		y = baseline + lineDescent
+	}
+
+	for _, p := range placements {
+		run, baseline := p.run, p.y
+		origin := image.Pt(run.x.Round(), baseline.Round())
+		lineTop := baseline - run.ascent
+		if run.span.Background != (color.NRGBA{}) {
+			rect := image.Rectangle{
+				Min: image.Pt(run.x.Round(), (baseline - p.ascent).Round()),
+				Max: image.Pt((run.x + run.width).Round(), (baseline + p.descent).Round()),
+			}
+			paint.FillShape(gtx.Ops, run.span.Background, clip.Rect(rect).Op())
+		}
+		t := op.Offset(origin).Push(gtx.Ops)
+		outline := clip.Outline{Path: run.path}.Op().Push(gtx.Ops)
+		paint.ColorOp{Color: run.span.Color}.Add(gtx.Ops)
+		paint.PaintOp{}.Add(gtx.Ops)
+		outline.Pop()
+		if call := lt.Bitmaps(run.glyphs); call != (op.CallOp{}) {
+			call.Add(gtx.Ops)
+		}
+		t.Pop()
+		if run.span.Underline {
+			underlineRule(gtx, run, baseline+run.descent/2)
+		}
+		if run.span.Strikethrough {
+			underlineRule(gtx, run, baseline-run.ascent/3)
+		}
+		if run.span.Link != nil {
+			size := image.Pt(run.width.Round(), (run.ascent + run.descent).Round())
+			stack := op.Offset(image.Pt(run.x.Round(), lineTop.Round())).Push(gtx.Ops)
+			run.span.Link.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+				return layout.Dimensions{Size: size}
+			})
+			stack.Pop()
+		}
+	}
+
+	dims := layout.Dimensions{Size: cs.Constrain(bounds.Size())}
+	return dims
+}
+
+// underlineRule paints a one-pixel-thick rule spanning run's width at
+// document y-coordinate y, used for both the underline and strikethrough
+// decorations.
+func underlineRule(gtx layout.Context, run *styledRun, y fixed.Int26_6) {
+	top := y.Round()
+	rect := image.Rectangle{
+		Min: image.Pt(run.x.Round(), top),
+		Max: image.Pt((run.x + run.width).Round(), top+1),
+	}
+	paint.FillShape(gtx.Ops, run.span.Color, clip.Rect(rect).Op())
+}
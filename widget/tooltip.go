@@ -0,0 +1,58 @@
+// SPDX-License-Identifier: Unlicense OR MIT
+
+package widget
+
+import (
+	"image"
+	"time"
+
+	"gioui.org/gesture"
+	"gioui.org/layout"
+	"gioui.org/op"
+	"gioui.org/op/clip"
+)
+
+// Tooltip wraps a widget with a floating overlay that appears once
+// the pointer has dwelled over it for Delay, using gesture.Hover's
+// intent detection. The overlay is positioned with layout.Overlay so
+// it can render outside the wrapped widget's bounds.
+type Tooltip struct {
+	// Delay overrides how long the pointer must dwell before Content
+	// is shown. The zero value uses gesture.Hover's default delay.
+	Delay time.Duration
+	// Content lays out the tooltip body while it is visible.
+	Content layout.Widget
+
+	hover   gesture.Hover
+	visible bool
+	at      image.Point
+}
+
+// Layout w, the wrapped widget, and its tooltip overlay once hover
+// intent fires over it.
+func (t *Tooltip) Layout(gtx layout.Context, w layout.Widget) layout.Dimensions {
+	t.hover.Delay = t.Delay
+	for {
+		e, ok := t.hover.Events(gtx.Metric, gtx.Source, gtx.Now)
+		if !ok {
+			break
+		}
+		switch e.Kind {
+		case gesture.HoverIntent:
+			t.visible = true
+			t.at = e.Position
+		case gesture.HoverEnd:
+			t.visible = false
+		}
+	}
+	m := op.Record(gtx.Ops)
+	dims := w(gtx)
+	c := m.Stop()
+	defer clip.Rect(image.Rectangle{Max: dims.Size}).Push(gtx.Ops).Pop()
+	t.hover.Add(gtx.Ops)
+	c.Add(gtx.Ops)
+	if t.visible && t.Content != nil {
+		layout.Overlay(gtx, t.at, t.Content)
+	}
+	return dims
+}